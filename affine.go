@@ -1,11 +1,19 @@
 package tochka
 
 import (
+	"errors"
 	"math"
 	"strconv"
 	"strings"
 )
 
+// DefaultTolerance is the reciprocal condition number below which Invert treats
+// a transformation as singular.
+const DefaultTolerance = 1e-6
+
+// ErrSingularMatrix is returned when a transformation cannot be inverted.
+var ErrSingularMatrix = errors.New("singular matrix")
+
 // Affine2D represents an affine transformation in a 2D coordinate system.
 // It contains the elements of the transformation matrix that allow performing operations
 // such as translation, scaling, rotation, and shear.
@@ -107,26 +115,40 @@ func (A Affine2D) Mul(B Affine2D) (r Affine2D) {
 }
 
 // Invert computes the inverse transformation for the current matrix.
-// If the matrix is close to singular, the results may be inaccurate.
+// If the matrix is singular or ill-conditioned with respect to DefaultTolerance,
+// the identity transformation is returned; use TryInvert to detect that case.
 func (a Affine2D) Invert() Affine2D {
-	if math.Abs(float64(a.a)) < 1e-6 && math.Abs(float64(a.b)) < 1e-6 &&
-		math.Abs(float64(a.d)) < 1e-6 && math.Abs(float64(a.e)) < 1e-6 {
-		return Affine2D{a: 0, b: 0, c: -a.c, d: 0, e: 0, f: -a.f}
-	}
-	a.a += 1
-	a.e += 1
-	det := a.a*a.e - a.b*a.d
-	if math.Abs(float64(det)) < 1e-6 {
+	inv, err := a.TryInvert(DefaultTolerance)
+	if err != nil {
 		return Affine2D{} // matrix is singular
 	}
-	a.a, a.e = a.e/det, a.a/det
-	a.b, a.d = -a.b/det, -a.d/det
-	temp := a.c
-	a.c = -a.a*a.c - a.b*a.f
-	a.f = -a.d*temp - a.e*a.f
-	a.a -= 1
-	a.e -= 1
-	return a
+	return inv
+}
+
+// TryInvert computes the inverse transformation for the current matrix.
+// tolerance is the smallest accepted reciprocal condition number of the linear part
+// (see Cond); a tolerance of zero only rejects matrices with a zero determinant.
+// Returns ErrSingularMatrix if the matrix cannot be inverted reliably.
+// The computation is carried out in double precision.
+func (a Affine2D) TryInvert(tolerance float32) (Affine2D, error) {
+	inv, err := a.To64().TryInvert(float64(tolerance))
+	if err != nil {
+		return Affine2D{}, err
+	}
+	return inv.To32(), nil
+}
+
+// Determinant returns the determinant of the linear part of the transformation.
+// A negative determinant indicates that the transformation contains a reflection.
+func (a Affine2D) Determinant() float32 {
+	return float32(a.To64().Determinant())
+}
+
+// Cond returns the condition number of the linear part of the transformation,
+// that is the ratio of its largest to its smallest singular value.
+// The result is 1 for rigid and uniformly scaled transformations and +Inf for singular ones.
+func (a Affine2D) Cond() float32 {
+	return float32(a.To64().Cond())
 }
 
 // Transform applies the current transformation to a given point.
@@ -134,8 +156,41 @@ func (a Affine2D) Invert() Affine2D {
 func (a Affine2D) Transform(p Point) Point {
 	return Point{
 		X: p.X*(a.a+1) + p.Y*a.b + a.c,
-		Y: p.Y*a.d + p.Y*(a.e+1) + a.f,
+		Y: p.X*a.d + p.Y*(a.e+1) + a.f,
+	}
+}
+
+// TransformPoints applies the current transformation to every point of src and stores
+// the results in dst, like a loop over Transform without the per-call overhead.
+// It processes min(len(dst), len(src)) points and returns that number.
+// dst and src may be the same slice for in-place operation, but must not otherwise overlap.
+func (a Affine2D) TransformPoints(dst, src []Point) int {
+	n := min(len(dst), len(src))
+	dst, src = dst[:n], src[:n]
+	sx, hx, ox, hy, sy, oy := a.Elems()
+	for k, p := range src {
+		dst[k] = Point{
+			X: p.X*sx + p.Y*hx + ox,
+			Y: p.X*hy + p.Y*sy + oy,
+		}
+	}
+	return n
+}
+
+// TransformXY applies the current transformation to the interleaved x, y coordinate pairs
+// of src, such as a vertex buffer, and stores the results in dst.
+// It processes min(len(dst), len(src))/2 pairs and returns that number.
+// dst and src may be the same slice for in-place operation, but must not otherwise overlap.
+func (a Affine2D) TransformXY(dst, src []float32) int {
+	n := min(len(dst), len(src)) / 2
+	dst, src = dst[:2*n], src[:2*n]
+	sx, hx, ox, hy, sy, oy := a.Elems()
+	for k := 0; k < len(src); k += 2 {
+		x, y := src[k], src[k+1]
+		dst[k] = x*sx + y*hx + ox
+		dst[k+1] = x*hy + y*sy + oy
 	}
+	return n
 }
 
 // Elems returns the elements of the transformation matrix.
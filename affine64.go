@@ -0,0 +1,260 @@
+package tochka
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Affine2D64 represents an affine transformation in a 2D coordinate system with elements
+// stored in double precision. It mirrors the Affine2D API and uses the same offset-from-identity
+// encoding, so the zero value is the identity transformation.
+type Affine2D64 struct {
+	a, b, c float64
+	d, e, f float64
+}
+
+// NewAffine2D64 creates a new double precision affine transformation.
+// sx, hx, ox are elements of the first row of the matrix (scaling along X, shear along X, translation along X).
+// hy, sy, oy are elements of the second row of the matrix (shear along Y, scaling along Y, translation along Y).
+func NewAffine2D64(sx, hx, ox, hy, sy, oy float64) Affine2D64 {
+	return Affine2D64{
+		a: sx - 1, b: hx, c: ox,
+		d: hy, e: sy - 1, f: oy,
+	}
+}
+
+// To64 converts the transformation to double precision. The conversion is lossless.
+func (a Affine2D) To64() Affine2D64 {
+	return Affine2D64{
+		a: float64(a.a), b: float64(a.b), c: float64(a.c),
+		d: float64(a.d), e: float64(a.e), f: float64(a.f),
+	}
+}
+
+// To32 converts the transformation to single precision, rounding each element to the nearest float32.
+func (a Affine2D64) To32() Affine2D {
+	return Affine2D{
+		a: float32(a.a), b: float32(a.b), c: float32(a.c),
+		d: float32(a.d), e: float32(a.e), f: float32(a.f),
+	}
+}
+
+// Offset performs a translation on the transformation matrix by a given vector.
+// offset defines the amount of translation along X and Y.
+func (a Affine2D64) Offset(offset Point64) Affine2D64 {
+	return Affine2D64{
+		a.a, a.b, a.c + offset.X, // translation along X
+		a.d, a.e, a.f + offset.Y, // translation along Y
+	}
+}
+
+// OffsetInPlace translates the matrix in place.
+// offset defines the amount of translation along X and Y.
+func (a *Affine2D64) OffsetInPlace(offset Point64) {
+	a.c += offset.X
+	a.f += offset.Y
+}
+
+// Scale performs scaling on the matrix relative to a given point.
+// origin defines the point around which scaling occurs.
+// factor defines the scaling factors along X and Y.
+// Returns a new transformation matrix that takes scaling into account.
+func (a Affine2D64) Scale(origin, factor Point64) Affine2D64 {
+	if origin == (Point64{}) {
+		return a.scale(factor)
+	}
+	a = a.Offset(origin.Mul(-1))
+	a = a.scale(factor)
+	return a.Offset(origin)
+}
+
+// ScaleInPlace scales the matrix in place.
+// origin defines the point relative to which scaling occurs.
+// factor defines the scaling factors along X and Y.
+func (a *Affine2D64) ScaleInPlace(origin, factor Point64) {
+	*a = a.Scale(origin, factor)
+}
+
+// Rotate performs a rotation on the matrix around a given point by a specified angle.
+// origin defines the point around which the rotation occurs.
+// radians defines the angle of rotation in radians.
+// Returns a new transformation matrix that takes rotation into account.
+func (a Affine2D64) Rotate(origin Point64, radians float64) Affine2D64 {
+	if origin == (Point64{}) {
+		return a.rotate(radians)
+	}
+	a = a.Offset(origin.Mul(-1)) // shift to origin
+	a = a.rotate(radians)        // rotate
+	return a.Offset(origin)      // return to original position
+}
+
+// RotateInPlace rotates the matrix in place.
+// origin defines the point relative to which rotation occurs.
+// radians defines the angle of rotation in radians.
+func (a *Affine2D64) RotateInPlace(origin Point64, radians float64) {
+	*a = a.Rotate(origin, radians)
+}
+
+// Shear performs a shear transformation on the matrix under given angles relative to a specified point.
+// origin defines the point relative to which shearing occurs.
+// radiansX and radiansY define the shear angles along the X and Y axes, respectively.
+// Returns a new transformation matrix that takes shear into account.
+func (a Affine2D64) Shear(origin Point64, radiansX, radiansY float64) Affine2D64 {
+	if origin == (Point64{}) {
+		return a.shear(radiansX, radiansY)
+	}
+	a = a.Offset(origin.Mul(-1))    // shift the coordinate system
+	a = a.shear(radiansX, radiansY) // apply shear
+	return a.Offset(origin)         // return to original position
+}
+
+// Mul multiplies the current matrix by another matrix.
+// B is the other transformation matrix.
+// Returns the result of the multiplication.
+func (A Affine2D64) Mul(B Affine2D64) (r Affine2D64) {
+	r.a = (A.a+1)*(B.a+1) + A.b*B.d - 1
+	r.b = (A.a+1)*B.b + A.b*(B.e+1)
+	r.c = (A.a+1)*B.c + A.b*B.f + A.c
+	r.d = A.d*(B.a+1) + (A.e+1)*B.d
+	r.e = A.d*B.b + (A.e+1)*(B.e+1) - 1
+	r.f = A.d*B.c + (A.e+1)*B.f + A.f
+	return r
+}
+
+// Invert computes the inverse transformation for the current matrix.
+// If the matrix is singular or ill-conditioned with respect to DefaultTolerance,
+// the identity transformation is returned; use TryInvert to detect that case.
+func (a Affine2D64) Invert() Affine2D64 {
+	inv, err := a.TryInvert(DefaultTolerance)
+	if err != nil {
+		return Affine2D64{} // matrix is singular
+	}
+	return inv
+}
+
+// TryInvert computes the inverse transformation for the current matrix.
+// tolerance is the smallest accepted reciprocal condition number of the linear part
+// (see Cond); a tolerance of zero only rejects matrices with a zero determinant.
+// Returns ErrSingularMatrix if the matrix cannot be inverted reliably.
+func (a Affine2D64) TryInvert(tolerance float64) (Affine2D64, error) {
+	det := a.Determinant()
+	if det == 0 || math.IsNaN(det) || math.IsInf(det, 0) {
+		return Affine2D64{}, ErrSingularMatrix
+	}
+	if tolerance > 0 && 1/a.Cond() < tolerance {
+		return Affine2D64{}, ErrSingularMatrix
+	}
+	// The diagonal is kept in offset-from-identity form, so that
+	// transformations close to the identity are inverted without
+	// cancellation: (1+e)/det - 1 = (b*d - a*(1+e)) / det.
+	var r Affine2D64
+	r.a = (a.b*a.d - a.a*(a.e+1)) / det
+	r.b = -a.b / det
+	r.d = -a.d / det
+	r.e = (a.b*a.d - a.e*(a.a+1)) / det
+	r.c = -(r.a+1)*a.c - r.b*a.f
+	r.f = -r.d*a.c - (r.e+1)*a.f
+	return r, nil
+}
+
+// Determinant returns the determinant of the linear part of the transformation.
+// A negative determinant indicates that the transformation contains a reflection.
+func (a Affine2D64) Determinant() float64 {
+	return (a.a+1)*(a.e+1) - a.b*a.d
+}
+
+// Cond returns the condition number of the linear part of the transformation,
+// that is the ratio of its largest to its smallest singular value.
+// The result is 1 for rigid and uniformly scaled transformations and +Inf for singular ones.
+func (a Affine2D64) Cond() float64 {
+	smax, smin := a.singularValues()
+	if smin == 0 {
+		return math.Inf(1)
+	}
+	return smax / smin
+}
+
+// singularValues returns the largest and the smallest singular values of the linear part.
+func (a Affine2D64) singularValues() (smax, smin float64) {
+	sx, hx, _, hy, sy, _ := a.Elems()
+	e, f := (sx+sy)/2, (sx-sy)/2
+	g, h := (hy+hx)/2, (hy-hx)/2
+	q, r := math.Hypot(e, h), math.Hypot(f, g)
+	return q + r, math.Abs(q - r)
+}
+
+// Transform applies the current transformation to a given point.
+// p is the point to which the transformation is applied.
+func (a Affine2D64) Transform(p Point64) Point64 {
+	return Point64{
+		X: p.X*(a.a+1) + p.Y*a.b + a.c,
+		Y: p.X*a.d + p.Y*(a.e+1) + a.f,
+	}
+}
+
+// Elems returns the elements of the transformation matrix.
+func (a Affine2D64) Elems() (sx, hx, ox, hy, sy, oy float64) {
+	return a.a + 1, a.b, a.c, a.d, a.e + 1, a.f
+}
+
+// Split splits the transformation into a matrix without translation and a translation vector.
+// Returns a new matrix and a translation vector.
+func (a Affine2D64) Split() (src Affine2D64, offset Point64) {
+	return Affine2D64{
+		a: a.a, b: a.b, c: 0,
+		d: a.d, e: a.e, f: 0,
+	}, Point64{X: a.c, Y: a.f}
+}
+
+// scale performs internal scaling of the current matrix.
+// factor defines the scaling factors.
+func (a Affine2D64) scale(factor Point64) Affine2D64 {
+	return Affine2D64{
+		(a.a+1)*factor.X - 1, a.b * factor.X, a.c * factor.X, // scale along X
+		a.d * factor.Y, (a.e+1)*factor.Y - 1, a.f * factor.Y, // scale along Y
+	}
+}
+
+// rotate performs internal rotation of the matrix by the specified angle.
+// radians defines the angle of rotation in radians.
+func (a Affine2D64) rotate(radians float64) Affine2D64 {
+	s, c := math.Sincos(radians)
+	return Affine2D64{
+		(a.a+1)*c - a.d*s - 1, a.b*c - (a.e+1)*s, a.c*c - a.f*s, // apply rotation
+		(a.a+1)*s + a.d*c, a.b*s + (a.e+1)*c - 1, a.c*s + a.f*c, // update elements for translation along Y
+	}
+}
+
+// shear performs internal shearing of the matrix under given angles.
+// radiansX and radiansY define the shear angles along the X and Y axes.
+func (a Affine2D64) shear(radiansX, radiansY float64) Affine2D64 {
+	tx := math.Tan(radiansX)
+	ty := math.Tan(radiansY)
+
+	return Affine2D64{
+		(a.a + 1) + a.d*tx - 1, a.b + (a.e+1)*tx, a.c + a.f*tx, // update elements for shifting along X
+		(a.a+1)*ty + a.d, a.b*ty + (a.e + 1) - 1, a.c*ty + a.f, // update elements for shifting along Y
+	}
+}
+
+// String returns the string representation of the transformation matrix.
+// Format: "[[sx hx ox] [hy sy oy]]".
+func (a Affine2D64) String() string {
+	sx, hx, ox, hy, sy, oy := a.Elems()
+	var b strings.Builder
+	b.WriteString("[[")
+	b.WriteString(strconv.FormatFloat(sx, 'g', 6, 64))
+	b.WriteString(" ")
+	b.WriteString(strconv.FormatFloat(hx, 'g', 6, 64))
+	b.WriteString(" ")
+	b.WriteString(strconv.FormatFloat(ox, 'g', 6, 64))
+	b.WriteString("] [")
+	b.WriteString(strconv.FormatFloat(hy, 'g', 6, 64))
+	b.WriteString(" ")
+	b.WriteString(strconv.FormatFloat(sy, 'g', 6, 64))
+	b.WriteString(" ")
+	b.WriteString(strconv.FormatFloat(oy, 'g', 6, 64))
+	b.WriteString("]]")
+	return b.String()
+}
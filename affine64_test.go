@@ -0,0 +1,140 @@
+package tochka
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// TestNewAffine2D64 tests that the constructor creates the identity for the identity elements.
+func TestNewAffine2D64(t *testing.T) {
+	a := NewAffine2D64(1, 0, 0, 0, 1, 0)
+	if a != (Affine2D64{}) {
+		t.Errorf("Expected identity, got %v", a)
+	}
+}
+
+// TestAffine2D64_Conversion tests that conversion to double precision is lossless.
+func TestAffine2D64_Conversion(t *testing.T) {
+	a := NewAffine2D(1.1, 0.3, -7.25, 0.2, 0.9, 1e-3)
+	if got := a.To64().To32(); got != a {
+		t.Errorf("To64().To32() = %v; want %v", got, a)
+	}
+	sx, hx, ox, hy, sy, oy := a.Elems()
+	gsx, ghx, gox, ghy, gsy, goy := a.To64().Elems()
+	if gsx != float64(sx) || ghx != float64(hx) || gox != float64(ox) ||
+		ghy != float64(hy) || gsy != float64(sy) || goy != float64(oy) {
+		t.Errorf("To64().Elems() = %v; want %v", a.To64(), a)
+	}
+}
+
+// TestAffine2D64_Semantics tests that every operation of Affine2D64 matches Affine2D.
+func TestAffine2D64_Semantics(t *testing.T) {
+	origin := NewPoint(1, -2)
+	p := NewPoint(3, 5)
+	a := NewAffine2D(2, 0.5, 3, -0.25, 1.5, -1)
+	b := NewAffine2D(0.5, 0, 2, 0.75, 2, 4)
+
+	cases := []struct {
+		name string
+		f32  Affine2D
+		f64  Affine2D64
+	}{
+		{"Offset", a.Offset(origin), a.To64().Offset(origin.To64())},
+		{"Scale", a.Scale(origin, NewPoint(2, 3)), a.To64().Scale(origin.To64(), NewPoint64(2, 3))},
+		{"Rotate", a.Rotate(origin, math.Pi/3), a.To64().Rotate(origin.To64(), math.Pi/3)},
+		{"Shear", a.Shear(origin, math.Pi/6, math.Pi/8), a.To64().Shear(origin.To64(), math.Pi/6, math.Pi/8)},
+		{"Mul", a.Mul(b), a.To64().Mul(b.To64())},
+		{"Invert", a.Invert(), a.To64().Invert()},
+	}
+	for _, tc := range cases {
+		if !affineAlmostEqual(tc.f64.To32(), tc.f32, 1e-5) {
+			t.Errorf("%s: float64 result %v differs from float32 result %v", tc.name, tc.f64, tc.f32)
+		}
+		got := tc.f64.Transform(p.To64()).To32()
+		want := tc.f32.Transform(p)
+		if !almostEqual(got.X, want.X, 1e-4) || !almostEqual(got.Y, want.Y, 1e-4) {
+			t.Errorf("%s: Transform() = %v; want %v", tc.name, got, want)
+		}
+	}
+
+	if got, want := a.To64().String(), a.String(); got != want {
+		t.Errorf("String() = %s; want %s", got, want)
+	}
+}
+
+// TestAffine2D64_InPlace tests that the in-place variants match the value variants.
+func TestAffine2D64_InPlace(t *testing.T) {
+	a := NewAffine2D64(2, 0.5, 3, -0.25, 1.5, -1)
+	origin, factor := NewPoint64(1, 1), NewPoint64(2, 0.5)
+
+	b := a
+	b.OffsetInPlace(origin)
+	if b != a.Offset(origin) {
+		t.Errorf("OffsetInPlace() = %v; want %v", b, a.Offset(origin))
+	}
+	b = a
+	b.ScaleInPlace(origin, factor)
+	if b != a.Scale(origin, factor) {
+		t.Errorf("ScaleInPlace() = %v; want %v", b, a.Scale(origin, factor))
+	}
+	b = a
+	b.RotateInPlace(origin, 1)
+	if b != a.Rotate(origin, 1) {
+		t.Errorf("RotateInPlace() = %v; want %v", b, a.Rotate(origin, 1))
+	}
+}
+
+// TestAffine2D64_Precision tests that chained operations keep double precision.
+func TestAffine2D64_Precision(t *testing.T) {
+	a := NewAffine2D64(1, 0, 0, 0, 1, 0).
+		Rotate(NewPoint64(1e4, -3e4), 0.1).
+		Scale(NewPoint64(5e3, 2e3), NewPoint64(3, 7)).
+		Offset(NewPoint64(1e5, 1e5))
+	p := NewPoint64(123456.789, -98765.4321)
+
+	r := a
+	for i := 0; i < 16; i++ {
+		r = r.Mul(a).Mul(a.Invert())
+	}
+	q := a.Invert().Transform(r.Transform(p))
+	if d := q.Distance(p); d > 1e-6 {
+		t.Errorf("round trip drifted by %v", d)
+	}
+}
+
+// TestAffine2D64_TryInvert tests exact inversion and singular matrix detection in double precision.
+func TestAffine2D64_TryInvert(t *testing.T) {
+	a := NewAffine2D64(1+1e-12, 1e-12, 3, -1e-12, 1, 4)
+	inv, err := a.TryInvert(DefaultTolerance)
+	if err != nil {
+		t.Fatalf("TryInvert() returned an error: %v", err)
+	}
+	if id := a.Mul(inv); math.Abs(id.a) > 1e-18 || math.Abs(id.b) > 1e-18 ||
+		math.Abs(id.d) > 1e-18 || math.Abs(id.e) > 1e-18 {
+		t.Errorf("a * a^-1 = %v; want identity", id)
+	}
+
+	if _, err := NewAffine2D64(1, 1, 0, 1, 1, 0).TryInvert(0); !errors.Is(err, ErrSingularMatrix) {
+		t.Errorf("TryInvert() error = %v; want %v", err, ErrSingularMatrix)
+	}
+	if inv := NewAffine2D64(1, 1, 0, 1, 1, 0).Invert(); inv != (Affine2D64{}) {
+		t.Errorf("Invert() = %v; want identity for a singular matrix", inv)
+	}
+}
+
+// TestAffine2D64_Split tests separating the matrix into its linear part and offset.
+func TestAffine2D64_Split(t *testing.T) {
+	a := NewAffine2D64(2, 1, 3, 1, 2, 4)
+	mat, off := a.Split()
+	if mat != NewAffine2D64(2, 1, 0, 1, 2, 0) || off != NewPoint64(3, 4) {
+		t.Errorf("Split failed: got mat %+v, offset %+v", mat, off)
+	}
+}
+
+// affineAlmostEqual compares the elements of two transformations within a given precision.
+func affineAlmostEqual(a, b Affine2D, epsilon float32) bool {
+	return almostEqual(a.a, b.a, epsilon) && almostEqual(a.b, b.b, epsilon) &&
+		almostEqual(a.c, b.c, epsilon) && almostEqual(a.d, b.d, epsilon) &&
+		almostEqual(a.e, b.e, epsilon) && almostEqual(a.f, b.f, epsilon)
+}
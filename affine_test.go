@@ -1,6 +1,7 @@
 package tochka
 
 import (
+	"errors"
 	"math"
 	"testing"
 )
@@ -56,6 +57,74 @@ func TestAffine2D_Invert(t *testing.T) {
 	}
 }
 
+// TestAffine2D_InvertNearIdentity tests that matrices close to the identity are inverted exactly
+// instead of being treated as pure translations.
+func TestAffine2D_InvertNearIdentity(t *testing.T) {
+	a := NewAffine2D(1, 0, 5, 0, 1, 7).Scale(Point{}, Point{X: 1 + 1e-6, Y: 1 - 1e-6}).Rotate(Point{}, 1e-6)
+	p := Point{X: 1000, Y: -1000}
+	q := a.Invert().Transform(a.Transform(p))
+	if !almostEqual(q.X, p.X, 1e-3) || !almostEqual(q.Y, p.Y, 1e-3) {
+		t.Errorf("Invert round trip failed. Expected %v, got %v", p, q)
+	}
+}
+
+// TestAffine2D_TryInvert tests that singular and ill-conditioned matrices are reported.
+func TestAffine2D_TryInvert(t *testing.T) {
+	tests := []struct {
+		name      string
+		a         Affine2D
+		tolerance float32
+		wantErr   bool
+	}{
+		{"identity", Affine2D{}, DefaultTolerance, false},
+		{"small uniform scale", NewAffine2D(1e-4, 0, 1, 0, 1e-4, 1), DefaultTolerance, false},
+		{"singular", NewAffine2D(1, 2, 0, 2, 4, 0), 0, true},
+		{"zero", NewAffine2D(0, 0, 3, 0, 0, 4), 0, true},
+		{"ill-conditioned", NewAffine2D(1, 0, 0, 0, 1e-4, 0), 1e-3, true},
+		{"ill-conditioned within tolerance", NewAffine2D(1, 0, 0, 0, 1e-4, 0), 1e-5, false},
+	}
+	for _, tt := range tests {
+		inv, err := tt.a.TryInvert(tt.tolerance)
+		if tt.wantErr {
+			if !errors.Is(err, ErrSingularMatrix) {
+				t.Errorf("%s: TryInvert() error = %v; want %v", tt.name, err, ErrSingularMatrix)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: TryInvert() returned an error: %v", tt.name, err)
+			continue
+		}
+		if id := tt.a.Mul(inv); !affineAlmostEqual(id, Affine2D{}, 1e-4) {
+			t.Errorf("%s: a * a^-1 = %v; want identity", tt.name, id)
+		}
+	}
+}
+
+// TestAffine2D_Determinant tests the determinant of the linear part.
+func TestAffine2D_Determinant(t *testing.T) {
+	a := NewAffine2D(2, 1, 5, 1, 3, 6)
+	if det := a.Determinant(); det != 5 {
+		t.Errorf("Determinant failed. Expected 5, got %v", det)
+	}
+	if det := NewAffine2D(-1, 0, 0, 0, 1, 0).Determinant(); det != -1 {
+		t.Errorf("Determinant failed. Expected -1 for a reflection, got %v", det)
+	}
+}
+
+// TestAffine2D_Cond tests the condition number of the linear part.
+func TestAffine2D_Cond(t *testing.T) {
+	if c := NewAffine2D(3, 0, 1, 0, 3, 1).Rotate(Point{}, 0.7).Cond(); !almostEqual(c, 1, 1e-5) {
+		t.Errorf("Cond failed. Expected 1 for a similarity, got %v", c)
+	}
+	if c := NewAffine2D(4, 0, 0, 0, 0.5, 0).Cond(); !almostEqual(c, 8, 1e-5) {
+		t.Errorf("Cond failed. Expected 8, got %v", c)
+	}
+	if c := NewAffine2D(1, 2, 0, 2, 4, 0).Cond(); !math.IsInf(float64(c), 1) {
+		t.Errorf("Cond failed. Expected +Inf for a singular matrix, got %v", c)
+	}
+}
+
 // TestAffine2D_Transform tests applying the transformation to a point.
 func TestAffine2D_Transform(t *testing.T) {
 	a := NewAffine2D(1, 0, 1, 0, 1, 1)
@@ -65,6 +134,69 @@ func TestAffine2D_Transform(t *testing.T) {
 	if transformed != expected {
 		t.Errorf("Transform failed. Expected %v, got %v", expected, transformed)
 	}
+
+	// The shear along Y multiplies X, which a point with distinct coordinates tells apart.
+	a = NewAffine2D(2, 3, 1, 5, 7, -1)
+	transformed = a.Transform(Point{X: 2, Y: 10})
+	expected = Point{X: 35, Y: 79}
+	if transformed != expected {
+		t.Errorf("Transform with shear failed. Expected %v, got %v", expected, transformed)
+	}
+}
+
+// TestAffine2D_TransformPoints tests that the batch transformation matches Transform.
+func TestAffine2D_TransformPoints(t *testing.T) {
+	a := NewAffine2D(2, 0.5, 3, -0.25, 1.5, -1)
+	src := []Point{{X: 1, Y: 2}, {X: -3, Y: 4}, {X: 0.5, Y: -0.5}}
+	dst := make([]Point, 2)
+	if n := a.TransformPoints(dst, src); n != 2 {
+		t.Errorf("TransformPoints() = %d; want 2", n)
+	}
+	for k := range dst {
+		if want := a.Transform(src[k]); !pointAlmostEqual(dst[k], want, 1e-5) {
+			t.Errorf("TransformPoints()[%d] = %v; want %v", k, dst[k], want)
+		}
+	}
+
+	// In-place operation.
+	want := []Point{a.Transform(src[0]), a.Transform(src[1]), a.Transform(src[2])}
+	a.TransformPoints(src, src)
+	for k := range src {
+		if !pointAlmostEqual(src[k], want[k], 1e-5) {
+			t.Errorf("TransformPoints() in place [%d] = %v; want %v", k, src[k], want[k])
+		}
+	}
+}
+
+// TestAffine2D_TransformXY tests that interleaved coordinates are transformed pairwise.
+func TestAffine2D_TransformXY(t *testing.T) {
+	a := NewAffine2D(2, 0.5, 3, -0.25, 1.5, -1)
+	buf := []float32{1, 2, -3, 4, 7}
+	want0, want1 := a.Transform(Point{X: 1, Y: 2}), a.Transform(Point{X: -3, Y: 4})
+	if n := a.TransformXY(buf, buf); n != 2 {
+		t.Errorf("TransformXY() = %d; want 2", n)
+	}
+	got0, got1 := Point{X: buf[0], Y: buf[1]}, Point{X: buf[2], Y: buf[3]}
+	if !pointAlmostEqual(got0, want0, 1e-5) || !pointAlmostEqual(got1, want1, 1e-5) {
+		t.Errorf("TransformXY() = %v, %v; want %v, %v", got0, got1, want0, want1)
+	}
+	if buf[4] != 7 {
+		t.Errorf("TransformXY() modified the trailing element: %v", buf[4])
+	}
+}
+
+// TestAffine2D_TransformBatchAllocs tests that the batch transformations do not allocate.
+func TestAffine2D_TransformBatchAllocs(t *testing.T) {
+	a := NewAffine2D(2, 0.5, 3, -0.25, 1.5, -1)
+	points := make([]Point, 64)
+	xy := make([]float32, 128)
+	allocs := testing.AllocsPerRun(10, func() {
+		a.TransformPoints(points, points)
+		a.TransformXY(xy, xy)
+	})
+	if allocs != 0 {
+		t.Errorf("batch transformations allocated %v times; want 0", allocs)
+	}
 }
 
 // TestAffine2D_Mul tests matrix multiplication by verifying the Mul() method.
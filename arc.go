@@ -0,0 +1,84 @@
+package tochka
+
+import "math"
+
+// Arc represents a portion of the boundary of an ellipse. Start and Sweep are parametric
+// angles in radians: the point at angle θ is the image of (cos θ, sin θ) under the map that
+// takes the unit circle onto the ellipse. A positive Sweep runs counterclockwise, in the
+// direction of increasing angles, and a Sweep of 2π or more covers the whole ellipse.
+type Arc struct {
+	Ellipse      Ellipse
+	Start, Sweep float32
+}
+
+// NewArc creates an arc of the ellipse e starting at the parametric angle start and
+// extending by sweep radians.
+func NewArc(e Ellipse, start, sweep float32) Arc {
+	return Arc{Ellipse: e, Start: start, Sweep: sweep}
+}
+
+// At returns the point of the arc at parameter t in [0, 1], where t = 0 yields the start
+// of the arc and t = 1 yields its end.
+func (a Arc) At(t float32) Point {
+	return a.at64(float64(a.Start) + float64(t)*float64(a.Sweep)).To32()
+}
+
+// Bounds returns the tight axis-aligned bounds of the arc, computed from its endpoints
+// and the extrema of the ellipse that lie within the sweep.
+func (a Arc) Bounds() Rect {
+	points := []Point{a.At(0), a.At(1)}
+	sin, cos := math.Sincos(float64(a.Ellipse.Rotation))
+	rx, ry := float64(a.Ellipse.Radii.X), float64(a.Ellipse.Radii.Y)
+	tx := math.Atan2(-ry*sin, rx*cos)
+	ty := math.Atan2(ry*cos, rx*sin)
+	for _, theta := range [4]float64{tx, tx + math.Pi, ty, ty + math.Pi} {
+		if a.covers(theta) {
+			points = append(points, a.at64(theta).To32())
+		}
+	}
+	return boundsOf(points)
+}
+
+// Transform applies the transformation a to the arc. The ellipse is transformed exactly
+// and the angles are adjusted to its new axes, so that the arc covers the image of the
+// original arc. A transformation with a negative determinant reverses the sweep.
+func (a Arc) Transform(m Affine2D) Arc {
+	full := m.To64().Mul(a.Ellipse.unitMap())
+	e := ellipseFromUnitMap(full)
+	inv, err := e.unitMap().TryInvert(0)
+	if err != nil {
+		return Arc{Ellipse: e, Start: a.Start, Sweep: a.Sweep}
+	}
+	// The map between the unit frames of both ellipses is a rotation or a reflection.
+	r := inv.Mul(full)
+	sin, cos := math.Sincos(float64(a.Start))
+	p := r.Transform(Point64{X: cos, Y: sin})
+	sweep := a.Sweep
+	if r.Determinant() < 0 {
+		sweep = -sweep
+	}
+	return Arc{Ellipse: e, Start: float32(math.Atan2(p.Y, p.X)), Sweep: sweep}
+}
+
+// at64 returns the point of the ellipse at the parametric angle theta in double precision.
+func (a Arc) at64(theta float64) Point64 {
+	sin, cos := math.Sincos(theta)
+	return a.Ellipse.unitMap().Transform(Point64{X: cos, Y: sin})
+}
+
+// covers reports whether the parametric angle theta lies within the sweep of the arc.
+func (a Arc) covers(theta float64) bool {
+	sweep := float64(a.Sweep)
+	if math.Abs(sweep) >= 2*math.Pi {
+		return true
+	}
+	d := theta - float64(a.Start)
+	if sweep < 0 {
+		d, sweep = -d, -sweep
+	}
+	d = math.Mod(d, 2*math.Pi)
+	if d < 0 {
+		d += 2 * math.Pi
+	}
+	return d <= sweep
+}
@@ -0,0 +1,63 @@
+package tochka
+
+import (
+	"math"
+	"testing"
+)
+
+// TestArc_At tests the endpoints and midpoint of a rotated elliptical arc.
+func TestArc_At(t *testing.T) {
+	a := NewArc(NewEllipse(Point{X: 1, Y: 1}, 2, 1, math.Pi/2), 0, math.Pi)
+	tests := []struct {
+		t    float32
+		want Point
+	}{
+		{0, Point{X: 1, Y: 3}},
+		{0.5, Point{X: 0, Y: 1}},
+		{1, Point{X: 1, Y: -1}},
+	}
+	for _, tt := range tests {
+		if got := a.At(tt.t); !pointAlmostEqual(got, tt.want, 1e-6) {
+			t.Errorf("At(%v) = %v; want %v", tt.t, got, tt.want)
+		}
+	}
+}
+
+// TestArc_Bounds tests that the bounds include only the extrema within the sweep.
+func TestArc_Bounds(t *testing.T) {
+	e := NewEllipse(Point{}, 2, 1, 0)
+	tests := []struct {
+		start, sweep float32
+		want         Rect
+	}{
+		{0, math.Pi / 2, NewRect(0, 0, 2, 1)},
+		{0, -math.Pi / 2, NewRect(0, -1, 2, 0)},
+		{math.Pi / 4, math.Pi, NewRect(-2, -math.Sqrt2/2, math.Sqrt2, 1)},
+		{1, 2 * math.Pi, NewRect(-2, -1, 2, 1)},
+	}
+	for _, tt := range tests {
+		got := NewArc(e, tt.start, tt.sweep).Bounds()
+		if !pointAlmostEqual(got.Min, tt.want.Min, 1e-6) || !pointAlmostEqual(got.Max, tt.want.Max, 1e-6) {
+			t.Errorf("Bounds() of (%v, %v) = %v; want %v", tt.start, tt.sweep, got, tt.want)
+		}
+	}
+}
+
+// TestArc_Transform tests that a transformed arc follows the transformed points of the original.
+func TestArc_Transform(t *testing.T) {
+	a := NewArc(NewEllipse(Point{X: 1, Y: 2}, 3, 1, 0.4), 0.3, 2)
+	transforms := []Affine2D{
+		Affine2D{}.Rotate(Point{}, 1).Offset(Point{X: 5, Y: 0}),
+		Affine2D{}.Shear(Point{}, 0.5, 0).Scale(Point{}, Point{X: 2, Y: 0.5}),
+		NewAffine2D(-1, 0, 0, 0, 1, 0),
+	}
+	for _, m := range transforms {
+		got := a.Transform(m)
+		for k := 0; k <= 10; k++ {
+			u := float32(k) / 10
+			if p, want := got.At(u), m.Transform(a.At(u)); !pointAlmostEqual(p, want, 1e-4) {
+				t.Errorf("Transform(%v).At(%v) = %v; want %v", m, u, p, want)
+			}
+		}
+	}
+}
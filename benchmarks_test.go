@@ -1,7 +1,9 @@
 package tochka
 
 import (
+	"fmt"
 	"math"
+	"math/rand/v2"
 	"testing"
 )
 
@@ -37,6 +39,160 @@ func BenchmarkAffineString(b *testing.B) {
 	a := NewAffine2D(1, 0, 2, 0, 1, 3)
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		a.String()
+		_ = a.String()
 	}
 }
+
+// benchmarkPoints is the number of points transformed by the batch benchmarks.
+const benchmarkPoints = 4096
+
+// BenchmarkTransformLoop measures the performance of calling Transform() in a loop over a slice.
+func BenchmarkTransformLoop(b *testing.B) {
+	a := NewAffine2D(2, 0.5, 3, -0.25, 1.5, -1)
+	points := make([]Point, benchmarkPoints)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for k, p := range points {
+			points[k] = a.Transform(p)
+		}
+	}
+}
+
+// BenchmarkTransformPoints measures the performance of the TransformPoints() method.
+func BenchmarkTransformPoints(b *testing.B) {
+	a := NewAffine2D(2, 0.5, 3, -0.25, 1.5, -1)
+	points := make([]Point, benchmarkPoints)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a.TransformPoints(points, points)
+	}
+}
+
+// BenchmarkTransformXY measures the performance of the TransformXY() method.
+func BenchmarkTransformXY(b *testing.B) {
+	a := NewAffine2D(2, 0.5, 3, -0.25, 1.5, -1)
+	xy := make([]float32, 2*benchmarkPoints)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a.TransformXY(xy, xy)
+	}
+}
+
+// benchmarkFlatten measures the performance of a Flatten() method at several tolerances
+// and reports the number of segments emitted per curve.
+func benchmarkFlatten(b *testing.B, flatten func(float32) []Point) {
+	for _, tol := range []float32{1, 0.25, 0.01} {
+		b.Run(fmt.Sprintf("tol=%v", tol), func(b *testing.B) {
+			var segments int
+			for i := 0; i < b.N; i++ {
+				segments = len(flatten(tol)) - 1
+			}
+			b.ReportMetric(float64(segments), "segments")
+		})
+	}
+}
+
+// BenchmarkFlattenQuad measures the flattening of a quadratic Bézier curve.
+func BenchmarkFlattenQuad(b *testing.B) {
+	benchmarkFlatten(b, NewQuadBezier(Point{X: 0, Y: 0}, Point{X: 50, Y: 100}, Point{X: 100, Y: 0}).Flatten)
+}
+
+// BenchmarkFlattenCubic measures the flattening of a cubic Bézier curve.
+func BenchmarkFlattenCubic(b *testing.B) {
+	c := NewCubicBezier(Point{X: 0, Y: 0}, Point{X: 0, Y: 100}, Point{X: 100, Y: 100}, Point{X: 100, Y: 0})
+	benchmarkFlatten(b, c.Flatten)
+}
+
+// BenchmarkFlattenArc measures the flattening of an elliptical arc.
+func BenchmarkFlattenArc(b *testing.B) {
+	benchmarkFlatten(b, NewArc(NewEllipse(Point{}, 100, 50, 0.3), 0, math.Pi).Flatten)
+}
+
+// BenchmarkAppendPoints measures the encoding of a flattened circle and reports
+// the encoded size in bytes per point.
+func BenchmarkAppendPoints(b *testing.B) {
+	pts := NewEllipse(Point{X: 500, Y: 500}, 200, 200, 0).Flatten(0.01)
+	for _, tt := range []struct {
+		name string
+		opts BinaryOptions
+	}{
+		{"float32", BinaryOptions{}},
+		{"int16", BinaryOptions{Quantization: QuantizeInt16, Scale: 16}},
+		{"delta", BinaryOptions{Quantization: QuantizeInt32, Scale: 16, Delta: true}},
+	} {
+		b.Run(tt.name, func(b *testing.B) {
+			var buf []byte
+			for i := 0; i < b.N; i++ {
+				buf, _ = AppendPoints(buf[:0], pts, tt.opts)
+			}
+			b.ReportMetric(float64(len(buf))/float64(len(pts)), "bytes/point")
+		})
+	}
+}
+
+// BenchmarkConvexHull measures the convex hull of random points in a disk.
+func BenchmarkConvexHull(b *testing.B) {
+	r := rand.New(rand.NewPCG(1, 2))
+	points := make([]Point, 10000)
+	for k := range points {
+		s, c := math.Sincos(r.Float64() * 2 * math.Pi)
+		d := math.Sqrt(r.Float64()) * 100
+		points[k] = Point{X: float32(c * d), Y: float32(s * d)}
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ConvexHull(points)
+	}
+}
+
+// BenchmarkTriangulate measures the triangulation of a star-shaped polygon with 1000
+// vertices and 16 square holes.
+func BenchmarkTriangulate(b *testing.B) {
+	r := rand.New(rand.NewPCG(1, 2))
+	var p Polygon
+	for k := range 1000 {
+		s, c := math.Sincos(2 * math.Pi * float64(k) / 1000)
+		d := 90 + 10*r.Float64()
+		p.Outer = append(p.Outer, Point{X: float32(c * d), Y: float32(s * d)})
+	}
+	for x := -30; x < 30; x += 16 {
+		for y := -30; y < 30; y += 16 {
+			o := Point{X: float32(x), Y: float32(y)}
+			p.Holes = append(p.Holes, Ring{o, o.Add(Point{Y: 8}), o.Add(Point{X: 8, Y: 8}), o.Add(Point{X: 8})})
+		}
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p.Triangulate()
+	}
+}
+
+// BenchmarkDelaunay measures the Delaunay triangulation of random points in a disk.
+func BenchmarkDelaunay(b *testing.B) {
+	r := rand.New(rand.NewPCG(1, 2))
+	points := make([]Point, 10000)
+	for k := range points {
+		s, c := math.Sincos(r.Float64() * 2 * math.Pi)
+		d := math.Sqrt(r.Float64()) * 100
+		points[k] = Point{X: float32(c * d), Y: float32(s * d)}
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Delaunay(points)
+	}
+}
+
+// BenchmarkOrient2D measures Orient2D on a clear turn, decided by the float64 filter,
+// and on collinear points of very different magnitudes, which need the exact fallback.
+func BenchmarkOrient2D(b *testing.B) {
+	b.Run("filter", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			Orient2D(Point{X: 0, Y: 0}, Point{X: 12, Y: 12}, Point{X: 24, Y: 25})
+		}
+	})
+	b.Run("exact", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			Orient2D(Point{X: 1e20, Y: 1e20}, Point{X: 1e-20, Y: 1e-20}, Point{X: 3, Y: 3})
+		}
+	})
+}
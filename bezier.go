@@ -0,0 +1,330 @@
+package tochka
+
+import "math"
+
+// closestSamples is the number of intervals in which a curve is sampled to seed
+// the search for the point closest to a given point.
+const closestSamples = 16
+
+// QuadBezier represents a quadratic Bézier curve with endpoints P0 and P2 and control point P1.
+type QuadBezier struct {
+	P0, P1, P2 Point
+}
+
+// CubicBezier represents a cubic Bézier curve with endpoints P0 and P3 and control points P1 and P2.
+type CubicBezier struct {
+	P0, P1, P2, P3 Point
+}
+
+// NewQuadBezier creates a quadratic Bézier curve from its control points.
+func NewQuadBezier(p0, p1, p2 Point) QuadBezier {
+	return QuadBezier{P0: p0, P1: p1, P2: p2}
+}
+
+// NewCubicBezier creates a cubic Bézier curve from its control points.
+func NewCubicBezier(p0, p1, p2, p3 Point) CubicBezier {
+	return CubicBezier{P0: p0, P1: p1, P2: p2, P3: p3}
+}
+
+// At returns the point of the curve at parameter t in [0, 1].
+func (q QuadBezier) At(t float32) Point {
+	return q.at64(float64(t)).To32()
+}
+
+// Derivative returns the first derivative of the curve at parameter t.
+func (q QuadBezier) Derivative(t float32) Point {
+	return q.derivative64(float64(t)).To32()
+}
+
+// Tangent returns the unit tangent of the curve at parameter t. Where the derivative
+// vanishes because control points coincide, the direction of the curve is used instead.
+// A curve collapsed to a single point has a zero tangent.
+func (q QuadBezier) Tangent(t float32) Point {
+	p0, p1, p2 := q.P0.To64(), q.P1.To64(), q.P2.To64()
+	d := q.derivative64(float64(t))
+	if d == (Point64{}) {
+		d = p2.Sub(p0)
+		if t > 0.5 {
+			d = firstNonZero(p2.Sub(p1), p2.Sub(p0))
+		}
+	}
+	return unit64(d).To32()
+}
+
+// Normal returns the unit normal of the curve at parameter t, which is the tangent
+// rotated by 90 degrees counterclockwise.
+func (q QuadBezier) Normal(t float32) Point {
+	tan := q.Tangent(t)
+	return Point{X: -tan.Y, Y: tan.X}
+}
+
+// Split divides the curve at parameter t using de Casteljau's algorithm and returns
+// the part before and the part after t.
+func (q QuadBezier) Split(t float32) (QuadBezier, QuadBezier) {
+	p0, p1, p2 := q.P0.To64(), q.P1.To64(), q.P2.To64()
+	tt := float64(t)
+	a, b := lerp64(p0, p1, tt), lerp64(p1, p2, tt)
+	m := lerp64(a, b, tt)
+	return QuadBezier{P0: q.P0, P1: a.To32(), P2: m.To32()},
+		QuadBezier{P0: m.To32(), P1: b.To32(), P2: q.P2}
+}
+
+// Bounds returns the tight axis-aligned bounds of the curve, computed from the endpoints
+// and the extrema found at the roots of the derivative.
+func (q QuadBezier) Bounds() Rect {
+	points := []Point{q.P0, q.P2}
+	p0, p1, p2 := q.P0.To64(), q.P1.To64(), q.P2.To64()
+	// The derivative divided by 2 is a*t + b per coordinate.
+	for _, t := range [2]float64{
+		linearRoot(p0.X-2*p1.X+p2.X, p1.X-p0.X),
+		linearRoot(p0.Y-2*p1.Y+p2.Y, p1.Y-p0.Y),
+	} {
+		if t > 0 && t < 1 {
+			points = append(points, q.at64(t).To32())
+		}
+	}
+	return boundsOf(points)
+}
+
+// Elevate returns the cubic Bézier curve describing exactly the same curve.
+func (q QuadBezier) Elevate() CubicBezier {
+	p0, p1, p2 := q.P0.To64(), q.P1.To64(), q.P2.To64()
+	return CubicBezier{
+		P0: q.P0,
+		P1: p0.Add(p1.Sub(p0).Mul(2.0 / 3)).To32(),
+		P2: p2.Add(p1.Sub(p2).Mul(2.0 / 3)).To32(),
+		P3: q.P2,
+	}
+}
+
+// Project returns the parameter in [0, 1] of the point of the curve closest to p.
+func (q QuadBezier) Project(p Point) float32 {
+	return float32(closestParam(q.at64, q.derivative64, q.second64, p.To64()))
+}
+
+// ClosestPoint returns the point of the curve closest to p.
+func (q QuadBezier) ClosestPoint(p Point) Point {
+	return q.At(q.Project(p))
+}
+
+// Transform applies the transformation a to the curve. Affine transformations map
+// Bézier curves exactly by transforming their control points.
+func (q QuadBezier) Transform(a Affine2D) QuadBezier {
+	return QuadBezier{P0: a.Transform(q.P0), P1: a.Transform(q.P1), P2: a.Transform(q.P2)}
+}
+
+// At returns the point of the curve at parameter t in [0, 1].
+func (c CubicBezier) At(t float32) Point {
+	return c.at64(float64(t)).To32()
+}
+
+// Derivative returns the first derivative of the curve at parameter t.
+func (c CubicBezier) Derivative(t float32) Point {
+	return c.derivative64(float64(t)).To32()
+}
+
+// Tangent returns the unit tangent of the curve at parameter t. Where the derivative
+// vanishes because control points coincide, the direction towards the next distinct
+// control point is used instead. A curve collapsed to a single point has a zero tangent.
+func (c CubicBezier) Tangent(t float32) Point {
+	d := c.derivative64(float64(t))
+	if d == (Point64{}) {
+		p0, p1, p2, p3 := c.P0.To64(), c.P1.To64(), c.P2.To64(), c.P3.To64()
+		if t <= 0.5 {
+			d = firstNonZero(p2.Sub(p0), p3.Sub(p0))
+		} else {
+			d = firstNonZero(p3.Sub(p1), p3.Sub(p0))
+		}
+	}
+	return unit64(d).To32()
+}
+
+// Normal returns the unit normal of the curve at parameter t, which is the tangent
+// rotated by 90 degrees counterclockwise.
+func (c CubicBezier) Normal(t float32) Point {
+	tan := c.Tangent(t)
+	return Point{X: -tan.Y, Y: tan.X}
+}
+
+// Split divides the curve at parameter t using de Casteljau's algorithm and returns
+// the part before and the part after t.
+func (c CubicBezier) Split(t float32) (CubicBezier, CubicBezier) {
+	p0, p1, p2, p3 := c.P0.To64(), c.P1.To64(), c.P2.To64(), c.P3.To64()
+	tt := float64(t)
+	a, b, d := lerp64(p0, p1, tt), lerp64(p1, p2, tt), lerp64(p2, p3, tt)
+	e, f := lerp64(a, b, tt), lerp64(b, d, tt)
+	m := lerp64(e, f, tt)
+	return CubicBezier{P0: c.P0, P1: a.To32(), P2: e.To32(), P3: m.To32()},
+		CubicBezier{P0: m.To32(), P1: f.To32(), P2: d.To32(), P3: c.P3}
+}
+
+// Bounds returns the tight axis-aligned bounds of the curve, computed from the endpoints
+// and the extrema found at the roots of the derivative.
+func (c CubicBezier) Bounds() Rect {
+	points := []Point{c.P0, c.P3}
+	p0, p1, p2, p3 := c.P0.To64(), c.P1.To64(), c.P2.To64(), c.P3.To64()
+	// The derivative divided by 3 is a*t² + b*t + k per coordinate.
+	roots := append(
+		quadraticRoots(-p0.X+3*p1.X-3*p2.X+p3.X, 2*(p0.X-2*p1.X+p2.X), p1.X-p0.X),
+		quadraticRoots(-p0.Y+3*p1.Y-3*p2.Y+p3.Y, 2*(p0.Y-2*p1.Y+p2.Y), p1.Y-p0.Y)...,
+	)
+	for _, t := range roots {
+		if t > 0 && t < 1 {
+			points = append(points, c.at64(t).To32())
+		}
+	}
+	return boundsOf(points)
+}
+
+// Project returns the parameter in [0, 1] of the point of the curve closest to p.
+func (c CubicBezier) Project(p Point) float32 {
+	return float32(closestParam(c.at64, c.derivative64, c.second64, p.To64()))
+}
+
+// ClosestPoint returns the point of the curve closest to p.
+func (c CubicBezier) ClosestPoint(p Point) Point {
+	return c.At(c.Project(p))
+}
+
+// Transform applies the transformation a to the curve. Affine transformations map
+// Bézier curves exactly by transforming their control points.
+func (c CubicBezier) Transform(a Affine2D) CubicBezier {
+	return CubicBezier{
+		P0: a.Transform(c.P0), P1: a.Transform(c.P1),
+		P2: a.Transform(c.P2), P3: a.Transform(c.P3),
+	}
+}
+
+// at64 evaluates the curve at parameter t in double precision.
+func (q QuadBezier) at64(t float64) Point64 {
+	mt := 1 - t
+	return q.P0.To64().Mul(mt * mt).
+		Add(q.P1.To64().Mul(2 * mt * t)).
+		Add(q.P2.To64().Mul(t * t))
+}
+
+// derivative64 evaluates the first derivative at parameter t in double precision.
+func (q QuadBezier) derivative64(t float64) Point64 {
+	p0, p1, p2 := q.P0.To64(), q.P1.To64(), q.P2.To64()
+	return p1.Sub(p0).Mul(2 * (1 - t)).Add(p2.Sub(p1).Mul(2 * t))
+}
+
+// second64 evaluates the second derivative, which is constant for a quadratic curve.
+func (q QuadBezier) second64(float64) Point64 {
+	p0, p1, p2 := q.P0.To64(), q.P1.To64(), q.P2.To64()
+	return p0.Sub(p1.Mul(2)).Add(p2).Mul(2)
+}
+
+// at64 evaluates the curve at parameter t in double precision.
+func (c CubicBezier) at64(t float64) Point64 {
+	mt := 1 - t
+	return c.P0.To64().Mul(mt * mt * mt).
+		Add(c.P1.To64().Mul(3 * mt * mt * t)).
+		Add(c.P2.To64().Mul(3 * mt * t * t)).
+		Add(c.P3.To64().Mul(t * t * t))
+}
+
+// derivative64 evaluates the first derivative at parameter t in double precision.
+func (c CubicBezier) derivative64(t float64) Point64 {
+	p0, p1, p2, p3 := c.P0.To64(), c.P1.To64(), c.P2.To64(), c.P3.To64()
+	mt := 1 - t
+	return p1.Sub(p0).Mul(3 * mt * mt).
+		Add(p2.Sub(p1).Mul(6 * mt * t)).
+		Add(p3.Sub(p2).Mul(3 * t * t))
+}
+
+// second64 evaluates the second derivative at parameter t in double precision.
+func (c CubicBezier) second64(t float64) Point64 {
+	p0, p1, p2, p3 := c.P0.To64(), c.P1.To64(), c.P2.To64(), c.P3.To64()
+	a := p0.Sub(p1.Mul(2)).Add(p2)
+	b := p1.Sub(p2.Mul(2)).Add(p3)
+	return a.Mul(6 * (1 - t)).Add(b.Mul(6 * t))
+}
+
+// closestParam returns the parameter in [0, 1] minimizing the distance between the curve
+// and p. The curve is sampled to find the candidate minima, which are then refined with
+// Newton's method applied to the derivative of the squared distance.
+func closestParam(at, d1, d2 func(float64) Point64, p Point64) float64 {
+	best, bestDist := 0.0, math.Inf(1)
+	consider := func(t float64) {
+		if dist := at(t).Sub(p).Magnitude(); dist < bestDist {
+			best, bestDist = t, dist
+		}
+	}
+	for k := 0; k <= closestSamples; k++ {
+		t := float64(k) / closestSamples
+		consider(t)
+		for i := 0; i < 8; i++ {
+			diff := at(t).Sub(p)
+			v := d1(t)
+			num := diff.Dot(v)
+			den := v.Dot(v) + diff.Dot(d2(t))
+			if den <= 0 {
+				break
+			}
+			next := math.Min(1, math.Max(0, t-num/den))
+			if next == t {
+				break
+			}
+			t = next
+		}
+		consider(t)
+	}
+	return best
+}
+
+// lerp64 linearly interpolates between the points a and b at parameter t in double precision.
+func lerp64(a, b Point64, t float64) Point64 {
+	return a.Add(b.Sub(a).Mul(t))
+}
+
+// unit64 returns the vector scaled to unit length, or the zero vector if it has no length.
+func unit64(v Point64) Point64 {
+	m := v.Magnitude()
+	if m == 0 {
+		return Point64{}
+	}
+	return v.Mul(1 / m)
+}
+
+// firstNonZero returns the first of the vectors that is not zero.
+func firstNonZero(vs ...Point64) Point64 {
+	for _, v := range vs {
+		if v != (Point64{}) {
+			return v
+		}
+	}
+	return Point64{}
+}
+
+// linearRoot returns the root of a*t + b = 0, or NaN if a is zero.
+func linearRoot(a, b float64) float64 {
+	if a == 0 {
+		return math.NaN()
+	}
+	return -b / a
+}
+
+// quadraticRoots returns the real roots of a*t² + b*t + c = 0. A degenerate equation
+// with a zero leading coefficient is solved as a linear one.
+func quadraticRoots(a, b, c float64) []float64 {
+	if a == 0 {
+		if b == 0 {
+			return nil
+		}
+		return []float64{-c / b}
+	}
+	disc := b*b - 4*a*c
+	switch {
+	case disc < 0:
+		return nil
+	case disc == 0:
+		return []float64{-b / (2 * a)}
+	}
+	// Avoid cancellation by computing the root of larger magnitude first.
+	q := -(b + math.Copysign(math.Sqrt(disc), b)) / 2
+	if q == 0 {
+		return []float64{0}
+	}
+	return []float64{q / a, c / q}
+}
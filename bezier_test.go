@@ -0,0 +1,173 @@
+package tochka
+
+import (
+	"math"
+	"testing"
+)
+
+// arch is a symmetric cubic curve rising from (0, 0) to a peak of 3 at x = 2 and back to (4, 0).
+var arch = NewCubicBezier(Point{X: 0, Y: 0}, Point{X: 0, Y: 4}, Point{X: 4, Y: 4}, Point{X: 4, Y: 0})
+
+// TestQuadBezier_At tests evaluation, derivatives, tangents and normals of a quadratic curve.
+func TestQuadBezier_At(t *testing.T) {
+	q := NewQuadBezier(Point{X: 0, Y: 0}, Point{X: 1, Y: 2}, Point{X: 2, Y: 0})
+	if got := q.At(0.5); got != (Point{X: 1, Y: 1}) {
+		t.Errorf("At(0.5) = %v; want (1, 1)", got)
+	}
+	if q.At(0) != q.P0 || q.At(1) != q.P2 {
+		t.Errorf("At(0), At(1) = %v, %v; want the endpoints", q.At(0), q.At(1))
+	}
+	if got := q.Derivative(0); got != (Point{X: 2, Y: 4}) {
+		t.Errorf("Derivative(0) = %v; want (2, 4)", got)
+	}
+	if got := q.Tangent(0.5); got != (Point{X: 1, Y: 0}) {
+		t.Errorf("Tangent(0.5) = %v; want (1, 0)", got)
+	}
+	if got := q.Normal(0.5); got != (Point{X: 0, Y: 1}) {
+		t.Errorf("Normal(0.5) = %v; want (0, 1)", got)
+	}
+	// The derivative vanishes at the start, where the control point coincides with P0.
+	d := NewQuadBezier(Point{X: 0, Y: 0}, Point{X: 0, Y: 0}, Point{X: 3, Y: 4})
+	if got := d.Tangent(0); !pointAlmostEqual(got, Point{X: 0.6, Y: 0.8}, 1e-6) {
+		t.Errorf("Tangent(0) of a degenerate curve = %v; want (0.6, 0.8)", got)
+	}
+	// The derivative vanishes at the end, where the control point coincides with P2.
+	e := NewQuadBezier(Point{X: 0, Y: 0}, Point{X: 1, Y: 0}, Point{X: 1, Y: 0})
+	if got := e.Tangent(1); got != (Point{X: 1, Y: 0}) {
+		t.Errorf("Tangent(1) of a degenerate curve = %v; want (1, 0)", got)
+	}
+}
+
+// TestCubicBezier_At tests evaluation, derivatives and tangents of a cubic curve.
+func TestCubicBezier_At(t *testing.T) {
+	if got := arch.At(0.5); got != (Point{X: 2, Y: 3}) {
+		t.Errorf("At(0.5) = %v; want (2, 3)", got)
+	}
+	if got := arch.Derivative(0); got != (Point{X: 0, Y: 12}) {
+		t.Errorf("Derivative(0) = %v; want (0, 12)", got)
+	}
+	if got := arch.Tangent(1); got != (Point{X: 0, Y: -1}) {
+		t.Errorf("Tangent(1) = %v; want (0, -1)", got)
+	}
+	if got := arch.Normal(0); got != (Point{X: -1, Y: 0}) {
+		t.Errorf("Normal(0) = %v; want (-1, 0)", got)
+	}
+	d := NewCubicBezier(Point{X: 0, Y: 0}, Point{X: 0, Y: 0}, Point{X: 0, Y: 0}, Point{X: 0, Y: 5})
+	if got := d.Tangent(0); got != (Point{X: 0, Y: 1}) {
+		t.Errorf("Tangent(0) of a degenerate curve = %v; want (0, 1)", got)
+	}
+	if got := (CubicBezier{}).Tangent(0.5); got != (Point{}) {
+		t.Errorf("Tangent() of a point = %v; want zero", got)
+	}
+}
+
+// TestBezier_Split tests that both halves of a split curve follow the original curve.
+func TestBezier_Split(t *testing.T) {
+	const split = 0.3
+	left, right := arch.Split(split)
+	if left.P3 != right.P0 || left.P0 != arch.P0 || right.P3 != arch.P3 {
+		t.Fatalf("Split() = %v, %v; halves must share the split point", left, right)
+	}
+	for k := 0; k <= 10; k++ {
+		u := float32(k) / 10
+		if got, want := left.At(u), arch.At(u*split); !pointAlmostEqual(got, want, 1e-5) {
+			t.Errorf("left.At(%v) = %v; want %v", u, got, want)
+		}
+		if got, want := right.At(u), arch.At(split+u*(1-split)); !pointAlmostEqual(got, want, 1e-5) {
+			t.Errorf("right.At(%v) = %v; want %v", u, got, want)
+		}
+	}
+
+	q := NewQuadBezier(Point{X: 0, Y: 0}, Point{X: 1, Y: 2}, Point{X: 2, Y: 0})
+	ql, qr := q.Split(0.5)
+	if want := (QuadBezier{P0: q.P0, P1: Point{X: 0.5, Y: 1}, P2: Point{X: 1, Y: 1}}); ql != want {
+		t.Errorf("Split(0.5) left = %v; want %v", ql, want)
+	}
+	if want := (QuadBezier{P0: Point{X: 1, Y: 1}, P1: Point{X: 1.5, Y: 1}, P2: q.P2}); qr != want {
+		t.Errorf("Split(0.5) right = %v; want %v", qr, want)
+	}
+}
+
+// TestBezier_Bounds tests that the bounds are tight rather than the hull of the control points.
+func TestBezier_Bounds(t *testing.T) {
+	if got, want := arch.Bounds(), NewRect(0, 0, 4, 3); got != want {
+		t.Errorf("CubicBezier.Bounds() = %v; want %v", got, want)
+	}
+	q := NewQuadBezier(Point{X: 0, Y: 0}, Point{X: 1, Y: 2}, Point{X: 2, Y: 0})
+	if got, want := q.Bounds(), NewRect(0, 0, 2, 1); got != want {
+		t.Errorf("QuadBezier.Bounds() = %v; want %v", got, want)
+	}
+	// An S-shaped curve has extrema in both coordinates.
+	s := NewCubicBezier(Point{X: 0, Y: 0}, Point{X: 6, Y: 2}, Point{X: -2, Y: 2}, Point{X: 4, Y: 0})
+	got := s.Bounds()
+	for k := 0; k <= 1000; k++ {
+		p := s.At(float32(k) / 1000)
+		if p.X < got.Min.X-1e-5 || p.X > got.Max.X+1e-5 || p.Y < got.Min.Y-1e-5 || p.Y > got.Max.Y+1e-5 {
+			t.Fatalf("Bounds() = %v does not contain %v", got, p)
+		}
+	}
+	if got.Max.X >= 4.5 || got.Min.X <= -0.5 || got.Max.Y != 1.5 {
+		t.Errorf("Bounds() = %v; want tight bounds", got)
+	}
+}
+
+// TestQuadBezier_Elevate tests that degree elevation preserves the curve.
+func TestQuadBezier_Elevate(t *testing.T) {
+	q := NewQuadBezier(Point{X: 0, Y: 0}, Point{X: 3, Y: 6}, Point{X: 6, Y: 0})
+	c := q.Elevate()
+	if want := NewCubicBezier(Point{X: 0, Y: 0}, Point{X: 2, Y: 4}, Point{X: 4, Y: 4}, Point{X: 6, Y: 0}); c != want {
+		t.Errorf("Elevate() = %v; want %v", c, want)
+	}
+	for k := 0; k <= 10; k++ {
+		u := float32(k) / 10
+		if !pointAlmostEqual(c.At(u), q.At(u), 1e-5) {
+			t.Errorf("Elevate().At(%v) = %v; want %v", u, c.At(u), q.At(u))
+		}
+	}
+}
+
+// TestBezier_ClosestPoint tests the nearest point on a curve against dense sampling.
+func TestBezier_ClosestPoint(t *testing.T) {
+	if got := arch.Project(Point{X: 2, Y: 10}); got != 0.5 {
+		t.Errorf("Project() above the peak = %v; want 0.5", got)
+	}
+	if got := arch.ClosestPoint(Point{X: -5, Y: -1}); got != arch.P0 {
+		t.Errorf("ClosestPoint() before the start = %v; want %v", got, arch.P0)
+	}
+	q := NewQuadBezier(Point{X: 0, Y: 0}, Point{X: 1, Y: 2}, Point{X: 2, Y: 0})
+	for _, p := range []Point{{X: 1, Y: 0}, {X: 0.5, Y: 2}, {X: 3, Y: -1}, {X: 1, Y: 0.9}} {
+		for _, c := range []struct {
+			name    string
+			closest Point
+			at      func(float32) Point
+		}{
+			{"cubic", arch.ClosestPoint(p), arch.At},
+			{"quad", q.ClosestPoint(p), q.At},
+		} {
+			best := float32(math.Inf(1))
+			for k := 0; k <= 10000; k++ {
+				best = min(best, c.at(float32(k)/10000).Distance(p))
+			}
+			if got := c.closest.Distance(p); got > best+1e-4 {
+				t.Errorf("%s: ClosestPoint(%v) is at distance %v; want %v", c.name, p, got, best)
+			}
+		}
+	}
+}
+
+// TestBezier_Transform tests that transforming the control points transforms the curve.
+func TestBezier_Transform(t *testing.T) {
+	a := Affine2D{}.Rotate(Point{}, 0.7).Scale(Point{}, Point{X: 2, Y: 0.5}).Offset(Point{X: 3, Y: -1})
+	c := arch.Transform(a)
+	q := NewQuadBezier(Point{X: 0, Y: 0}, Point{X: 1, Y: 2}, Point{X: 2, Y: 0})
+	qt := q.Transform(a)
+	for k := 0; k <= 10; k++ {
+		u := float32(k) / 10
+		if got, want := c.At(u), a.Transform(arch.At(u)); !pointAlmostEqual(got, want, 1e-5) {
+			t.Errorf("CubicBezier.Transform().At(%v) = %v; want %v", u, got, want)
+		}
+		if got, want := qt.At(u), a.Transform(q.At(u)); !pointAlmostEqual(got, want, 1e-5) {
+			t.Errorf("QuadBezier.Transform().At(%v) = %v; want %v", u, got, want)
+		}
+	}
+}
@@ -0,0 +1,311 @@
+package tochka
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+// binaryVersion is the version byte that starts every binary encoding.
+const binaryVersion = 1
+
+var (
+	// ErrUnsupportedVersion is returned when binary data was written by an unknown version of the format.
+	ErrUnsupportedVersion = errors.New("unsupported binary encoding version")
+	// ErrInvalidEncoding is returned when binary data is truncated or malformed.
+	ErrInvalidEncoding = errors.New("invalid binary encoding")
+	// ErrInvalidOptions is returned when BinaryOptions describe an impossible encoding.
+	ErrInvalidOptions = errors.New("invalid binary encoding options")
+	// ErrQuantizationRange is returned when a coordinate does not fit the quantized integer type.
+	ErrQuantizationRange = errors.New("coordinate out of quantization range")
+)
+
+// Quantization selects how the coordinates of a point sequence are stored.
+type Quantization uint8
+
+const (
+	// QuantizeNone stores coordinates as float32.
+	QuantizeNone Quantization = iota
+	// QuantizeInt16 rounds scaled coordinates to int16.
+	QuantizeInt16
+	// QuantizeInt32 rounds scaled coordinates to int32.
+	QuantizeInt32
+)
+
+// deltaFlag marks delta encoding in the format byte of a point sequence.
+const deltaFlag = 0x80
+
+// BinaryOptions configures the encoding of point sequences by AppendPoints.
+// The zero value stores coordinates as float32 without loss.
+type BinaryOptions struct {
+	// Quantization selects the coordinate type.
+	Quantization Quantization
+	// Scale multiplies coordinates before they are rounded to integers, so 1/Scale
+	// is the quantization step. Zero means 1. Ignored by QuantizeNone.
+	Scale float32
+	// Delta stores every point as the difference from the previous one in zig-zag
+	// varints, which shrinks polylines with short edges. It requires quantization.
+	Delta bool
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. The point is written as a version
+// byte followed by X and Y as little-endian float32, 9 bytes in total.
+func (p Point) MarshalBinary() ([]byte, error) {
+	return p.AppendBinary(make([]byte, 0, 9))
+}
+
+// AppendBinary appends the encoding of MarshalBinary to b.
+func (p Point) AppendBinary(b []byte) ([]byte, error) {
+	b = append(b, binaryVersion)
+	return appendFloat32s(b, p.X, p.Y), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler for the encoding of MarshalBinary.
+func (p *Point) UnmarshalBinary(data []byte) error {
+	v, err := binaryPayload(data, 8)
+	if err != nil {
+		return err
+	}
+	*p = Point{X: float32At(v, 0), Y: float32At(v, 1)}
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. The transformation is written as a
+// version byte followed by sx-1, hx, ox, hy, sy-1, oy as little-endian float32, 25 bytes
+// in total. Storing the diagonal as an offset from one keeps the encoding exact and
+// makes the identity encode to zeros.
+func (a Affine2D) MarshalBinary() ([]byte, error) {
+	return a.AppendBinary(make([]byte, 0, 25))
+}
+
+// AppendBinary appends the encoding of MarshalBinary to b.
+func (a Affine2D) AppendBinary(b []byte) ([]byte, error) {
+	b = append(b, binaryVersion)
+	return appendFloat32s(b, a.a, a.b, a.c, a.d, a.e, a.f), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler for the encoding of MarshalBinary.
+func (a *Affine2D) UnmarshalBinary(data []byte) error {
+	v, err := binaryPayload(data, 24)
+	if err != nil {
+		return err
+	}
+	*a = Affine2D{
+		a: float32At(v, 0), b: float32At(v, 1), c: float32At(v, 2),
+		d: float32At(v, 3), e: float32At(v, 4), f: float32At(v, 5),
+	}
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler using AppendPoints with the zero BinaryOptions.
+func (r Ring) MarshalBinary() ([]byte, error) {
+	return AppendPoints(nil, r, BinaryOptions{})
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler using DecodePoints,
+// so it accepts every encoding written by AppendPoints.
+func (r *Ring) UnmarshalBinary(data []byte) error {
+	pts, err := DecodePoints(data)
+	if err != nil {
+		return err
+	}
+	*r = pts
+	return nil
+}
+
+// AppendPoints appends the encoding of a point sequence to dst. The encoding starts with
+// a version byte and a format byte, followed by the scale as a little-endian float32 when
+// quantized and the number of points as a uvarint. The points follow as float32 or int16
+// or int32 pairs, or as zig-zag varint differences when opts.Delta is set.
+//
+// Quantized coordinates are rounded to the nearest multiple of 1/Scale. Returns
+// ErrQuantizationRange if a coordinate does not fit the integer type, and
+// ErrInvalidOptions if the options are inconsistent; dst is returned unchanged on error.
+func AppendPoints(dst []byte, pts []Point, opts BinaryOptions) ([]byte, error) {
+	format := byte(opts.Quantization)
+	if opts.Quantization > QuantizeInt32 || opts.Delta && opts.Quantization == QuantizeNone {
+		return dst, ErrInvalidOptions
+	}
+	scale := opts.Scale
+	if scale == 0 {
+		scale = 1
+	}
+	if !(scale > 0) || math.IsInf(float64(scale), 0) {
+		return dst, ErrInvalidOptions
+	}
+	if opts.Delta {
+		format |= deltaFlag
+	}
+
+	b := append(dst, binaryVersion, format)
+	if opts.Quantization != QuantizeNone {
+		b = appendFloat32s(b, scale)
+	}
+	b = binary.AppendUvarint(b, uint64(len(pts)))
+	if opts.Quantization == QuantizeNone {
+		for _, p := range pts {
+			b = appendFloat32s(b, p.X, p.Y)
+		}
+		return b, nil
+	}
+
+	lo, hi := quantizationRange(opts.Quantization)
+	var px, py int64
+	for _, p := range pts {
+		x, okX := quantize(p.X, scale, lo, hi)
+		y, okY := quantize(p.Y, scale, lo, hi)
+		if !okX || !okY {
+			return dst, ErrQuantizationRange
+		}
+		switch {
+		case opts.Delta:
+			b = binary.AppendVarint(b, x-px)
+			b = binary.AppendVarint(b, y-py)
+			px, py = x, y
+		case opts.Quantization == QuantizeInt16:
+			b = binary.LittleEndian.AppendUint16(b, uint16(x))
+			b = binary.LittleEndian.AppendUint16(b, uint16(y))
+		default:
+			b = binary.LittleEndian.AppendUint32(b, uint32(x))
+			b = binary.LittleEndian.AppendUint32(b, uint32(y))
+		}
+	}
+	return b, nil
+}
+
+// DecodePoints decodes a point sequence written by AppendPoints. The data must hold
+// exactly one sequence, and varints must be minimally encoded. Returns
+// ErrUnsupportedVersion for data of an unknown version and ErrInvalidEncoding for
+// truncated or malformed data.
+func DecodePoints(data []byte) ([]Point, error) {
+	if len(data) < 2 {
+		return nil, ErrInvalidEncoding
+	}
+	if data[0] != binaryVersion {
+		return nil, ErrUnsupportedVersion
+	}
+	q, delta := Quantization(data[1]&^deltaFlag), data[1]&deltaFlag != 0
+	if q > QuantizeInt32 || delta && q == QuantizeNone {
+		return nil, ErrInvalidEncoding
+	}
+	data = data[2:]
+	scale := float32(1)
+	if q != QuantizeNone {
+		if len(data) < 4 {
+			return nil, ErrInvalidEncoding
+		}
+		scale = float32At(data, 0)
+		if !(scale > 0) || math.IsInf(float64(scale), 0) {
+			return nil, ErrInvalidEncoding
+		}
+		data = data[4:]
+	}
+	n, k := binary.Uvarint(data)
+	if k <= 0 || k != len(binary.AppendUvarint(nil, n)) {
+		return nil, ErrInvalidEncoding
+	}
+	data = data[k:]
+
+	// Every point takes at least two bytes, which bounds the allocation by the input size.
+	size := 8
+	if q == QuantizeInt16 {
+		size = 4
+	}
+	if delta {
+		if n > uint64(len(data))/2 {
+			return nil, ErrInvalidEncoding
+		}
+	} else if n != uint64(len(data)/size) || len(data)%size != 0 {
+		return nil, ErrInvalidEncoding
+	}
+
+	pts := make([]Point, n)
+	switch {
+	case q == QuantizeNone:
+		for i := range pts {
+			pts[i] = Point{X: float32At(data, 2*i), Y: float32At(data, 2*i+1)}
+		}
+		return pts, nil
+	case !delta:
+		for i := range pts {
+			var x, y int64
+			if q == QuantizeInt16 {
+				x = int64(int16(binary.LittleEndian.Uint16(data[4*i:])))
+				y = int64(int16(binary.LittleEndian.Uint16(data[4*i+2:])))
+			} else {
+				x = int64(int32(binary.LittleEndian.Uint32(data[8*i:])))
+				y = int64(int32(binary.LittleEndian.Uint32(data[8*i+4:])))
+			}
+			pts[i] = Point{X: dequantize(x, scale), Y: dequantize(y, scale)}
+		}
+		return pts, nil
+	}
+
+	lo, hi := quantizationRange(q)
+	var x, y int64
+	for i := range pts {
+		var d [2]int64
+		for j := range d {
+			v, k := binary.Varint(data)
+			if k <= 0 || k != len(binary.AppendVarint(nil, v)) {
+				return nil, ErrInvalidEncoding
+			}
+			d[j], data = v, data[k:]
+		}
+		x, y = x+d[0], y+d[1]
+		if x < lo || x > hi || y < lo || y > hi {
+			return nil, ErrInvalidEncoding
+		}
+		pts[i] = Point{X: dequantize(x, scale), Y: dequantize(y, scale)}
+	}
+	if len(data) != 0 {
+		return nil, ErrInvalidEncoding
+	}
+	return pts, nil
+}
+
+// binaryPayload checks the version byte and length of a fixed-size encoding and returns its payload.
+func binaryPayload(data []byte, size int) ([]byte, error) {
+	if len(data) > 0 && data[0] != binaryVersion {
+		return nil, ErrUnsupportedVersion
+	}
+	if len(data) != 1+size {
+		return nil, ErrInvalidEncoding
+	}
+	return data[1:], nil
+}
+
+// appendFloat32s appends the values as little-endian float32.
+func appendFloat32s(b []byte, v ...float32) []byte {
+	for _, x := range v {
+		b = binary.LittleEndian.AppendUint32(b, math.Float32bits(x))
+	}
+	return b
+}
+
+// float32At returns the k-th little-endian float32 of b.
+func float32At(b []byte, k int) float32 {
+	return math.Float32frombits(binary.LittleEndian.Uint32(b[4*k:]))
+}
+
+// quantizationRange returns the range of integers representable by q.
+func quantizationRange(q Quantization) (lo, hi int64) {
+	if q == QuantizeInt16 {
+		return math.MinInt16, math.MaxInt16
+	}
+	return math.MinInt32, math.MaxInt32
+}
+
+// quantize rounds v*scale to the nearest integer and reports whether it lies in [lo, hi].
+func quantize(v, scale float32, lo, hi int64) (int64, bool) {
+	r := math.Round(float64(v) * float64(scale))
+	if !(r >= float64(lo) && r <= float64(hi)) {
+		return 0, false
+	}
+	return int64(r), true
+}
+
+// dequantize converts a quantized coordinate back to the original scale.
+func dequantize(q int64, scale float32) float32 {
+	return float32(float64(q) / float64(scale))
+}
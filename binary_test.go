@@ -0,0 +1,210 @@
+package tochka
+
+import (
+	"bytes"
+	"encoding"
+	"errors"
+	"math"
+	"testing"
+)
+
+// Compile-time checks that the types implement the binary encoding interfaces.
+var (
+	_ encoding.BinaryMarshaler   = Point{}
+	_ encoding.BinaryUnmarshaler = (*Point)(nil)
+	_ encoding.BinaryMarshaler   = Affine2D{}
+	_ encoding.BinaryUnmarshaler = (*Affine2D)(nil)
+	_ encoding.BinaryMarshaler   = Ring(nil)
+	_ encoding.BinaryUnmarshaler = (*Ring)(nil)
+)
+
+// TestPoint_MarshalBinary tests the exact layout and round trip of a point.
+func TestPoint_MarshalBinary(t *testing.T) {
+	p := Point{X: 1, Y: -2}
+	data, err := p.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary() error = %v", err)
+	}
+	want := []byte{1, 0x00, 0x00, 0x80, 0x3f, 0x00, 0x00, 0x00, 0xc0}
+	if !bytes.Equal(data, want) {
+		t.Errorf("MarshalBinary() = % x; want % x", data, want)
+	}
+	var q Point
+	if err := q.UnmarshalBinary(data); err != nil || q != p {
+		t.Errorf("UnmarshalBinary() = %v, %v; want %v", q, err, p)
+	}
+	if err := q.UnmarshalBinary(data[:8]); !errors.Is(err, ErrInvalidEncoding) {
+		t.Errorf("UnmarshalBinary() of truncated data error = %v; want ErrInvalidEncoding", err)
+	}
+	if err := q.UnmarshalBinary(append([]byte{2}, data[1:]...)); !errors.Is(err, ErrUnsupportedVersion) {
+		t.Errorf("UnmarshalBinary() of version 2 error = %v; want ErrUnsupportedVersion", err)
+	}
+}
+
+// TestAffine2D_MarshalBinary tests that transformations round-trip exactly.
+func TestAffine2D_MarshalBinary(t *testing.T) {
+	data, _ := Affine2D{}.MarshalBinary()
+	if want := append([]byte{1}, make([]byte, 24)...); !bytes.Equal(data, want) {
+		t.Errorf("MarshalBinary() of the identity = % x; want % x", data, want)
+	}
+	// A diagonal close to one is kept exactly, unlike a round trip through Elems.
+	a := Affine2D{a: 1e-9, b: 0.5, c: -3, d: 0.25, e: -1e-9, f: 1e6}
+	data, err := a.MarshalBinary()
+	if err != nil || len(data) != 25 {
+		t.Fatalf("MarshalBinary() = % x, %v; want 25 bytes", data, err)
+	}
+	var b Affine2D
+	if err := b.UnmarshalBinary(data); err != nil || b != a {
+		t.Errorf("UnmarshalBinary() = %v, %v; want %v", b, err, a)
+	}
+	if err := b.UnmarshalBinary(nil); !errors.Is(err, ErrInvalidEncoding) {
+		t.Errorf("UnmarshalBinary(nil) error = %v; want ErrInvalidEncoding", err)
+	}
+}
+
+// TestAppendPoints tests every encoding of a polyline and its size.
+func TestAppendPoints(t *testing.T) {
+	pts := []Point{{X: 100, Y: 200}, {X: 100.5, Y: 199.75}, {X: 101, Y: 199}, {X: -3.25, Y: 0}}
+	tests := []struct {
+		name string
+		opts BinaryOptions
+		size int
+		eps  float32
+	}{
+		{"float32", BinaryOptions{}, 3 + 4*8, 1e-6},
+		{"int16", BinaryOptions{Quantization: QuantizeInt16, Scale: 4}, 7 + 4*4, 1e-6},
+		{"int32", BinaryOptions{Quantization: QuantizeInt32, Scale: 4}, 7 + 4*8, 1e-6},
+		{"int16 delta", BinaryOptions{Quantization: QuantizeInt16, Scale: 4, Delta: true}, 7 + 4 + 2 + 2 + 4, 1e-6},
+		{"coarse", BinaryOptions{Quantization: QuantizeInt32, Delta: true}, 0, 0.51},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prefix := []byte("hdr")
+			data, err := AppendPoints(prefix, pts, tt.opts)
+			if err != nil {
+				t.Fatalf("AppendPoints() error = %v", err)
+			}
+			if !bytes.HasPrefix(data, prefix) {
+				t.Fatalf("AppendPoints() did not append to dst")
+			}
+			data = data[len(prefix):]
+			if tt.size > 0 && len(data) != tt.size {
+				t.Errorf("AppendPoints() = %d bytes; want %d", len(data), tt.size)
+			}
+			got, err := DecodePoints(data)
+			if err != nil {
+				t.Fatalf("DecodePoints() error = %v", err)
+			}
+			if len(got) != len(pts) {
+				t.Fatalf("DecodePoints() = %v; want %v", got, pts)
+			}
+			for k := range pts {
+				if !pointAlmostEqual(got[k], pts[k], tt.eps) {
+					t.Errorf("point %d = %v; want %v", k, got[k], pts[k])
+				}
+			}
+		})
+	}
+}
+
+// TestAppendPoints_Errors tests invalid options and coordinates outside the quantization range.
+func TestAppendPoints_Errors(t *testing.T) {
+	pts := []Point{{X: 1, Y: 2}, {X: 5000, Y: 0}}
+	tests := []struct {
+		opts BinaryOptions
+		err  error
+	}{
+		{BinaryOptions{Delta: true}, ErrInvalidOptions},
+		{BinaryOptions{Quantization: 3}, ErrInvalidOptions},
+		{BinaryOptions{Quantization: QuantizeInt16, Scale: -1}, ErrInvalidOptions},
+		{BinaryOptions{Quantization: QuantizeInt16, Scale: float32(math.NaN())}, ErrInvalidOptions},
+		{BinaryOptions{Quantization: QuantizeInt16, Scale: 10}, ErrQuantizationRange},
+	}
+	for _, tt := range tests {
+		dst := []byte{9}
+		got, err := AppendPoints(dst, pts, tt.opts)
+		if !errors.Is(err, tt.err) || !bytes.Equal(got, dst) {
+			t.Errorf("AppendPoints(%+v) = % x, %v; want dst unchanged and %v", tt.opts, got, err, tt.err)
+		}
+	}
+	nan := []Point{{X: float32(math.NaN())}}
+	if _, err := AppendPoints(nil, nan, BinaryOptions{Quantization: QuantizeInt32}); !errors.Is(err, ErrQuantizationRange) {
+		t.Errorf("AppendPoints() of NaN error = %v; want ErrQuantizationRange", err)
+	}
+}
+
+// TestRing_MarshalBinary tests the binary round trip of a ring, including an empty one.
+func TestRing_MarshalBinary(t *testing.T) {
+	for _, r := range []Ring{square, {}} {
+		data, err := r.MarshalBinary()
+		if err != nil {
+			t.Fatalf("MarshalBinary() error = %v", err)
+		}
+		var got Ring
+		if err := got.UnmarshalBinary(data); err != nil || len(got) != len(r) {
+			t.Fatalf("UnmarshalBinary() = %v, %v; want %v", got, err, r)
+		}
+		for k := range r {
+			if got[k] != r[k] {
+				t.Errorf("point %d = %v; want %v", k, got[k], r[k])
+			}
+		}
+	}
+}
+
+// FuzzDecodePoints tests that arbitrary input never panics or over-allocates,
+// and that unquantized sequences re-encode to the same bytes.
+func FuzzDecodePoints(f *testing.F) {
+	pts := []Point{{X: 1, Y: 2}, {X: -3, Y: 4.5}, {X: 1e4, Y: -1e4}}
+	for _, opts := range []BinaryOptions{
+		{},
+		{Quantization: QuantizeInt16, Scale: 2},
+		{Quantization: QuantizeInt32, Scale: 100},
+		{Quantization: QuantizeInt32, Scale: 0.5, Delta: true},
+	} {
+		data, err := AppendPoints(nil, pts, opts)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+	}
+	f.Add([]byte{1, 0x81, 0, 0, 0x80, 0x3f, 0xff, 0xff, 0xff, 0xff, 0x0f})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		pts, err := DecodePoints(data)
+		if err != nil {
+			return
+		}
+		if len(pts) > len(data) {
+			t.Fatalf("DecodePoints() = %d points from %d bytes", len(pts), len(data))
+		}
+		if data[1] == byte(QuantizeNone) {
+			again, err := AppendPoints(nil, pts, BinaryOptions{})
+			if err != nil || !bytes.Equal(again, data) {
+				t.Errorf("re-encoding = % x, %v; want % x", again, err, data)
+			}
+		}
+	})
+}
+
+// FuzzUnmarshalBinary tests that the fixed-size decoders reject malformed input without panicking.
+func FuzzUnmarshalBinary(f *testing.F) {
+	p, _ := Point{X: 1, Y: 2}.MarshalBinary()
+	a, _ := NewAffine2D(1, 2, 3, 4, 5, 6).MarshalBinary()
+	f.Add(p)
+	f.Add(a)
+	f.Add([]byte{})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var p Point
+		if err := p.UnmarshalBinary(data); err == nil {
+			if again, _ := p.MarshalBinary(); !bytes.Equal(again, data) {
+				t.Errorf("Point re-encoding = % x; want % x", again, data)
+			}
+		}
+		var a Affine2D
+		if err := a.UnmarshalBinary(data); err == nil {
+			if again, _ := a.MarshalBinary(); !bytes.Equal(again, data) {
+				t.Errorf("Affine2D re-encoding = % x; want % x", again, data)
+			}
+		}
+	})
+}
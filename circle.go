@@ -0,0 +1,112 @@
+package tochka
+
+import "math"
+
+// Circle represents a circle with the specified center and radius.
+type Circle struct {
+	Center Point
+	Radius float32
+}
+
+// NewCircle creates a circle with the specified center and radius.
+func NewCircle(center Point, radius float32) Circle {
+	return Circle{Center: center, Radius: radius}
+}
+
+// Ellipse returns the circle as an ellipse with equal radii.
+func (c Circle) Ellipse() Ellipse {
+	return Ellipse{Center: c.Center, Radii: Point{X: c.Radius, Y: c.Radius}}
+}
+
+// Area returns the area of the circle.
+func (c Circle) Area() float32 {
+	r := float64(c.Radius)
+	return float32(math.Pi * r * r)
+}
+
+// Perimeter returns the circumference of the circle.
+func (c Circle) Perimeter() float32 {
+	return float32(2 * math.Pi * math.Abs(float64(c.Radius)))
+}
+
+// Contains reports whether the point p lies inside the circle or on its boundary.
+func (c Circle) Contains(p Point) bool {
+	d := p.To64().Sub(c.Center.To64())
+	r := float64(c.Radius)
+	return d.Dot(d) <= r*r
+}
+
+// ClosestPoint returns the point on the circle closest to p.
+// For the center itself, the point at angle zero is returned.
+func (c Circle) ClosestPoint(p Point) Point {
+	d := p.To64().Sub(c.Center.To64())
+	m := d.Magnitude()
+	if m == 0 {
+		return Point{X: c.Center.X + c.Radius, Y: c.Center.Y}
+	}
+	return c.Center.To64().Add(d.Mul(math.Abs(float64(c.Radius)) / m)).To32()
+}
+
+// Distance returns the distance between p and the circle.
+func (c Circle) Distance(p Point) float32 {
+	return float32(math.Abs(p.To64().Distance(c.Center.To64()) - math.Abs(float64(c.Radius))))
+}
+
+// Bounds returns the axis-aligned bounds of the circle.
+func (c Circle) Bounds() Rect {
+	r := float32(math.Abs(float64(c.Radius)))
+	return Rect{
+		Min: Point{X: c.Center.X - r, Y: c.Center.Y - r},
+		Max: Point{X: c.Center.X + r, Y: c.Center.Y + r},
+	}
+}
+
+// Transform applies the transformation a to the circle. Under shear or non-uniform scaling
+// a circle becomes an ellipse, so the exact image is returned as an Ellipse.
+func (c Circle) Transform(a Affine2D) Ellipse {
+	return c.Ellipse().Transform(a)
+}
+
+// IntersectLine returns the intersection points of the circle and the line, ordered along the line.
+// A tangent line yields a single point.
+func (c Circle) IntersectLine(l Line) []Point {
+	return c.Ellipse().IntersectLine(l)
+}
+
+// IntersectSegment returns the intersection points of the circle and the segment, ordered from A to B.
+func (c Circle) IntersectSegment(s Segment) []Point {
+	return c.Ellipse().IntersectSegment(s)
+}
+
+// IntersectCircle returns the intersection points of two circles. Tangent circles yield
+// a single point. Concentric circles have either no or infinitely many common points,
+// in both cases no points are returned.
+func (c Circle) IntersectCircle(o Circle) []Point {
+	p, q := c.Center.To64(), o.Center.To64()
+	r1, r2 := math.Abs(float64(c.Radius)), math.Abs(float64(o.Radius))
+	d := p.Distance(q)
+	if d == 0 {
+		return nil
+	}
+	// a is the distance from p to the chord through the intersection points along p→q,
+	// h is half the length of the chord.
+	a := (r1*r1 - r2*r2 + d*d) / (2 * d)
+	h2 := r1*r1 - a*a
+	tol := parallelEpsilon * r1 * r1
+	if h2 < -tol {
+		return nil
+	}
+	u := q.Sub(p).Mul(1 / d)
+	mid := p.Add(u.Mul(a))
+	if h2 <= tol {
+		return []Point{mid.To32()}
+	}
+	h := math.Sqrt(h2)
+	n := Point64{X: -u.Y, Y: u.X}
+	return []Point{mid.Sub(n.Mul(h)).To32(), mid.Add(n.Mul(h)).To32()}
+}
+
+// IntersectEllipse returns the intersection points of the circle and an ellipse.
+func (c Circle) IntersectEllipse(e Ellipse) []Point {
+	return c.Ellipse().IntersectEllipse(e)
+}
@@ -0,0 +1,95 @@
+package tochka
+
+import (
+	"math"
+	"testing"
+)
+
+// TestCircle_Measures tests the area, perimeter and bounds of a circle.
+func TestCircle_Measures(t *testing.T) {
+	c := NewCircle(Point{X: 1, Y: 2}, 3)
+	if !almostEqual(c.Area(), 9*math.Pi, 1e-5) || !almostEqual(c.Perimeter(), 6*math.Pi, 1e-5) {
+		t.Errorf("Area() = %v, Perimeter() = %v", c.Area(), c.Perimeter())
+	}
+	if got, want := c.Bounds(), NewRect(-2, -1, 4, 5); got != want {
+		t.Errorf("Bounds() = %v; want %v", got, want)
+	}
+}
+
+// TestCircle_Nearest tests containment, closest point and distance.
+func TestCircle_Nearest(t *testing.T) {
+	c := NewCircle(Point{X: 0, Y: 0}, 5)
+	if !c.Contains(Point{X: 3, Y: 4}) || c.Contains(Point{X: 3, Y: 4.1}) {
+		t.Errorf("Contains() failed")
+	}
+	if got := c.ClosestPoint(Point{X: 0, Y: 10}); got != (Point{X: 0, Y: 5}) {
+		t.Errorf("ClosestPoint() = %v; want (0, 5)", got)
+	}
+	if got := c.Distance(Point{X: 0, Y: 2}); got != 3 {
+		t.Errorf("Distance() = %v; want 3", got)
+	}
+}
+
+// TestCircle_IntersectCircle tests crossing, tangent and disjoint circles.
+func TestCircle_IntersectCircle(t *testing.T) {
+	c := NewCircle(Point{X: 0, Y: 0}, 5)
+	got := c.IntersectCircle(NewCircle(Point{X: 8, Y: 0}, 5))
+	if len(got) != 2 || !pointAlmostEqual(got[0], Point{X: 4, Y: -3}, 1e-5) || !pointAlmostEqual(got[1], Point{X: 4, Y: 3}, 1e-5) {
+		t.Errorf("IntersectCircle() = %v; want (4, -3), (4, 3)", got)
+	}
+	got = c.IntersectCircle(NewCircle(Point{X: 7, Y: 0}, 2))
+	if len(got) != 1 || !pointAlmostEqual(got[0], Point{X: 5, Y: 0}, 1e-5) {
+		t.Errorf("IntersectCircle() tangent = %v; want (5, 0)", got)
+	}
+	if got := c.IntersectCircle(NewCircle(Point{X: 20, Y: 0}, 2)); got != nil {
+		t.Errorf("IntersectCircle() disjoint = %v; want none", got)
+	}
+	if got := c.IntersectCircle(NewCircle(Point{X: 0, Y: 0}, 5)); got != nil {
+		t.Errorf("IntersectCircle() coincident = %v; want none", got)
+	}
+}
+
+// TestCircle_IntersectLine tests secant, tangent and missing lines and segments.
+func TestCircle_IntersectLine(t *testing.T) {
+	c := NewCircle(Point{X: 0, Y: 0}, 5)
+	got := c.IntersectLine(Line{Origin: Point{X: -10, Y: 3}, Dir: Point{X: 1, Y: 0}})
+	if len(got) != 2 || !pointAlmostEqual(got[0], Point{X: -4, Y: 3}, 1e-5) || !pointAlmostEqual(got[1], Point{X: 4, Y: 3}, 1e-5) {
+		t.Errorf("IntersectLine() = %v; want (-4, 3), (4, 3)", got)
+	}
+	got = c.IntersectLine(Line{Origin: Point{X: 5, Y: 0}, Dir: Point{X: 0, Y: 1}})
+	if len(got) != 1 || !pointAlmostEqual(got[0], Point{X: 5, Y: 0}, 1e-5) {
+		t.Errorf("IntersectLine() tangent = %v; want (5, 0)", got)
+	}
+	if got := c.IntersectLine(Line{Origin: Point{X: 6, Y: 0}, Dir: Point{X: 0, Y: 1}}); got != nil {
+		t.Errorf("IntersectLine() missing = %v; want none", got)
+	}
+	got = c.IntersectSegment(NewSegment(Point{X: 0, Y: 3}, Point{X: 10, Y: 3}))
+	if len(got) != 1 || !pointAlmostEqual(got[0], Point{X: 4, Y: 3}, 1e-5) {
+		t.Errorf("IntersectSegment() = %v; want (4, 3)", got)
+	}
+}
+
+// TestCircle_Transform tests that a circle under non-uniform transformations becomes an exact ellipse.
+func TestCircle_Transform(t *testing.T) {
+	c := NewCircle(Point{X: 1, Y: 1}, 2)
+	a := NewAffine2D(3, 0, 5, 0, 1, -1)
+	e := c.Transform(a)
+	want := NewEllipse(Point{X: 8, Y: 0}, 6, 2, 0)
+	if !ellipseAlmostEqual(e, want, 1e-5) {
+		t.Errorf("Transform() = %+v; want %+v", e, want)
+	}
+
+	// Every point of the transformed circle must lie on the resulting ellipse.
+	shear := Affine2D{}.Shear(Point{}, 0.6, 0).Rotate(Point{}, 0.3).Offset(Point{X: 2, Y: -4})
+	e = c.Transform(shear)
+	for k := 0; k < 16; k++ {
+		theta := float64(k) * math.Pi / 8
+		p := shear.Transform(c.Center.Add(Point{X: float32(math.Cos(theta)), Y: float32(math.Sin(theta))}.Mul(c.Radius)))
+		if d := e.Distance(p); d > 1e-4 {
+			t.Errorf("transformed point %v lies %v away from %+v", p, d, e)
+		}
+	}
+	if !almostEqual(e.Area(), c.Area()*shear.Determinant(), 1e-4) {
+		t.Errorf("Area() = %v; want %v", e.Area(), c.Area()*shear.Determinant())
+	}
+}
@@ -0,0 +1,85 @@
+package tochka
+
+import "math"
+
+// Decomposition holds the components of an affine transformation.
+// The transformation is recovered by applying, in order, the scale, the skew along X,
+// the rotation and the translation, which is the translate(), rotate(), skewX(), scale()
+// sequence used by SVG and CSS transforms.
+type Decomposition struct {
+	// Translation is the offset applied last.
+	Translation Point
+	// Rotation is the rotation angle in radians, in the range (-π, π].
+	Rotation float32
+	// Scale holds the scaling factors along X and Y. A reflection is represented
+	// by a negative Y factor.
+	Scale Point
+	// Skew is the shear angle along X in radians, in the range (-π/2, π/2).
+	Skew float32
+}
+
+// Decompose splits the transformation into translation, rotation, scale and skew components.
+// Compose reverses the operation. For a degenerate transformation whose first column is zero
+// the rotation is taken from the second column and the skew is zero.
+func (a Affine2D) Decompose() Decomposition {
+	sx, hx, ox, hy, sy, oy := a.To64().Elems()
+
+	var rotation, scaleX, scaleY, skew float64
+	scaleX = math.Hypot(sx, hy)
+	if scaleX != 0 {
+		rotation = math.Atan2(hy, sx)
+		cos, sin := sx/scaleX, hy/scaleX
+		// Project the second column onto the rotated frame.
+		scaleY = (sx*sy - hx*hy) / scaleX
+		if scaleY != 0 {
+			skew = math.Atan((hx*cos + sy*sin) / scaleY)
+		}
+	} else {
+		scaleY = math.Hypot(hx, sy)
+		if scaleY != 0 {
+			rotation = math.Atan2(-hx, sy)
+		}
+	}
+	return Decomposition{
+		Translation: Point{X: float32(ox), Y: float32(oy)},
+		Rotation:    float32(rotation),
+		Scale:       Point{X: float32(scaleX), Y: float32(scaleY)},
+		Skew:        float32(skew),
+	}
+}
+
+// Compose builds the affine transformation described by the decomposition.
+func (d Decomposition) Compose() Affine2D {
+	sin, cos := math.Sincos(float64(d.Rotation))
+	k := math.Tan(float64(d.Skew))
+	scaleX, scaleY := float64(d.Scale.X), float64(d.Scale.Y)
+	return NewAffine2D64(
+		scaleX*cos, scaleY*(cos*k-sin), float64(d.Translation.X),
+		scaleX*sin, scaleY*(sin*k+cos), float64(d.Translation.Y),
+	).To32()
+}
+
+// Interpolate returns the transformation between a and b at parameter t, where t = 0 yields a
+// and t = 1 yields b. Both transformations are decomposed; translation, scale and skew are
+// interpolated linearly and rotation is interpolated along the shortest arc before recomposing,
+// so intermediate transformations do not suffer the distortion of element-wise interpolation.
+func Interpolate(a, b Affine2D, t float32) Affine2D {
+	da, db := a.Decompose(), b.Decompose()
+	delta := math.Remainder(float64(db.Rotation)-float64(da.Rotation), 2*math.Pi)
+	return Decomposition{
+		Translation: lerpPoint(da.Translation, db.Translation, t),
+		Rotation:    da.Rotation + float32(delta)*t,
+		Scale:       lerpPoint(da.Scale, db.Scale, t),
+		Skew:        lerp(da.Skew, db.Skew, t),
+	}.Compose()
+}
+
+// lerp linearly interpolates between a and b at parameter t.
+func lerp(a, b, t float32) float32 {
+	return a + (b-a)*t
+}
+
+// lerpPoint linearly interpolates between the points a and b at parameter t.
+func lerpPoint(a, b Point, t float32) Point {
+	return Point{X: lerp(a.X, b.X, t), Y: lerp(a.Y, b.Y, t)}
+}
@@ -0,0 +1,141 @@
+package tochka
+
+import (
+	"math"
+	"testing"
+)
+
+// TestDecompose tests that the components used to build a transformation are recovered.
+func TestDecompose(t *testing.T) {
+	tests := []struct {
+		name string
+		want Decomposition
+	}{
+		{"identity", Decomposition{Scale: Point{X: 1, Y: 1}}},
+		{"translation", Decomposition{Translation: Point{X: 3, Y: -4}, Scale: Point{X: 1, Y: 1}}},
+		{"rotation", Decomposition{Rotation: 2, Scale: Point{X: 1, Y: 1}}},
+		{"non-uniform scale", Decomposition{Scale: Point{X: 2, Y: 0.5}}},
+		{"skew", Decomposition{Scale: Point{X: 1, Y: 1}, Skew: 0.4}},
+		{"reflection", Decomposition{Rotation: -0.3, Scale: Point{X: 2, Y: -3}}},
+		{"all", Decomposition{Translation: Point{X: 10, Y: 20}, Rotation: math.Pi / 3, Scale: Point{X: 1.5, Y: 2.5}, Skew: -0.2}},
+	}
+	for _, tt := range tests {
+		a := Affine2D{}.
+			Scale(Point{}, tt.want.Scale).
+			Shear(Point{}, tt.want.Skew, 0).
+			Rotate(Point{}, tt.want.Rotation).
+			Offset(tt.want.Translation)
+		got := a.Decompose()
+		if !decompositionAlmostEqual(got, tt.want, 1e-5) {
+			t.Errorf("%s: Decompose() = %+v; want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestDecompose_RoundTrip tests that Compose reverses Decompose.
+func TestDecompose_RoundTrip(t *testing.T) {
+	tests := []Affine2D{
+		NewAffine2D(2, 1, 3, 1, 2, 4),
+		NewAffine2D(-1, 0, 0, 0, 1, 0),
+		NewAffine2D(1, 0, 0, 0, -1, 0),
+		NewAffine2D(-1, 0, 5, 0, -1, 5),
+		NewAffine2D(0, 1, 0, -1, 0, 0),
+		NewAffine2D(0.3, -2, 7, 0.8, 0.1, -3),
+		NewAffine2D(0, 0, 1, 0, 2, 1),
+		NewAffine2D(0, 0, 0, 0, 0, 0),
+	}
+	for _, a := range tests {
+		d := a.Decompose()
+		if got := d.Compose(); !affineAlmostEqual(got, a, 1e-5) {
+			t.Errorf("Compose(Decompose(%v)) = %v (%+v)", a, got, d)
+		}
+		if again := d.Compose().Decompose(); !decompositionAlmostEqual(again, d, 1e-5) {
+			t.Errorf("Decompose is not stable for %v: %+v then %+v", a, d, again)
+		}
+	}
+}
+
+// TestDecompose_Reflection tests that a reflection is reported through the sign of the Y scale.
+func TestDecompose_Reflection(t *testing.T) {
+	a := NewAffine2D(-2, 0, 0, 0, 3, 0)
+	d := a.Decompose()
+	if (d.Scale.X < 0) || (d.Scale.Y >= 0) {
+		t.Errorf("Decompose() = %+v; want positive X scale and negative Y scale", d)
+	}
+	if !almostEqual(d.Scale.X*d.Scale.Y, a.Determinant(), 1e-5) {
+		t.Errorf("scale product %v does not match determinant %v", d.Scale.X*d.Scale.Y, a.Determinant())
+	}
+}
+
+// decompositionAlmostEqual compares two decompositions within a given precision.
+func decompositionAlmostEqual(a, b Decomposition, epsilon float32) bool {
+	return almostEqual(a.Translation.X, b.Translation.X, epsilon) &&
+		almostEqual(a.Translation.Y, b.Translation.Y, epsilon) &&
+		angleAlmostEqual(a.Rotation, b.Rotation, epsilon) &&
+		almostEqual(a.Scale.X, b.Scale.X, epsilon) &&
+		almostEqual(a.Scale.Y, b.Scale.Y, epsilon) &&
+		almostEqual(a.Skew, b.Skew, epsilon)
+}
+
+// angleAlmostEqual compares two angles modulo 2π within a given precision.
+func angleAlmostEqual(a, b float32, epsilon float32) bool {
+	d := math.Remainder(float64(a)-float64(b), 2*math.Pi)
+	return math.Abs(d) < float64(epsilon)
+}
+
+// TestInterpolate_Endpoints tests that the interpolation starts at a and ends at b.
+func TestInterpolate_Endpoints(t *testing.T) {
+	a := NewAffine2D(2, 1, 3, 1, 2, 4)
+	b := NewAffine2D(-1, 0.5, -2, 0.3, 0.7, 8)
+	if got := Interpolate(a, b, 0); !affineAlmostEqual(got, a, 1e-5) {
+		t.Errorf("Interpolate(a, b, 0) = %v; want %v", got, a)
+	}
+	if got := Interpolate(a, b, 1); !affineAlmostEqual(got, b, 1e-5) {
+		t.Errorf("Interpolate(a, b, 1) = %v; want %v", got, b)
+	}
+}
+
+// TestInterpolate_Rotation tests that rotations stay rigid and follow the shortest arc.
+func TestInterpolate_Rotation(t *testing.T) {
+	a := Affine2D{}.Rotate(Point{}, 0.2)
+	b := Affine2D{}.Rotate(Point{}, -0.2+2*math.Pi)
+	for _, tt := range []float32{0.25, 0.5, 0.75} {
+		got := Interpolate(a, b, tt)
+		want := Affine2D{}.Rotate(Point{}, 0.2-0.4*tt)
+		if !affineAlmostEqual(got, want, 1e-5) {
+			t.Errorf("Interpolate(a, b, %v) = %v; want %v", tt, got, want)
+		}
+	}
+
+	// Element-wise interpolation of a half turn collapses to zero at the midpoint,
+	// the decomposed interpolation must stay a rotation instead.
+	flip := Affine2D{}.Rotate(Point{}, math.Pi)
+	for _, tt := range []float32{0.25, 0.5, 0.75} {
+		got := Interpolate(Affine2D{}, flip, tt)
+		if det := got.Determinant(); !almostEqual(det, 1, 1e-5) {
+			t.Errorf("Interpolate(identity, flip, %v) determinant = %v; want 1", tt, det)
+		}
+		if rot := got.Decompose().Rotation; !almostEqual(float32(math.Abs(float64(rot))), math.Pi*tt, 1e-5) {
+			t.Errorf("Interpolate(identity, flip, %v) rotation = %v; want ±%v", tt, rot, math.Pi*tt)
+		}
+	}
+}
+
+// TestInterpolate_Reflection tests that interpolating between reflections keeps the reflection.
+func TestInterpolate_Reflection(t *testing.T) {
+	a := NewAffine2D(-1, 0, 0, 0, 1, 0)
+	b := NewAffine2D(1, 0, 10, 0, -2, 0).Rotate(Point{}, 0.5)
+	for _, tt := range []float32{0, 0.25, 0.5, 0.75, 1} {
+		got := Interpolate(a, b, tt)
+		if det := got.Determinant(); det >= 0 {
+			t.Errorf("Interpolate(a, b, %v) determinant = %v; want negative", tt, det)
+		}
+	}
+
+	// A reflection across X and one across Y differ by a half turn.
+	got := Interpolate(a, NewAffine2D(1, 0, 0, 0, -1, 0), 0.5)
+	if !affineAlmostEqual(got, NewAffine2D(0, 1, 0, 1, 0, 0), 1e-5) &&
+		!affineAlmostEqual(got, NewAffine2D(0, -1, 0, -1, 0, 0), 1e-5) {
+		t.Errorf("Interpolate between axis reflections = %v; want a diagonal reflection", got)
+	}
+}
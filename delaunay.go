@@ -0,0 +1,532 @@
+package tochka
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrInvalidConstraint is returned by ConstrainedDelaunay for a constrained edge that
+// refers to a missing or non-finite point, or crosses another constrained edge.
+var ErrInvalidConstraint = errors.New("invalid constrained edge")
+
+// Triangulation is a triangle mesh over a point set, as returned by Delaunay and
+// ConstrainedDelaunay. Vertices are indices into the points it was built from.
+type Triangulation struct {
+	// Triangles lists the vertices of every triangle in counterclockwise order.
+	Triangles [][3]int
+	// Adjacent lists for every triangle the neighbor across the edge from its vertex k
+	// to its vertex k+1 (mod 3), or -1 if that edge lies on the convex hull.
+	Adjacent [][3]int
+	// Constrained marks the edges of every triangle, in the order of Adjacent, that are
+	// constrained. It is nil for an unconstrained triangulation.
+	Constrained [][3]bool
+}
+
+// Edges returns every edge of the triangulation once, as the pair of its vertices.
+// The edges of a Delaunay triangulation form a graph that contains the nearest
+// neighbor of every point.
+func (t Triangulation) Edges() [][2]int {
+	var edges [][2]int
+	for k, v := range t.Triangles {
+		for e := range 3 {
+			if n := t.Adjacent[k][e]; n < 0 || k < n {
+				edges = append(edges, [2]int{v[e], v[(e+1)%3]})
+			}
+		}
+	}
+	return edges
+}
+
+// Delaunay returns the Delaunay triangulation of the points, in which the circumcircle
+// of every triangle contains no point in its interior. It is computed by Bowyer–Watson
+// insertion in the order of a Hilbert curve, with the robust Orient2D and InCircle
+// predicates, so cocircular and collinear points never make it fail; among cocircular
+// points the choice of diagonals is arbitrary.
+//
+// Duplicate points are represented by their first occurrence, and points with NaN or
+// infinite coordinates are ignored. If fewer than three points remain, or all of them
+// are collinear, the triangulation has no triangles.
+func Delaunay(points []Point) Triangulation {
+	var d delaunay
+	if !d.build(points) {
+		return Triangulation{}
+	}
+	return d.result(false)
+}
+
+// ConstrainedDelaunay returns the constrained Delaunay triangulation of the points, which
+// contains every edge given as a pair of point indices and is otherwise as close to the
+// Delaunay triangulation as the constraints allow. Constraining the edges of a polygon
+// preserves its boundary in the mesh. An edge passing through other points is split at
+// them, and a constrained edge between duplicate points is ignored.
+//
+// It returns an error wrapping ErrInvalidConstraint if an edge refers to a point that
+// is out of range or not finite, or if two edges cross.
+func ConstrainedDelaunay(points []Point, edges [][2]int) (Triangulation, error) {
+	for k, e := range edges {
+		for _, i := range e {
+			if i < 0 || i >= len(points) {
+				return Triangulation{}, fmt.Errorf("%w: edge %d refers to point %d of %d", ErrInvalidConstraint, k, i, len(points))
+			}
+			if !finite(float64(points[i].X), float64(points[i].Y)) {
+				return Triangulation{}, fmt.Errorf("%w: edge %d refers to non-finite point %d", ErrInvalidConstraint, k, i)
+			}
+		}
+	}
+	var d delaunay
+	if !d.build(points) {
+		return Triangulation{}, nil
+	}
+	for k, e := range edges {
+		if err := d.constrain(d.rep[e[0]], d.rep[e[1]]); err != nil {
+			return Triangulation{}, fmt.Errorf("%w: edge %d %v", ErrInvalidConstraint, k, err)
+		}
+	}
+	return d.result(true), nil
+}
+
+// ghost is the vertex at infinity. Every edge of the convex hull is closed by a ghost
+// triangle, so that points outside the hull are inserted like points inside it.
+const ghost = -1
+
+// delaunayTriangle is a triangle of the mesh under construction.
+type delaunayTriangle struct {
+	v   [3]int
+	adj [3]int
+	// cons marks the constrained edges.
+	cons [3]bool
+	// dead marks a triangle that was removed and whose slot may be reused.
+	dead bool
+}
+
+// cavityEdge is an edge on the boundary of a region being retriangulated, with the
+// triangle outside the region.
+type cavityEdge struct {
+	u, v, out int
+	cons      bool
+}
+
+// delaunay holds the state of a triangulation under construction.
+type delaunay struct {
+	points []Point
+	tris   []delaunayTriangle
+	free   []int
+	// rep maps every point to its first occurrence, or to ghost if it is not finite.
+	rep []int
+	// vertexTri holds a live triangle for every inserted vertex.
+	vertexTri []int
+	// last is a recently created triangle, where point location starts.
+	last int
+	// cavity, boundary and ids are scratch space for point insertion.
+	cavity   []int
+	boundary []cavityEdge
+	ids      []int
+}
+
+// build inserts the points and reports whether the triangulation has any triangles.
+func (d *delaunay) build(points []Point) bool {
+	d.points = points
+	d.rep = make([]int, len(points))
+	d.vertexTri = make([]int, len(points))
+	order := make([]int, 0, len(points))
+	for i, p := range points {
+		d.rep[i], d.vertexTri[i] = ghost, -1
+		if finite(float64(p.X), float64(p.Y)) {
+			order = append(order, i)
+		}
+	}
+	slices.SortFunc(order, func(i, j int) int {
+		a, b := points[i], points[j]
+		return cmp.Or(cmp.Compare(a.X, b.X), cmp.Compare(a.Y, b.Y), cmp.Compare(i, j))
+	})
+	unique := order[:0]
+	for _, i := range order {
+		if n := len(unique); n > 0 && points[unique[n-1]] == points[i] {
+			d.rep[i] = unique[n-1]
+			continue
+		}
+		d.rep[i] = i
+		unique = append(unique, i)
+	}
+
+	// Start from the first two points and the first point not collinear with them.
+	m := slices.IndexFunc(unique, func(i int) bool {
+		return len(unique) > 2 && Orient2D(points[unique[0]], points[unique[1]], points[i]) != Collinear
+	})
+	if m < 0 {
+		return false
+	}
+	a, b, c := unique[0], unique[1], unique[m]
+	if Orient2D(points[a], points[b], points[c]) == Clockwise {
+		b, c = c, b
+	}
+	d.fill(nil, [][3]int{{a, b, c}, {b, a, ghost}, {c, b, ghost}, {a, c, ghost}})
+	// Inserting the other points along a Hilbert curve keeps every point close to the
+	// previous one, so that point location takes few steps.
+	bounds := Rect{Min: points[unique[0]], Max: points[unique[0]]}
+	for _, i := range unique {
+		bounds.Min = Point{X: min(bounds.Min.X, points[i].X), Y: min(bounds.Min.Y, points[i].Y)}
+		bounds.Max = Point{X: max(bounds.Max.X, points[i].X), Y: max(bounds.Max.Y, points[i].Y)}
+	}
+	type keyed struct {
+		key uint32
+		i   int
+	}
+	rest := make([]keyed, 0, len(unique))
+	for k, i := range unique {
+		if k != 0 && k != 1 && k != m {
+			rest = append(rest, keyed{hilbert(points[i], bounds), i})
+		}
+	}
+	slices.SortFunc(rest, func(a, b keyed) int { return cmp.Compare(a.key, b.key) })
+	for _, r := range rest {
+		d.insert(r.i)
+	}
+	return true
+}
+
+// hilbert returns the position of p along a Hilbert curve filling the bounds on a grid
+// of 2^16 by 2^16 cells.
+func hilbert(p Point, bounds Rect) uint32 {
+	const n = 1 << 16
+	cell := func(v, lo, hi float32) uint32 {
+		if hi <= lo {
+			return 0
+		}
+		return uint32(min((float64(v)-float64(lo))/(float64(hi)-float64(lo))*n, n-1))
+	}
+	x, y := cell(p.X, bounds.Min.X, bounds.Max.X), cell(p.Y, bounds.Min.Y, bounds.Max.Y)
+	var key uint32
+	for s := uint32(n / 2); s > 0; s /= 2 {
+		var rx, ry uint32
+		if x&s != 0 {
+			rx = 1
+		}
+		if y&s != 0 {
+			ry = 1
+		}
+		key += s * s * (3*rx ^ ry)
+		// Rotate the quadrant so that the curve continues in the next level.
+		if ry == 0 {
+			if rx == 1 {
+				x, y = n-1-x, n-1-y
+			}
+			x, y = y, x
+		}
+	}
+	return key
+}
+
+// insert adds a vertex by removing the triangles whose circumcircle contains it and
+// connecting it to the boundary of the resulting cavity.
+func (d *delaunay) insert(i int) {
+	p := d.points[i]
+	start := d.locate(p)
+	d.tris[start].dead = true
+	cavity := append(d.cavity[:0], start)
+	boundary := d.boundary[:0]
+	for k := 0; k < len(cavity); k++ {
+		t := d.tris[cavity[k]]
+		for e := range 3 {
+			n := t.adj[e]
+			if d.tris[n].dead {
+				continue
+			}
+			if d.conflict(n, p) {
+				d.tris[n].dead = true
+				cavity = append(cavity, n)
+				continue
+			}
+			boundary = append(boundary, cavityEdge{u: t.v[e], v: t.v[(e+1)%3], out: n, cons: t.cons[e]})
+		}
+	}
+	d.free = append(d.free, cavity...)
+	d.fan(boundary, i)
+	d.cavity, d.boundary = cavity, boundary
+}
+
+// conflict reports whether the vertex p lies inside the circumcircle of the triangle. The
+// circumcircle of a ghost triangle is the open half-plane outside its hull edge, together
+// with the edge itself.
+func (d *delaunay) conflict(t int, p Point) bool {
+	v := d.tris[t].v
+	for k := range 3 {
+		if v[(k+2)%3] == ghost {
+			a, b := d.points[v[k]], d.points[v[(k+1)%3]]
+			o := Orient2D(a, b, p)
+			return o == CounterClockwise || o == Collinear && inBox(a, b, p)
+		}
+	}
+	return InCircle(d.points[v[0]], d.points[v[1]], d.points[v[2]], p) > 0
+}
+
+// locate returns a triangle whose circumcircle contains p, found by walking from the last
+// created triangle towards p. It returns the triangle containing p, or the ghost triangle
+// of a hull edge that p lies beyond.
+func (d *delaunay) locate(p Point) int {
+	t := d.last
+	if g := slices.Index(d.tris[t].v[:], ghost); g >= 0 {
+		// Step from a ghost triangle across its hull edge.
+		t = d.tris[t].adj[(g+1)%3]
+	}
+	for step := 0; ; step++ {
+		tr := d.tris[t]
+		if slices.Contains(tr.v[:], ghost) {
+			return t
+		}
+		moved := false
+		// Rotating the first edge tested prevents cycles between equally good moves.
+		for j := range 3 {
+			e := (j + step) % 3
+			if Orient2D(d.points[tr.v[e]], d.points[tr.v[(e+1)%3]], p) == Clockwise {
+				t, moved = tr.adj[e], true
+				break
+			}
+		}
+		if !moved {
+			return t
+		}
+	}
+}
+
+// constrain inserts the edge between the vertices a and b, splitting it at vertices that
+// lie on it.
+func (d *delaunay) constrain(a, b int) error {
+	for a != b {
+		next, err := d.constrainFrom(a, b)
+		if err != nil {
+			return err
+		}
+		a = next
+	}
+	return nil
+}
+
+// constrainFrom inserts the part of the edge from a to b up to the first vertex it
+// reaches, and returns that vertex.
+func (d *delaunay) constrainFrom(a, b int) (int, error) {
+	pa, pb := d.points[a], d.points[b]
+	start := d.vertexTri[a]
+	t := start
+	for {
+		tr := d.tris[t]
+		i := slices.Index(tr.v[:], a)
+		x, y := tr.v[(i+1)%3], tr.v[(i+2)%3]
+		if x != ghost {
+			px := d.points[x]
+			ox := Orient2D(pa, px, pb)
+			if x == b || ox == Collinear && inBox(pa, pb, px) {
+				d.setConstrained(t, i)
+				return x, nil
+			}
+			if y != ghost && ox == CounterClockwise && Orient2D(pa, d.points[y], pb) == Clockwise {
+				return d.cut(t, (i+1)%3, a, b)
+			}
+		}
+		// Turn counterclockwise around a.
+		t = tr.adj[(i+2)%3]
+		if t == start {
+			return 0, fmt.Errorf("from point %d to %d leaves the triangulation", a, b)
+		}
+	}
+}
+
+// cut removes the triangles crossed by the edge from a towards b, starting with the
+// triangle t whose edge e it crosses, retriangulates the two sides of the edge and
+// returns the vertex where the edge ends, either b or a vertex lying on it.
+func (d *delaunay) cut(t, e, a, b int) (int, error) {
+	pa, pb := d.points[a], d.points[b]
+	// The chains hold the vertices on each side of the edge, from a onwards.
+	right := []int{d.tris[t].v[e]}
+	left := []int{d.tris[t].v[(e+1)%3]}
+	removed := []int{t}
+	end := -1
+	for end < 0 {
+		tr := d.tris[t]
+		if tr.cons[e] {
+			return 0, fmt.Errorf("crosses the constrained edge from point %d to %d", tr.v[e], tr.v[(e+1)%3])
+		}
+		// The neighbor across the crossed edge lists its endpoints in reverse order at j and
+		// j+1, followed by the vertex z.
+		n := tr.adj[e]
+		nt := d.tris[n]
+		j := slices.Index(nt.v[:], tr.v[(e+1)%3])
+		z := nt.v[(j+2)%3]
+		removed = append(removed, n)
+		o := Orient2D(pa, pb, d.points[z])
+		switch {
+		case z == b || o == Collinear:
+			end = z
+		case o == CounterClockwise:
+			left = append(left, z)
+			t, e = n, (j+1)%3
+		default:
+			right = append(right, z)
+			t, e = n, (j+2)%3
+		}
+	}
+
+	for _, r := range removed {
+		d.tris[r].dead = true
+	}
+	var boundary []cavityEdge
+	for _, r := range removed {
+		tr := d.tris[r]
+		for e := range 3 {
+			if n := tr.adj[e]; !d.tris[n].dead {
+				boundary = append(boundary, cavityEdge{u: tr.v[e], v: tr.v[(e+1)%3], out: n, cons: tr.cons[e]})
+			}
+		}
+	}
+	d.free = append(d.free, removed...)
+	slices.Reverse(left)
+	tris := d.pseudoPolygon(nil, a, end, left)
+	tris = d.pseudoPolygon(tris, end, a, right)
+	for _, t := range d.fill(boundary, tris) {
+		if k := slices.Index(d.tris[t].v[:], a); k >= 0 && d.tris[t].v[(k+1)%3] == end {
+			d.setConstrained(t, k)
+			break
+		}
+	}
+	return end, nil
+}
+
+// pseudoPolygon appends the constrained Delaunay triangulation of the polygon formed by
+// the edge from p to q and the chain of vertices to its left, ordered from q back to p.
+// The apex of the triangle on the edge is the chain vertex whose circle through p and q
+// contains no other chain vertex.
+func (d *delaunay) pseudoPolygon(tris [][3]int, p, q int, chain []int) [][3]int {
+	if len(chain) == 0 {
+		return tris
+	}
+	c := 0
+	for k := 1; k < len(chain); k++ {
+		if InCircle(d.points[p], d.points[q], d.points[chain[c]], d.points[chain[k]]) > 0 {
+			c = k
+		}
+	}
+	tris = append(tris, [3]int{p, q, chain[c]})
+	tris = d.pseudoPolygon(tris, chain[c], q, chain[:c])
+	return d.pseudoPolygon(tris, p, chain[c], chain[c+1:])
+}
+
+// fill creates the triangles, links them to each other and to the triangles outside the
+// boundary of the region they cover, and returns their slots.
+func (d *delaunay) fill(boundary []cavityEdge, tris [][3]int) []int {
+	type half struct{ t, e int }
+	edges := make(map[[2]int]half, 3*len(tris))
+	ids := make([]int, len(tris))
+	for k, v := range tris {
+		ids[k] = d.alloc(v)
+		for e := range 3 {
+			edges[[2]int{v[e], v[(e+1)%3]}] = half{ids[k], e}
+		}
+	}
+	for k, v := range tris {
+		for e := range 3 {
+			if twin, ok := edges[[2]int{v[(e+1)%3], v[e]}]; ok {
+				d.tris[ids[k]].adj[e] = twin.t
+			}
+		}
+	}
+	for _, b := range boundary {
+		h := edges[[2]int{b.u, b.v}]
+		d.linkOut(h.t, h.e, b)
+	}
+	if len(ids) > 0 {
+		d.last = ids[0]
+	}
+	return ids
+}
+
+// fan connects the vertex i to the boundary edges of a cavity that is star-shaped from
+// it. It is a faster fill for the cavities of point insertion.
+func (d *delaunay) fan(boundary []cavityEdge, i int) {
+	ids := d.ids[:0]
+	for _, b := range boundary {
+		t := d.alloc([3]int{b.u, b.v, i})
+		d.linkOut(t, 0, b)
+		ids = append(ids, t)
+	}
+	// The edge from v to i of the triangle on u, v is shared with the triangle on v, w.
+	for k, b := range boundary {
+		for j, c := range boundary {
+			if c.u == b.v {
+				d.tris[ids[k]].adj[1] = ids[j]
+				d.tris[ids[j]].adj[2] = ids[k]
+				break
+			}
+		}
+	}
+	d.last = ids[0]
+	d.ids = ids
+}
+
+// linkOut links the edge e of the new triangle t to the triangle outside the boundary edge b.
+func (d *delaunay) linkOut(t, e int, b cavityEdge) {
+	d.tris[t].adj[e] = b.out
+	d.tris[t].cons[e] = b.cons
+	out := &d.tris[b.out]
+	out.adj[slices.Index(out.v[:], b.v)] = t
+}
+
+// alloc stores a new triangle, reusing the slot of a removed one if possible.
+func (d *delaunay) alloc(v [3]int) int {
+	tr := delaunayTriangle{v: v, adj: [3]int{-1, -1, -1}}
+	var t int
+	if n := len(d.free); n > 0 {
+		t = d.free[n-1]
+		d.free = d.free[:n-1]
+		d.tris[t] = tr
+	} else {
+		t = len(d.tris)
+		d.tris = append(d.tris, tr)
+	}
+	for _, u := range v {
+		if u != ghost {
+			d.vertexTri[u] = t
+		}
+	}
+	return t
+}
+
+// setConstrained marks the edge e of the triangle t, on both of its sides.
+func (d *delaunay) setConstrained(t, e int) {
+	tr := &d.tris[t]
+	tr.cons[e] = true
+	n := &d.tris[tr.adj[e]]
+	n.cons[slices.Index(n.v[:], tr.v[(e+1)%3])] = true
+}
+
+// result returns the live finite triangles with their adjacency.
+func (d *delaunay) result(constrained bool) Triangulation {
+	index := make([]int, len(d.tris))
+	var out Triangulation
+	for t, tr := range d.tris {
+		index[t] = -1
+		if !tr.dead && !slices.Contains(tr.v[:], ghost) {
+			index[t] = len(out.Triangles)
+			out.Triangles = append(out.Triangles, tr.v)
+		}
+	}
+	out.Adjacent = make([][3]int, 0, len(out.Triangles))
+	if constrained {
+		out.Constrained = make([][3]bool, 0, len(out.Triangles))
+	}
+	for t, tr := range d.tris {
+		if index[t] < 0 {
+			continue
+		}
+		var adj [3]int
+		for e, n := range tr.adj {
+			adj[e] = index[n]
+		}
+		out.Adjacent = append(out.Adjacent, adj)
+		if constrained {
+			out.Constrained = append(out.Constrained, tr.cons)
+		}
+	}
+	return out
+}
@@ -0,0 +1,273 @@
+package tochka
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// checkMesh fails the test unless the triangles are counterclockwise, their adjacency is
+// symmetric and they cover the convex hull of the points.
+func checkMesh(t *testing.T, name string, points []Point, tri Triangulation) {
+	t.Helper()
+	if len(tri.Adjacent) != len(tri.Triangles) {
+		t.Fatalf("%s: %d adjacency entries for %d triangles", name, len(tri.Adjacent), len(tri.Triangles))
+	}
+	var area float64
+	for k, v := range tri.Triangles {
+		a, b, c := points[v[0]], points[v[1]], points[v[2]]
+		if Orient2D(a, b, c) != CounterClockwise {
+			t.Fatalf("%s: triangle %d %v is not counterclockwise", name, k, v)
+		}
+		area += b.To64().Sub(a.To64()).Cross(c.To64().Sub(a.To64())) / 2
+		for e, n := range tri.Adjacent[k] {
+			if n < 0 {
+				continue
+			}
+			w := tri.Triangles[n]
+			j := slices.Index(w[:], v[(e+1)%3])
+			if j < 0 || w[(j+1)%3] != v[e] || tri.Adjacent[n][j] != k {
+				t.Fatalf("%s: triangles %d %v and %d %v are not adjacent", name, k, v, n, w)
+			}
+			if tri.Constrained != nil && tri.Constrained[k][e] != tri.Constrained[n][j] {
+				t.Fatalf("%s: edge %d of triangle %d is constrained on one side only", name, e, k)
+			}
+		}
+	}
+	finitePoints := slices.DeleteFunc(slices.Clone(points), func(p Point) bool {
+		return !finite(float64(p.X), float64(p.Y))
+	})
+	if hull := float64(ConvexHull(finitePoints).Area()); math.Abs(area-hull) > 1e-5*hull {
+		t.Fatalf("%s: triangles cover %v; want the hull area %v", name, area, hull)
+	}
+}
+
+// checkEmptyCircles fails the test if the circumcircle of a triangle contains a point.
+func checkEmptyCircles(t *testing.T, name string, points []Point, tri Triangulation) {
+	t.Helper()
+	for _, v := range tri.Triangles {
+		for _, p := range points {
+			if InCircle(points[v[0]], points[v[1]], points[v[2]], p) > 0 {
+				t.Fatalf("%s: the circumcircle of %v contains %v", name, v, p)
+			}
+		}
+	}
+}
+
+// hasEdge reports whether the triangulation has an edge between a and b.
+func hasEdge(tri Triangulation, a, b int) bool {
+	return slices.ContainsFunc(tri.Edges(), func(e [2]int) bool {
+		return e == [2]int{a, b} || e == [2]int{b, a}
+	})
+}
+
+// TestDelaunay tests small, degenerate and cocircular point sets.
+func TestDelaunay(t *testing.T) {
+	pt := func(x, y float32) Point { return Point{X: x, Y: y} }
+	tests := []struct {
+		name      string
+		points    []Point
+		triangles int
+	}{
+		{"empty", nil, 0},
+		{"two points", []Point{pt(0, 0), pt(1, 0)}, 0},
+		{"collinear", []Point{pt(0, 0), pt(2, 2), pt(1, 1), pt(3, 3)}, 0},
+		{"duplicates", []Point{pt(0, 0), pt(0, 0), pt(1, 1), pt(1, 1)}, 0},
+		{"triangle", []Point{pt(0, 0), pt(0, 1), pt(1, 0)}, 1},
+		{"square", []Point{pt(0, 0), pt(1, 0), pt(1, 1), pt(0, 1)}, 2},
+		{"collinear start", []Point{pt(0, 0), pt(0, 1), pt(0, 2), pt(0, 3), pt(1, 0)}, 3},
+		{"duplicate vertex", []Point{pt(0, 0), pt(1, 0), pt(0, 1), pt(1, 0), pt(1, 1)}, 2},
+		{"non-finite point", []Point{pt(0, 0), pt(1, 0), pt(float32(math.NaN()), 0), pt(0, 1)}, 1},
+	}
+	for _, tt := range tests {
+		tri := Delaunay(tt.points)
+		if len(tri.Triangles) != tt.triangles {
+			t.Errorf("%s: Delaunay() = %v; want %d triangles", tt.name, tri.Triangles, tt.triangles)
+			continue
+		}
+		if tt.triangles > 0 {
+			checkMesh(t, tt.name, tt.points, tri)
+		}
+		if tri.Constrained != nil {
+			t.Errorf("%s: Delaunay() has constrained edges", tt.name)
+		}
+	}
+	if tri := Delaunay([]Point{pt(0, 0), pt(1, 0), pt(0, 1), pt(1, 0), pt(1, 1)}); slices.ContainsFunc(tri.Triangles, func(v [3]int) bool { return slices.Contains(v[:], 3) }) {
+		t.Errorf("Delaunay() = %v; want the duplicate point 3 unused", tri.Triangles)
+	}
+
+	// A grid is full of cocircular points. Every cell is split in two.
+	var grid []Point
+	for x := range 10 {
+		for y := range 10 {
+			grid = append(grid, pt(float32(x), float32(y)))
+		}
+	}
+	tri := Delaunay(grid)
+	if len(tri.Triangles) != 162 {
+		t.Errorf("grid: Delaunay() has %d triangles; want 162", len(tri.Triangles))
+	}
+	checkMesh(t, "grid", grid, tri)
+	checkEmptyCircles(t, "grid", grid, tri)
+}
+
+// TestDelaunay_Random tests random points, in a disk and on a coarse lattice, against
+// the empty circle property and Euler's formula.
+func TestDelaunay_Random(t *testing.T) {
+	r := rand.New(rand.NewPCG(11, 12))
+	for n := range 40 {
+		points := make([]Point, 3+r.IntN(200))
+		for k := range points {
+			if n%2 == 0 {
+				s, c := math.Sincos(r.Float64() * 2 * math.Pi)
+				d := math.Sqrt(r.Float64()) * 100
+				points[k] = Point{X: float32(c * d), Y: float32(s * d)}
+			} else {
+				points[k] = Point{X: float32(r.IntN(12)), Y: float32(r.IntN(12))}
+			}
+		}
+		tri := Delaunay(points)
+		checkMesh(t, "random", points, tri)
+		checkEmptyCircles(t, "random", points, tri)
+
+		vertices := len(compactPoints(points))
+		hull := len(ConvexHullCollinear(points))
+		if want := 2*vertices - 2 - hull; len(tri.Triangles) != want {
+			t.Fatalf("random: %d triangles for %d points with %d on the hull; want %d", len(tri.Triangles), vertices, hull, want)
+		}
+		if edges := len(tri.Edges()); edges != 3*vertices-3-hull {
+			t.Fatalf("random: %d edges; want %d", edges, 3*vertices-3-hull)
+		}
+	}
+}
+
+// compactPoints returns the distinct points.
+func compactPoints(points []Point) []Point {
+	var out []Point
+	for _, p := range points {
+		if !slices.Contains(out, p) {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// TestConstrainedDelaunay tests that constrained edges are preserved, including edges
+// crossing many triangles and edges passing through points.
+func TestConstrainedDelaunay(t *testing.T) {
+	pt := func(x, y float32) Point { return Point{X: x, Y: y} }
+	var grid []Point
+	for y := range 6 {
+		for x := range 6 {
+			grid = append(grid, pt(float32(x)+float32(y%2)*0.25, float32(y)))
+		}
+	}
+	// The edge from (0, 0) to (5.25, 5) crosses most of the grid, and the edge from
+	// (0, 4) to (3, 4) passes through two points.
+	tri, err := ConstrainedDelaunay(grid, [][2]int{{0, 35}, {24, 27}})
+	if err != nil {
+		t.Fatalf("ConstrainedDelaunay() error = %v", err)
+	}
+	checkMesh(t, "grid", grid, tri)
+	for _, e := range [][2]int{{0, 35}, {24, 25}, {25, 26}, {26, 27}} {
+		if !hasEdge(tri, e[0], e[1]) {
+			t.Errorf("grid: edge %v is missing", e)
+		}
+	}
+	constrained := 0
+	for _, c := range tri.Constrained {
+		for _, b := range c {
+			if b {
+				constrained++
+			}
+		}
+	}
+	if constrained != 2*4 {
+		t.Errorf("grid: %d constrained half-edges; want 8", constrained)
+	}
+
+	// The boundary of a concave polygon is preserved.
+	comb := []Point{pt(0, 0), pt(10, 0), pt(10, 10), pt(8, 10), pt(8, 1), pt(6, 1), pt(6, 10), pt(4, 10), pt(4, 1), pt(2, 1), pt(2, 10), pt(0, 10)}
+	var ring [][2]int
+	for k := range comb {
+		ring = append(ring, [2]int{k, (k + 1) % len(comb)})
+	}
+	tri, err = ConstrainedDelaunay(comb, ring)
+	if err != nil {
+		t.Fatalf("ConstrainedDelaunay() error = %v", err)
+	}
+	checkMesh(t, "comb", comb, tri)
+	for _, e := range ring {
+		if !hasEdge(tri, e[0], e[1]) {
+			t.Errorf("comb: boundary edge %v is missing", e)
+		}
+	}
+
+	// Without constraints the result is the Delaunay triangulation.
+	tri, err = ConstrainedDelaunay(grid, nil)
+	if err != nil {
+		t.Fatalf("ConstrainedDelaunay() error = %v", err)
+	}
+	checkEmptyCircles(t, "unconstrained", grid, tri)
+
+	for _, edges := range [][][2]int{
+		{{0, 36}},
+		{{-1, 0}},
+		{{0, 35}, {5, 30}},
+	} {
+		if _, err := ConstrainedDelaunay(grid, edges); !errors.Is(err, ErrInvalidConstraint) {
+			t.Errorf("ConstrainedDelaunay(%v) error = %v; want ErrInvalidConstraint", edges, err)
+		}
+	}
+}
+
+// TestConstrainedDelaunay_Random constrains random points with edges of the Delaunay
+// triangulation of the points stretched along X, which never cross each other, and
+// checks that every point inside the circumcircle of a triangle is hidden from it by a
+// constrained edge.
+func TestConstrainedDelaunay_Random(t *testing.T) {
+	r := rand.New(rand.NewPCG(13, 14))
+	for range 20 {
+		points := make([]Point, 3+r.IntN(60))
+		stretched := make([]Point, len(points))
+		for k := range points {
+			points[k] = Point{X: float32(r.IntN(1000)) / 10, Y: float32(r.IntN(1000)) / 10}
+			stretched[k] = Point{X: points[k].X * 8, Y: points[k].Y}
+		}
+		var edges [][2]int
+		for _, e := range Delaunay(stretched).Edges() {
+			if r.IntN(2) == 0 {
+				edges = append(edges, e)
+			}
+		}
+		tri, err := ConstrainedDelaunay(points, edges)
+		if err != nil {
+			t.Fatalf("ConstrainedDelaunay() error = %v", err)
+		}
+		checkMesh(t, "random", points, tri)
+		for _, e := range edges {
+			if !hasEdge(tri, e[0], e[1]) {
+				t.Fatalf("random: constrained edge %v is missing", e)
+			}
+		}
+		for _, v := range tri.Triangles {
+			a, b, c := points[v[0]], points[v[1]], points[v[2]]
+			center := Point{X: (a.X + b.X + c.X) / 3, Y: (a.Y + b.Y + c.Y) / 3}
+			for _, p := range points {
+				if InCircle(a, b, c, p) <= 0 {
+					continue
+				}
+				hidden := slices.ContainsFunc(edges, func(e [2]int) bool {
+					q, s := points[e[0]], points[e[1]]
+					return Orient2D(q, s, center) != Orient2D(q, s, p) && Orient2D(center, p, q) != Orient2D(center, p, s) &&
+						p != q && p != s
+				})
+				if !hidden {
+					t.Fatalf("random: the circumcircle of %v contains the visible point %v", v, p)
+				}
+			}
+		}
+	}
+}
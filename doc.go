@@ -37,12 +37,219 @@
 //   - Rotate(origin Point, radians float32) Affine2D: Rotates around a given point by an angle in radians.
 //   - Shear(origin Point, radiansX, radiansY float32) Affine2D: Applies a shearing transformation to the matrix with specified angles.
 //   - Mul(other Affine2D) Affine2D: Multiplies the current transformation by another transformation.
-//   - Invert() Affine2D: Computes the inverse transformation, if possible.
+//   - Invert() Affine2D: Computes the inverse transformation, returning the identity for singular matrices.
+//   - TryInvert(tolerance float32) (Affine2D, error): Computes the inverse transformation, returning ErrSingularMatrix for singular or ill-conditioned matrices.
+//   - Determinant() float32: Returns the determinant of the linear part.
+//   - Cond() float32: Returns the condition number of the linear part.
 //   - Transform(p Point) Point: Applies the transformation to a point and returns a new point.
+//   - TransformPoints(dst, src []Point) int: Applies the transformation to a slice of points without allocating.
+//   - TransformXY(dst, src []float32) int: Applies the transformation to interleaved x, y coordinates without allocating.
 //   - Elems() (sx, hx, ox, hy, sy, oy float32): Returns the elements of the transformation matrix.
 //   - Split() (Affine2D, Point): Splits the transformation into a matrix without translation and a translation vector.
 //   - String() string: Returns a string representation of the transformation matrix in the format "[[sx hx ox] [hy sy oy]]".
 //
+// # Rect Type
+//
+// The Rect type represents an axis-aligned rectangle with Min and Max corners.
+// Its semantics mirror image.Rectangle: Min is inclusive, Max is exclusive and
+// a rectangle with no points is empty.
+//
+// Key methods:
+//   - NewRect(x0, y0, x1, y1 float32) Rect: Creates a well-formed rectangle from two corners.
+//   - Union, Intersect(other Rect) Rect: Combine rectangles.
+//   - Contains(p Point) bool, Overlaps(other Rect) bool, In(other Rect) bool: Test containment and overlap.
+//   - Inset(n float32) Rect, Add(p Point) Rect, Sub(p Point) Rect: Shrink, grow and translate.
+//   - Center() Point, Size() Point, Dx() float32, Dy() float32: Report the geometry.
+//   - Empty() bool, Canon() Rect, Eq(other Rect) bool: Handle empty and malformed rectangles.
+//   - RectFromImage(r image.Rectangle) Rect, RoundOut() image.Rectangle, RoundIn() image.Rectangle: Convert to and from integer rectangles.
+//   - Affine2D.TransformRect(r Rect) Rect: Returns the axis-aligned bounds of a transformed rectangle.
+//
+// # Segment, Ray and Line Types
+//
+// Segment{A, B}, Ray{Origin, Dir} and Line{Origin, Dir} describe linear
+// primitives. Each provides At, Project, ClosestPoint, Distance and Transform,
+// and Segment additionally Length, Midpoint and Bounds. Segment.Intersect,
+// Ray.IntersectSegment and Line.Intersect return an Intersection holding the
+// hit point, its parameters along both primitives and, for collinear
+// primitives, the end of the shared portion.
+//
+// # Circle and Ellipse Types
+//
+// Circle{Center, Radius} and Ellipse{Center, Radii, Rotation} provide Area,
+// Perimeter, Contains, ClosestPoint, Distance and Bounds, as well as
+// intersections with lines, segments, circles and ellipses. Transforming a
+// circle with Affine2D yields an Ellipse, since shear and non-uniform scaling
+// do not preserve circles; Ellipse.Transform computes the exact image.
+//
+// # Ring and Polygon Types
+//
+// A Ring is a closed sequence of points and a Polygon is an outer Ring with
+// optional holes. Both provide SignedArea (shoelace formula), Area, Centroid,
+// Orientation, Reverse, Perimeter, Bounds, IsConvex and Transform, and report
+// point containment under the EvenOdd or NonZero FillRule. Polygon.Orient
+// normalizes the winding of the outer ring and the holes.
+//
+// ConvexHull(points []Point) Ring computes the counterclockwise convex hull of a
+// point set with Andrew's monotone chain, and ConvexHullCollinear also keeps the
+// points lying on its edges. Duplicate and collinear inputs yield degenerate rings
+// of one or two points instead of failing.
+//
+// Polygon.Triangulate splits a polygon with holes into triangles by ear clipping,
+// for filling polygons in software renderers and exporting meshes. It bridges the
+// holes into the outer ring and returns vertex indices, three per triangle, into
+// the outer ring followed by the holes. Polygon.TriangulationDeviation compares the
+// total area of the triangles with the area of the polygon to check the result.
+//
+// # Robust Predicates
+//
+// Orient2D(a, b, c Point) Orientation and InCircle(a, b, c, d Point) int decide the
+// turn direction of three points and whether a point lies inside the circle through
+// three others. Unlike the sign of Point.Cross in float32, their answers are exact,
+// so algorithms built on them, such as ConvexHull, never see contradictory results.
+// They follow Shewchuk's adaptive approach: a float64 evaluation with a proven error
+// bound decides almost every call, and exact arithmetic settles the rest. Orient2D64
+// and InCircle64 accept Point64.
+//
+// # Delaunay Triangulation
+//
+// Delaunay(points []Point) Triangulation builds the Delaunay triangulation of a
+// point set by Bowyer–Watson insertion, for terrain meshes and neighbor graphs. A
+// Triangulation lists counterclockwise triangles as point indices together with
+// the neighbor across every edge, and Edges returns the edge graph, which links
+// every point to its nearest neighbor. ConstrainedDelaunay also forces given edges,
+// such as polygon boundaries, into the mesh, and reports crossing constraints with
+// ErrInvalidConstraint.
+//
+// # Bézier Curves
+//
+// QuadBezier{P0, P1, P2} and CubicBezier{P0, P1, P2, P3} evaluate points with
+// At and provide Derivative, unit Tangent and Normal, de Casteljau subdivision
+// with Split, tight Bounds from the extrema of the curve, ClosestPoint and
+// Project for the nearest point, and exact Transform by an Affine2D.
+// QuadBezier.Elevate returns the equivalent cubic curve.
+//
+// An Arc{Ellipse, Start, Sweep} is a portion of an ellipse between two
+// parametric angles, with At, tight Bounds and exact Transform.
+//
+// # Flattening
+//
+// QuadBezier, CubicBezier and Arc provide Flatten(tolerance float32) []Point,
+// which approximates the curve by a polyline that never deviates from it by
+// more than the tolerance. Béziers are subdivided adaptively and arcs are split
+// into equal angular steps; Ellipse.Flatten returns a Ring. A non-positive
+// tolerance selects DefaultFlatness.
+//
+// # Path Type
+//
+// A Path records MoveTo, LineTo, QuadTo, CubicTo, ArcTo and Close commands
+// forming any number of open or closed subpaths; ArcTo follows the endpoint
+// parameterization of SVG. Rectangles, rings, polygons, ellipses and arcs are
+// added with AddRect, AddRing, AddPolygon, AddEllipse and AddArc. Segments
+// iterates the commands as PathSegment values, Bounds returns tight bounds and
+// Transform applies an Affine2D. Flatten converts the subpaths to rings for
+// filling and Polylines to polylines for stroking.
+//
+// ParseSVGPath(d string) (*Path, error) reads SVG path data with all absolute
+// and relative commands and the compact number syntax, reporting malformed
+// input as a *ParseError with the byte offset of the problem. Path.SVGPath
+// writes minimal path data rounded to a configurable precision.
+//
+// # SVG and CSS Transforms
+//
+// ParseTransform(s string) (Affine2D, error) reads SVG transform attributes and
+// CSS transform properties: any sequence of matrix, translate, scale, rotate
+// (optionally about a center), skewX, skewY and their CSS variants, with CSS
+// angle and length units. Affine2D.SVGMatrix formats a transformation as
+// matrix(a,b,c,d,e,f) and Affine2D.SVGTransform as a compact translate, rotate,
+// skewX, scale list derived from Decompose.
+//
+// # Decomposition
+//
+// Affine2D.Decompose splits a transformation into a Decomposition holding its
+// translation, rotation, scale and skew, following the translate(), rotate(),
+// skewX(), scale() order used by SVG and CSS. Reflections are represented by a
+// negative Y scale. Decomposition.Compose rebuilds the transformation.
+//
+// Interpolate(a, b Affine2D, t float32) Affine2D blends two transformations
+// through their decompositions, interpolating rotation along the shortest arc,
+// which keeps intermediate steps of an animation free of distortion.
+//
+// # Projective2D Type
+//
+// The Projective2D type represents a projective transformation (homography)
+// described by a full 3x3 matrix. Unlike Affine2D it can express perspective,
+// which is needed for document rectification and pseudo-3D effects.
+//
+// Key methods:
+//   - NewProjective2D(sx, hx, ox, hy, sy, oy, px, py, w float32) Projective2D: Creates a transformation from the matrix elements.
+//   - QuadToQuad(src, dst [4]Point) (Projective2D, error): Maps the vertices of one quadrilateral onto another.
+//   - Affine2D.ToProjective() Projective2D: Converts an affine transformation.
+//   - ToAffine() (Affine2D, bool): Converts back to an affine transformation when there is no perspective.
+//   - Mul(other Projective2D) Projective2D: Multiplies the current transformation by another transformation.
+//   - Invert() Projective2D, TryInvert(tolerance float32) (Projective2D, error): Compute the inverse transformation.
+//   - Transform(p Point) Point: Applies the transformation to a point, including the perspective division.
+//
+// # Fitting
+//
+// Transformations can be estimated from point correspondences by least squares.
+// Each function returns the transformation together with Residuals statistics:
+//   - FitAffine(src, dst []Point) (Affine2D, Residuals, error): General affine fit.
+//   - FitSimilarity(src, dst []Point) (Affine2D, Residuals, error): Rotation, uniform scale and translation (Umeyama).
+//   - FitRigid(src, dst []Point) (Affine2D, Residuals, error): Rotation and translation (Procrustes).
+//
+// FitRANSAC(src, dst []Point, opts RANSACOptions) (RANSACResult, error) estimates
+// any of these models robustly when the correspondences contain outliers. The
+// inlier threshold, iteration budget and random seed are set in RANSACOptions,
+// and the result reports the refined transformation, inlier mask and score.
+//
+// # Double Precision
+//
+// The Point64 and Affine2D64 types are float64 counterparts of Point and
+// Affine2D with the same method set. They are intended for pipelines such as
+// CAD or mapping where chained transformations quickly exhaust float32
+// precision.
+//
+// Conversion helpers:
+//   - Point.To64() Point64, Affine2D.To64() Affine2D64: Lossless conversion to double precision.
+//   - Point64.To32() Point, Affine2D64.To32() Affine2D: Conversion to single precision, rounding to the nearest float32.
+//
+// # Encoding
+//
+// Point, Affine2D and their double precision counterparts implement
+// encoding.TextMarshaler and json.Marshaler together with the matching
+// unmarshalers. The text form matches String, "(x, y)" and
+// "[[sx hx ox] [hy sy oy]]", but uses the shortest digits that parse back to the
+// same value. In JSON a point is an object {"x": x, "y": y} and a transformation
+// an array of its six elements in the order of Elems; the text form is accepted
+// as a JSON string too. ParsePoint and ParseAffine2D read the text form, so the
+// types can be used directly with flag.TextVar.
+//
+// For compact storage and network transfer, Point, Affine2D and Ring also
+// implement encoding.BinaryMarshaler with little-endian float32 values behind
+// a version byte. AppendPoints(dst []byte, pts []Point, opts BinaryOptions)
+// encodes point sequences, optionally quantized to int16 or int32 with a scale
+// and stored as zig-zag varint differences, which suits polylines with short
+// edges. DecodePoints reads every variant back.
+//
+// # Well-Known Text and Binary
+//
+// The Geometry interface covers the simple features used by GIS systems: Point,
+// LineString, Polygon, MultiPoint, MultiLineString, MultiPolygon and
+// GeometryCollection. ParseWKT and FormatWKT read and write Well-Known Text, and
+// DecodeWKB, AppendWKB and AppendEWKB read and write Well-Known Binary, including
+// the SRID header of the extended WKB of PostGIS. The readers accept Z and M
+// values and discard them, require closed polygon rings, and report malformed
+// input as a *ParseError with the offset of the problem.
+//
+// # GeoJSON
+//
+// MarshalGeoJSON and UnmarshalGeoJSON convert geometries to and from GeoJSON
+// geometry objects. Feature and FeatureCollection implement json.Marshaler and
+// json.Unmarshaler, carrying identifiers, arbitrary properties and bbox members.
+// Written polygons follow the right-hand rule of RFC 7946, with counterclockwise
+// outer rings and clockwise holes, while the winding of parsed rings is kept.
+// GeometryBounds computes a bounding box for the bbox member.
+//
 // The package is designed for integration into graphical applications and
 // for processing 2D geometry. It is useful for both educational and production
 // projects where working with points and affine transformations in two-dimensional
@@ -0,0 +1,293 @@
+package tochka
+
+import (
+	"math"
+	"sort"
+)
+
+// ellipseSamples is the number of intervals in which the boundary of an ellipse is sampled
+// when searching for the intersections with another ellipse.
+const ellipseSamples = 360
+
+// Ellipse represents an ellipse with the specified center, radii along its own X and Y axes,
+// and rotation of those axes in radians.
+type Ellipse struct {
+	Center   Point
+	Radii    Point
+	Rotation float32
+}
+
+// NewEllipse creates an ellipse with the specified center, radii and rotation in radians.
+func NewEllipse(center Point, rx, ry, rotation float32) Ellipse {
+	return Ellipse{Center: center, Radii: Point{X: rx, Y: ry}, Rotation: rotation}
+}
+
+// Area returns the area of the ellipse.
+func (e Ellipse) Area() float32 {
+	return float32(math.Pi * math.Abs(float64(e.Radii.X)*float64(e.Radii.Y)))
+}
+
+// Perimeter returns an approximation of the perimeter of the ellipse using Ramanujan's
+// second formula, whose relative error stays below 1e-9 for ellipses with a ratio
+// of radii above 1/10, and is exact for circles.
+func (e Ellipse) Perimeter() float32 {
+	a, b := math.Abs(float64(e.Radii.X)), math.Abs(float64(e.Radii.Y))
+	if a+b == 0 {
+		return 0
+	}
+	h := (a - b) * (a - b) / ((a + b) * (a + b))
+	return float32(math.Pi * (a + b) * (1 + 3*h/(10+math.Sqrt(4-3*h))))
+}
+
+// Contains reports whether the point p lies inside the ellipse or on its boundary.
+// A degenerate ellipse with a zero radius contains no points.
+func (e Ellipse) Contains(p Point) bool {
+	inv, err := e.unitMap().TryInvert(0)
+	if err != nil {
+		return false
+	}
+	q := inv.Transform(p.To64())
+	return q.Dot(q) <= 1
+}
+
+// ClosestPoint returns the point on the boundary of the ellipse closest to p.
+func (e Ellipse) ClosestPoint(p Point) Point {
+	sin, cos := math.Sincos(float64(e.Rotation))
+	d := p.To64().Sub(e.Center.To64())
+	// Express p in the frame of the ellipse axes.
+	x, y := d.X*cos+d.Y*sin, -d.X*sin+d.Y*cos
+	x, y = closestOnAxisEllipse(math.Abs(float64(e.Radii.X)), math.Abs(float64(e.Radii.Y)), x, y)
+	return Point64{
+		X: x*cos - y*sin,
+		Y: x*sin + y*cos,
+	}.Add(e.Center.To64()).To32()
+}
+
+// Distance returns the distance between p and the boundary of the ellipse.
+func (e Ellipse) Distance(p Point) float32 {
+	return e.ClosestPoint(p).Distance(p)
+}
+
+// Bounds returns the axis-aligned bounds of the ellipse.
+func (e Ellipse) Bounds() Rect {
+	sin, cos := math.Sincos(float64(e.Rotation))
+	rx, ry := float64(e.Radii.X), float64(e.Radii.Y)
+	hw := float32(math.Hypot(rx*cos, ry*sin))
+	hh := float32(math.Hypot(rx*sin, ry*cos))
+	return Rect{
+		Min: Point{X: e.Center.X - hw, Y: e.Center.Y - hh},
+		Max: Point{X: e.Center.X + hw, Y: e.Center.Y + hh},
+	}
+}
+
+// Transform applies the transformation a to the ellipse. The image of an ellipse under an
+// affine transformation is again an ellipse, which is computed exactly. The resulting Radii.X
+// is the major radius and Rotation lies in (-π/2, π/2].
+func (e Ellipse) Transform(a Affine2D) Ellipse {
+	return ellipseFromUnitMap(a.To64().Mul(e.unitMap()))
+}
+
+// IntersectLine returns the intersection points of the ellipse boundary and the line,
+// ordered along the line. A tangent line yields a single point.
+func (e Ellipse) IntersectLine(l Line) []Point {
+	return e.intersectLinear(l.Origin, l.Dir, math.Inf(-1), math.Inf(1))
+}
+
+// IntersectSegment returns the intersection points of the ellipse boundary and the segment,
+// ordered from A to B.
+func (e Ellipse) IntersectSegment(s Segment) []Point {
+	return e.intersectLinear(s.A, s.B.Sub(s.A), 0, 1)
+}
+
+// IntersectEllipse returns the intersection points of the boundaries of two ellipses.
+// Tangent ellipses yield their points of contact. Coincident ellipses have infinitely many
+// common points, in which case no points are returned.
+func (e Ellipse) IntersectEllipse(o Ellipse) []Point {
+	base, other := e.unitMap(), o.unitMap()
+	inv, err := base.TryInvert(0)
+	if err != nil {
+		base, other = other, base
+		if inv, err = base.TryInvert(0); err != nil {
+			return nil
+		}
+	}
+	// Map the first ellipse onto the unit circle and express the second one by the implicit
+	// equation f(x, y) = |M⁻¹(x, y)|² - 1, then search the roots of f along the unit circle.
+	m, err := inv.Mul(other).TryInvert(0)
+	if err != nil {
+		return nil
+	}
+	f := func(theta float64) float64 {
+		sin, cos := math.Sincos(theta)
+		q := m.Transform(Point64{X: cos, Y: sin})
+		return q.Dot(q) - 1
+	}
+	var points []Point
+	for _, theta := range trigRoots(f) {
+		sin, cos := math.Sincos(theta)
+		points = append(points, base.Transform(Point64{X: cos, Y: sin}).To32())
+	}
+	return points
+}
+
+// unitMap returns the transformation that maps the unit circle onto the ellipse.
+func (e Ellipse) unitMap() Affine2D64 {
+	sin, cos := math.Sincos(float64(e.Rotation))
+	rx, ry := float64(e.Radii.X), float64(e.Radii.Y)
+	return NewAffine2D64(
+		rx*cos, -ry*sin, float64(e.Center.X),
+		rx*sin, ry*cos, float64(e.Center.Y),
+	)
+}
+
+// ellipseFromUnitMap returns the ellipse that is the image of the unit circle under m.
+func ellipseFromUnitMap(m Affine2D64) Ellipse {
+	p, q, ox, r, s, oy := m.Elems()
+	// The axes of the ellipse are the eigenvectors of M*Mᵀ
+	// and the radii are the singular values of M.
+	a, b, c := p*p+q*q, p*r+q*s, r*r+s*s
+	rotation := math.Atan2(2*b, a-c) / 2
+	smax, smin := m.singularValues()
+	return Ellipse{
+		Center:   Point{X: float32(ox), Y: float32(oy)},
+		Radii:    Point{X: float32(smax), Y: float32(smin)},
+		Rotation: float32(rotation),
+	}
+}
+
+// intersectLinear intersects the ellipse boundary with the primitive p + t*d for t in [tmin, tmax].
+func (e Ellipse) intersectLinear(p, d Point, tmin, tmax float64) []Point {
+	inv, err := e.unitMap().TryInvert(0)
+	if err != nil {
+		return nil
+	}
+	// In the frame where the ellipse is the unit circle solve |o + t*v|² = 1.
+	linear, _ := inv.Split()
+	o := inv.Transform(p.To64())
+	v := linear.Transform(d.To64())
+	qa, qb, qc := v.Dot(v), o.Dot(v), o.Dot(o)-1
+	if qa == 0 {
+		return nil
+	}
+	// The discriminant divided by qa is 1 - d² for the distance d between the line
+	// and the center in the unit frame, so tangency is judged relative to the unit radius.
+	disc := qb*qb - qa*qc
+	tol := parallelEpsilon * qa
+	var ts []float64
+	switch {
+	case disc < -tol:
+		return nil
+	case disc <= tol:
+		ts = []float64{-qb / qa}
+	default:
+		sq := math.Sqrt(disc)
+		ts = []float64{(-qb - sq) / qa, (-qb + sq) / qa}
+	}
+	var points []Point
+	p64, d64 := p.To64(), d.To64()
+	for _, t := range ts {
+		if inRange(t, tmin, tmax) {
+			points = append(points, p64.Add(d64.Mul(t)).To32())
+		}
+	}
+	return points
+}
+
+// closestOnAxisEllipse returns the point of the axis-aligned ellipse with radii a and b centered
+// at the origin that is closest to (x, y). It iterates on the evolute of the ellipse in the first
+// quadrant, which converges for points both inside and outside the ellipse.
+func closestOnAxisEllipse(a, b, x, y float64) (float64, float64) {
+	// A degenerate ellipse is a segment along one of the axes.
+	switch {
+	case a == 0:
+		return 0, math.Min(b, math.Max(-b, y))
+	case b == 0:
+		return math.Min(a, math.Max(-a, x)), 0
+	}
+	px, py := math.Abs(x), math.Abs(y)
+	tx, ty := math.Sqrt2/2, math.Sqrt2/2
+	for i := 0; i < 8; i++ {
+		ex := (a*a - b*b) * tx * tx * tx / a
+		ey := (b*b - a*a) * ty * ty * ty / b
+		rx, ry := a*tx-ex, b*ty-ey
+		qx, qy := px-ex, py-ey
+		r, q := math.Hypot(rx, ry), math.Hypot(qx, qy)
+		if q == 0 {
+			break
+		}
+		tx = math.Min(1, math.Max(0, (qx*r/q+ex)/a))
+		ty = math.Min(1, math.Max(0, (qy*r/q+ey)/b))
+		t := math.Hypot(tx, ty)
+		tx, ty = tx/t, ty/t
+	}
+	return math.Copysign(a*tx, x), math.Copysign(b*ty, y)
+}
+
+// trigRoots returns the roots of the periodic function f in [0, 2π). It samples f at
+// ellipseSamples points, refines every sign change by bisection and accepts local minima
+// of |f| within parallelEpsilon of zero as tangent roots. If f vanishes within parallelEpsilon
+// at every sample, the roots are not isolated and none are returned.
+func trigRoots(f func(float64) float64) []float64 {
+	const n = ellipseSamples
+	step := 2 * math.Pi / n
+	values := make([]float64, n)
+	var scale float64
+	for k := range values {
+		values[k] = f(float64(k) * step)
+		scale = math.Max(scale, math.Abs(values[k]))
+	}
+	if scale <= parallelEpsilon {
+		return nil
+	}
+
+	var roots []float64
+	for k := 0; k < n; k++ {
+		lo, hi := float64(k)*step, float64(k+1)*step
+		flo, fhi := values[k], values[(k+1)%n]
+		switch {
+		case flo == 0:
+			roots = append(roots, lo)
+		case flo*fhi < 0:
+			for i := 0; i < 60; i++ {
+				mid := (lo + hi) / 2
+				if fm := f(mid); fm*flo > 0 {
+					lo, flo = mid, fm
+				} else {
+					hi = mid
+				}
+			}
+			roots = append(roots, (lo+hi)/2)
+		default:
+			// Look for a tangency between samples k-1 and k+1 where |f| dips to zero.
+			prev := values[(k+n-1)%n]
+			if math.Abs(flo) > math.Abs(prev) || math.Abs(flo) > math.Abs(fhi) || prev*flo < 0 {
+				continue
+			}
+			a, b := lo-step, hi
+			for i := 0; i < 100; i++ {
+				m1, m2 := a+(b-a)/3, b-(b-a)/3
+				if math.Abs(f(m1)) < math.Abs(f(m2)) {
+					b = m2
+				} else {
+					a = m1
+				}
+			}
+			if theta := (a + b) / 2; math.Abs(f(theta)) <= parallelEpsilon {
+				roots = append(roots, math.Mod(theta+2*math.Pi, 2*math.Pi))
+			}
+		}
+	}
+	sort.Float64s(roots)
+	// Remove roots found twice, at the ends of neighbouring intervals.
+	unique := roots[:0]
+	for k, r := range roots {
+		if k > 0 && r-unique[len(unique)-1] < 1e-9 {
+			continue
+		}
+		unique = append(unique, r)
+	}
+	if len(unique) > 1 && unique[0]+2*math.Pi-unique[len(unique)-1] < 1e-9 {
+		unique = unique[:len(unique)-1]
+	}
+	return unique
+}
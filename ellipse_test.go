@@ -0,0 +1,125 @@
+package tochka
+
+import (
+	"math"
+	"testing"
+)
+
+// TestEllipse_Measures tests the area, perimeter and bounds of an ellipse.
+func TestEllipse_Measures(t *testing.T) {
+	e := NewEllipse(Point{X: 0, Y: 0}, 4, 2, 0)
+	if !almostEqual(e.Area(), 8*math.Pi, 1e-5) {
+		t.Errorf("Area() = %v; want 8π", e.Area())
+	}
+	// The perimeter of an ellipse with radii 4 and 2 is 19.376806...
+	if !almostEqual(e.Perimeter(), 19.376806, 1e-4) {
+		t.Errorf("Perimeter() = %v; want 19.376806", e.Perimeter())
+	}
+	if !almostEqual(NewEllipse(Point{}, 3, 3, 1).Perimeter(), 6*math.Pi, 1e-5) {
+		t.Errorf("Perimeter() of a circle is not exact")
+	}
+	b := NewEllipse(Point{X: 1, Y: 1}, 4, 2, math.Pi/2).Bounds()
+	if !pointAlmostEqual(b.Min, Point{X: -1, Y: -3}, 1e-5) || !pointAlmostEqual(b.Max, Point{X: 3, Y: 5}, 1e-5) {
+		t.Errorf("Bounds() = %v; want (-1, -3)-(3, 5)", b)
+	}
+}
+
+// TestEllipse_Nearest tests containment and closest points inside and outside the ellipse.
+func TestEllipse_Nearest(t *testing.T) {
+	e := NewEllipse(Point{X: 1, Y: 1}, 4, 2, math.Pi/6)
+	sin, cos := math.Sincos(math.Pi / 6)
+	local := func(x, y float64) Point {
+		return Point{X: float32(1 + x*cos - y*sin), Y: float32(1 + x*sin + y*cos)}
+	}
+	if !e.Contains(local(3.9, 0)) || e.Contains(local(0, 2.1)) {
+		t.Errorf("Contains() failed")
+	}
+	tests := []struct{ p, want Point }{
+		{local(10, 0), local(4, 0)},
+		{local(0, -5), local(0, -2)},
+		{local(0, 0.5), local(0, 2)},
+	}
+	for _, tt := range tests {
+		if got := e.ClosestPoint(tt.p); !pointAlmostEqual(got, tt.want, 1e-4) {
+			t.Errorf("ClosestPoint(%v) = %v; want %v", tt.p, got, tt.want)
+		}
+	}
+	// The segment to a closest point is normal to the ellipse, so no point of the
+	// boundary can be closer.
+	p := local(3, 3)
+	d := e.Distance(p)
+	for k := 0; k < 360; k++ {
+		s, c := math.Sincos(float64(k) * math.Pi / 180)
+		if q := local(4*c, 2*s); q.Distance(p) < d-1e-4 {
+			t.Fatalf("Distance() = %v, but %v is %v away", d, q, q.Distance(p))
+		}
+	}
+}
+
+// TestEllipse_IntersectLine tests the intersection with lines in a rotated ellipse.
+func TestEllipse_IntersectLine(t *testing.T) {
+	e := NewEllipse(Point{X: 0, Y: 0}, 4, 2, math.Pi/2)
+	got := e.IntersectLine(Line{Origin: Point{X: 0, Y: -10}, Dir: Point{X: 0, Y: 1}})
+	if len(got) != 2 || !pointAlmostEqual(got[0], Point{X: 0, Y: -4}, 1e-5) || !pointAlmostEqual(got[1], Point{X: 0, Y: 4}, 1e-5) {
+		t.Errorf("IntersectLine() = %v; want (0, -4), (0, 4)", got)
+	}
+	if got := e.IntersectLine(Line{Origin: Point{X: 2, Y: 0}, Dir: Point{X: 0, Y: 1}}); len(got) != 1 {
+		t.Errorf("IntersectLine() tangent = %v; want one point", got)
+	}
+}
+
+// TestEllipse_IntersectEllipse tests crossing, tangent and disjoint ellipses.
+func TestEllipse_IntersectEllipse(t *testing.T) {
+	e := NewEllipse(Point{}, 4, 2, 0)
+	got := e.IntersectEllipse(NewEllipse(Point{}, 4, 2, math.Pi/2))
+	if len(got) != 4 {
+		t.Fatalf("IntersectEllipse() = %v; want four points", got)
+	}
+	// The crossing points of the two ellipses lie on the diagonals, at |x| = |y| = 4/√5.
+	v := float32(4 / math.Sqrt(5))
+	for _, p := range got {
+		if !almostEqual(float32(math.Abs(float64(p.X))), v, 1e-4) || !almostEqual(float32(math.Abs(float64(p.Y))), v, 1e-4) {
+			t.Errorf("IntersectEllipse() point %v; want (±%v, ±%v)", p, v, v)
+		}
+	}
+
+	got = e.IntersectEllipse(NewEllipse(Point{X: 6, Y: 0}, 2, 1, 0))
+	if len(got) != 1 || !pointAlmostEqual(got[0], Point{X: 4, Y: 0}, 1e-3) {
+		t.Errorf("IntersectEllipse() tangent = %v; want (4, 0)", got)
+	}
+	if got := e.IntersectEllipse(NewEllipse(Point{X: 20, Y: 0}, 2, 1, 0)); got != nil {
+		t.Errorf("IntersectEllipse() disjoint = %v; want none", got)
+	}
+	if got := e.IntersectEllipse(e); got != nil {
+		t.Errorf("IntersectEllipse() coincident = %v; want none", got)
+	}
+	got = NewCircle(Point{}, 3).IntersectEllipse(e)
+	for _, p := range got {
+		if d := p.Magnitude(); !almostEqual(d, 3, 1e-4) {
+			t.Errorf("IntersectEllipse() point %v is not on the circle", p)
+		}
+	}
+	if len(got) != 4 {
+		t.Errorf("Circle.IntersectEllipse() = %v; want four points", got)
+	}
+}
+
+// TestEllipse_Transform tests that transformations compose on ellipses.
+func TestEllipse_Transform(t *testing.T) {
+	e := NewEllipse(Point{X: 1, Y: 2}, 3, 1, 0.4)
+	a := NewAffine2D(1.5, 0.4, 2, -0.3, 0.8, 1)
+	b := Affine2D{}.Rotate(Point{X: 3, Y: 3}, 1.1)
+	if got, want := e.Transform(a).Transform(b), e.Transform(b.Mul(a)); !ellipseAlmostEqual(got, want, 1e-4) {
+		t.Errorf("Transform() = %+v; want %+v", got, want)
+	}
+}
+
+// ellipseAlmostEqual compares two ellipses within a given precision, treating rotations
+// that differ by a half turn as equal.
+func ellipseAlmostEqual(a, b Ellipse, epsilon float32) bool {
+	if !pointAlmostEqual(a.Center, b.Center, epsilon) || !pointAlmostEqual(a.Radii, b.Radii, epsilon) {
+		return false
+	}
+	d := math.Remainder(float64(a.Rotation)-float64(b.Rotation), math.Pi)
+	return math.Abs(d) < float64(epsilon) || a.Radii.X == a.Radii.Y
+}
@@ -0,0 +1,404 @@
+package tochka
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// pointJSON is the JSON representation of Point.
+type pointJSON struct {
+	X float32 `json:"x"`
+	Y float32 `json:"y"`
+}
+
+// point64JSON is the JSON representation of Point64.
+type point64JSON struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// ParsePoint parses a point in the form printed by String and MarshalText, "(x, y)".
+// Any number of decimal places and exponents are accepted, as are NaN, +Inf and -Inf
+// and whitespace around the elements. Malformed input yields a *ParseError.
+func ParsePoint(s string) (Point, error) {
+	v, _, err := parseElems(s, "(n,n)", 32)
+	if err != nil {
+		return Point{}, err
+	}
+	return Point{X: float32(v[0]), Y: float32(v[1])}, nil
+}
+
+// ParsePoint64 parses a point in the form printed by String and MarshalText, "(x, y)".
+// Any number of decimal places and exponents are accepted, as are NaN, +Inf and -Inf
+// and whitespace around the elements. Malformed input yields a *ParseError.
+func ParsePoint64(s string) (Point64, error) {
+	v, _, err := parseElems(s, "(n,n)", 64)
+	if err != nil {
+		return Point64{}, err
+	}
+	return Point64{X: v[0], Y: v[1]}, nil
+}
+
+// ParseAffine2D parses a transformation in the form printed by String and MarshalText,
+// "[[sx hx ox] [hy sy oy]]". Any number of decimal places and exponents are accepted,
+// as are NaN, +Inf, -Inf and additional whitespace. Malformed input yields a *ParseError.
+func ParseAffine2D(s string) (Affine2D, error) {
+	_, text, err := parseElems(s, "[[n n n][n n n]]", 32)
+	if err != nil {
+		return Affine2D{}, err
+	}
+	v, err := affineElems(text, 32)
+	if err != nil {
+		return Affine2D{}, err
+	}
+	return Affine2D{
+		a: float32(v[0]), b: float32(v[1]), c: float32(v[2]),
+		d: float32(v[3]), e: float32(v[4]), f: float32(v[5]),
+	}, nil
+}
+
+// ParseAffine2D64 parses a transformation in the form printed by String and MarshalText,
+// "[[sx hx ox] [hy sy oy]]". Any number of decimal places and exponents are accepted,
+// as are NaN, +Inf, -Inf and additional whitespace. Malformed input yields a *ParseError.
+func ParseAffine2D64(s string) (Affine2D64, error) {
+	_, text, err := parseElems(s, "[[n n n][n n n]]", 64)
+	if err != nil {
+		return Affine2D64{}, err
+	}
+	v, err := affineElems(text, 64)
+	if err != nil {
+		return Affine2D64{}, err
+	}
+	return Affine2D64{a: v[0], b: v[1], c: v[2], d: v[3], e: v[4], f: v[5]}, nil
+}
+
+// MarshalText implements encoding.TextMarshaler. The point is written as "(x, y)",
+// like String but with as many digits as needed to parse back exactly.
+func (p Point) MarshalText() ([]byte, error) {
+	return formatElems(32, "(", ", ", ")", float64(p.X), float64(p.Y)), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using ParsePoint.
+func (p *Point) UnmarshalText(text []byte) error {
+	v, err := ParsePoint(string(text))
+	if err != nil {
+		return err
+	}
+	*p = v
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. The point is written as {"x": x, "y": y}.
+func (p Point) MarshalJSON() ([]byte, error) {
+	return json.Marshal(pointJSON{X: p.X, Y: p.Y})
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts the object written by MarshalJSON,
+// with keys matched case-insensitively, and a string in the form accepted by ParsePoint.
+func (p *Point) UnmarshalJSON(data []byte) error {
+	if isJSONString(data) {
+		return unmarshalJSONText(data, p)
+	}
+	var v pointJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*p = Point{X: v.X, Y: v.Y}
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler. The point is written as "(x, y)",
+// like String but with as many digits as needed to parse back exactly.
+func (p Point64) MarshalText() ([]byte, error) {
+	return formatElems(64, "(", ", ", ")", p.X, p.Y), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using ParsePoint64.
+func (p *Point64) UnmarshalText(text []byte) error {
+	v, err := ParsePoint64(string(text))
+	if err != nil {
+		return err
+	}
+	*p = v
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. The point is written as {"x": x, "y": y}.
+func (p Point64) MarshalJSON() ([]byte, error) {
+	return json.Marshal(point64JSON(p))
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts the object written by MarshalJSON,
+// with keys matched case-insensitively, and a string in the form accepted by ParsePoint64.
+func (p *Point64) UnmarshalJSON(data []byte) error {
+	if isJSONString(data) {
+		return unmarshalJSONText(data, p)
+	}
+	var v point64JSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*p = Point64(v)
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler. The transformation is written as
+// "[[sx hx ox] [hy sy oy]]", like String but with as many digits as needed to parse back exactly.
+func (a Affine2D) MarshalText() ([]byte, error) {
+	return formatAffine(affineTexts(32, float64(a.a), float64(a.b), float64(a.c), float64(a.d), float64(a.e), float64(a.f))), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using ParseAffine2D.
+func (a *Affine2D) UnmarshalText(text []byte) error {
+	v, err := ParseAffine2D(string(text))
+	if err != nil {
+		return err
+	}
+	*a = v
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. The transformation is written as the array
+// [sx, hx, ox, hy, sy, oy], in the order of Elems.
+func (a Affine2D) MarshalJSON() ([]byte, error) {
+	return marshalAffineJSON(affineTexts(32, float64(a.a), float64(a.b), float64(a.c), float64(a.d), float64(a.e), float64(a.f)))
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts the array written by MarshalJSON
+// and a string in the form accepted by ParseAffine2D.
+func (a *Affine2D) UnmarshalJSON(data []byte) error {
+	if isJSONString(data) {
+		return unmarshalJSONText(data, a)
+	}
+	v, err := unmarshalAffineJSON(data, 32)
+	if err != nil {
+		return err
+	}
+	*a = Affine2D{
+		a: float32(v[0]), b: float32(v[1]), c: float32(v[2]),
+		d: float32(v[3]), e: float32(v[4]), f: float32(v[5]),
+	}
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler. The transformation is written as
+// "[[sx hx ox] [hy sy oy]]", like String but with as many digits as needed to parse back exactly.
+func (a Affine2D64) MarshalText() ([]byte, error) {
+	return formatAffine(affineTexts(64, a.a, a.b, a.c, a.d, a.e, a.f)), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using ParseAffine2D64.
+func (a *Affine2D64) UnmarshalText(text []byte) error {
+	v, err := ParseAffine2D64(string(text))
+	if err != nil {
+		return err
+	}
+	*a = v
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. The transformation is written as the array
+// [sx, hx, ox, hy, sy, oy], in the order of Elems.
+func (a Affine2D64) MarshalJSON() ([]byte, error) {
+	return marshalAffineJSON(affineTexts(64, a.a, a.b, a.c, a.d, a.e, a.f))
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts the array written by MarshalJSON
+// and a string in the form accepted by ParseAffine2D64.
+func (a *Affine2D64) UnmarshalJSON(data []byte) error {
+	if isJSONString(data) {
+		return unmarshalJSONText(data, a)
+	}
+	v, err := unmarshalAffineJSON(data, 64)
+	if err != nil {
+		return err
+	}
+	*a = Affine2D64{a: v[0], b: v[1], c: v[2], d: v[3], e: v[4], f: v[5]}
+	return nil
+}
+
+// parseElems parses numbers embedded in the punctuation of layout. In the layout, n stands
+// for a number, a space for whitespace or a comma separating two numbers, and every other
+// character must appear literally. Whitespace is allowed around every element. Numbers may
+// be NaN, +Inf and -Inf, as strconv formats them, and finite numbers are checked to fit in
+// a float of bitSize bits. The text of every number is returned as well.
+func parseElems(s, layout string, bitSize int) ([]float64, []string, error) {
+	sc := textScanner{s: s}
+	var v []float64
+	var text []string
+	for k := 0; k < len(layout); k++ {
+		switch c := layout[k]; c {
+		case 'n':
+			sc.skipSpace()
+			start := sc.pos
+			x, ok := sc.nonFinite()
+			if !ok {
+				var err error
+				if x, err = sc.number(); err != nil {
+					return nil, nil, err
+				}
+				if bitSize == 32 && math.IsInf(float64(float32(x)), 0) {
+					return nil, nil, sc.errorf("number out of range")
+				}
+			}
+			v = append(v, x)
+			text = append(text, s[start:sc.pos])
+		case ' ':
+			sc.skipSeparator()
+		default:
+			sc.skipSpace()
+			if sc.pos == len(s) || s[sc.pos] != c {
+				return nil, nil, sc.errorf("expected %q", c)
+			}
+			sc.pos++
+		}
+	}
+	sc.skipSpace()
+	if sc.pos != len(s) {
+		return nil, nil, sc.errorf("unexpected character %q", s[sc.pos])
+	}
+	return v, text, nil
+}
+
+// nonFinite parses NaN, +Inf or -Inf at the current position of the scanner, reporting
+// whether one was found.
+func (sc *textScanner) nonFinite() (float64, bool) {
+	for _, s := range []string{"NaN", "+Inf", "-Inf"} {
+		if strings.HasPrefix(sc.s[sc.pos:], s) {
+			sc.pos += len(s)
+			x, _ := strconv.ParseFloat(s, 64)
+			return x, true
+		}
+	}
+	return 0, false
+}
+
+// formatElems writes the values with the shortest representation that parses back
+// to the same float of bitSize bits, between the prefix and suffix and joined by sep.
+func formatElems(bitSize int, prefix, sep, suffix string, v ...float64) []byte {
+	buf := []byte(prefix)
+	for k, x := range v {
+		if k > 0 {
+			buf = append(buf, sep...)
+		}
+		buf = strconv.AppendFloat(buf, x, 'g', -1, bitSize)
+	}
+	return append(buf, suffix...)
+}
+
+// formatAffine writes the texts of the elements of a transformation as "[[sx hx ox] [hy sy oy]]".
+func formatAffine(text [6]string) []byte {
+	return fmt.Appendf(nil, "[[%s %s %s] [%s %s %s]]", text[0], text[1], text[2], text[3], text[4], text[5])
+}
+
+// marshalAffineJSON writes the texts of the elements of a transformation as a JSON array.
+func marshalAffineJSON(text [6]string) ([]byte, error) {
+	var v [6]json.Number
+	for k, s := range text {
+		v[k] = json.Number(s)
+	}
+	return json.Marshal(v)
+}
+
+// unmarshalAffineJSON decodes a JSON array of the six elements of a transformation and
+// returns them as by affineElems.
+func unmarshalAffineJSON(data []byte, bitSize int) ([]float64, error) {
+	var v []json.Number
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	if len(v) != 6 {
+		return nil, fmt.Errorf("affine transformation has %d elements; want 6", len(v))
+	}
+	text := make([]string, len(v))
+	for k, n := range v {
+		text[k] = n.String()
+	}
+	return affineElems(text, bitSize)
+}
+
+// affineTexts formats the fields of a transformation, in which the diagonal elements
+// a and e are stored as offsets from 1, as the texts of its six elements. Every text
+// parses back exactly with affineElems.
+func affineTexts(bitSize int, a, b, c, d, e, f float64) [6]string {
+	format := func(x float64) string { return strconv.FormatFloat(x, 'g', -1, bitSize) }
+	return [6]string{diagonalText(a, bitSize), format(b), format(c), format(d), diagonalText(e, bitSize), format(f)}
+}
+
+// affineElems parses the texts of the six elements of a transformation into floats of
+// bitSize bits. The diagonal elements are returned as offsets from 1, computed before
+// rounding so that transformations close to the identity keep their precision.
+func affineElems(text []string, bitSize int) ([]float64, error) {
+	v := make([]float64, len(text))
+	for k, s := range text {
+		x, err := strconv.ParseFloat(s, bitSize)
+		if err != nil {
+			return nil, err
+		}
+		if k == 0 || k == 4 {
+			x = diagonalOffset(s, x, bitSize)
+		}
+		v[k] = x
+	}
+	return v, nil
+}
+
+// diagonalText returns the shortest text of the diagonal element 1+x from which
+// diagonalOffset recovers x exactly, falling back to the exact decimal value of 1+x.
+func diagonalText(x float64, bitSize int) string {
+	for _, s := range []string{strconv.FormatFloat(x+1, 'g', -1, bitSize), strconv.FormatFloat(x+1, 'g', -1, 64)} {
+		if v, err := strconv.ParseFloat(s, bitSize); err == nil && diagonalOffset(s, v, bitSize) == x {
+			return s
+		}
+	}
+	if math.IsNaN(x) || math.IsInf(x, 0) {
+		return strconv.FormatFloat(x, 'g', -1, bitSize)
+	}
+	// The shortest decimal of x, "d.ddde±n", has len(ddd)-n fractional digits, and so does 1+x.
+	text := strconv.FormatFloat(x, 'e', -1, bitSize)
+	mantissa, exp, _ := strings.Cut(text, "e")
+	n, _ := strconv.Atoi(exp)
+	_, frac, _ := strings.Cut(mantissa, ".")
+	r, _ := new(big.Rat).SetString(text)
+	r.Add(r, big.NewRat(1, 1))
+	return r.FloatString(max(len(frac)-n, 0))
+}
+
+// diagonalOffset returns the offset from 1 of the diagonal element with text s and value v,
+// rounded to a float of bitSize bits. The subtraction is exact. Zero values are handled
+// without exact arithmetic, which also bounds the exponents it has to deal with.
+func diagonalOffset(s string, v float64, bitSize int) float64 {
+	if v != 0 {
+		if r, ok := new(big.Rat).SetString(s); ok {
+			r.Sub(r, big.NewRat(1, 1))
+			if bitSize == 32 {
+				x, _ := r.Float32()
+				return float64(x)
+			}
+			x, _ := r.Float64()
+			return x
+		}
+	}
+	if bitSize == 32 {
+		return float64(float32(v - 1))
+	}
+	return v - 1
+}
+
+// isJSONString reports whether the JSON value is a string.
+func isJSONString(data []byte) bool {
+	return len(data) > 0 && data[0] == '"'
+}
+
+// unmarshalJSONText decodes a JSON string and passes its contents to UnmarshalText.
+func unmarshalJSONText(data []byte, v interface{ UnmarshalText([]byte) error }) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	return v.UnmarshalText([]byte(s))
+}
@@ -0,0 +1,249 @@
+package tochka
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"math"
+	"testing"
+)
+
+// Compile-time checks that the types implement the encoding interfaces.
+var (
+	_ encoding.TextMarshaler   = Point{}
+	_ encoding.TextUnmarshaler = (*Point)(nil)
+	_ json.Marshaler           = Affine2D{}
+	_ json.Unmarshaler         = (*Affine2D)(nil)
+	_ encoding.TextMarshaler   = Point64{}
+	_ encoding.TextUnmarshaler = (*Affine2D64)(nil)
+)
+
+// TestParsePoint tests parsing the output of String and MarshalText.
+func TestParsePoint(t *testing.T) {
+	tests := []struct {
+		s    string
+		want Point
+	}{
+		{"(1.500000, -2.000000)", Point{X: 1.5, Y: -2}},
+		{" ( 1e3 ,-.25 ) ", Point{X: 1000, Y: -0.25}},
+		{Point{X: 0.1, Y: 3}.String(), Point{X: 0.1, Y: 3}},
+		{"(3.4028235e+38, 0)", Point{X: math.MaxFloat32}},
+	}
+	for _, tt := range tests {
+		if got, err := ParsePoint(tt.s); err != nil || got != tt.want {
+			t.Errorf("ParsePoint(%q) = %v, %v; want %v", tt.s, got, err, tt.want)
+		}
+	}
+	for _, s := range []string{"", "1, 2", "(1 2)", "(1, 2", "(1, 2) x", "(1e39, 0)"} {
+		var perr *ParseError
+		if _, err := ParsePoint(s); !errors.As(err, &perr) {
+			t.Errorf("ParsePoint(%q) error = %v; want a *ParseError", s, err)
+		}
+	}
+	if _, err := ParsePoint64("(1e39, 0)"); err != nil {
+		t.Errorf("ParsePoint64() error = %v; want large values accepted", err)
+	}
+}
+
+// TestParseAffine2D tests parsing the output of String and MarshalText.
+func TestParseAffine2D(t *testing.T) {
+	a := NewAffine2D(2, 0.5, -3, 0.25, 1.5, 1e6)
+	got, err := ParseAffine2D(a.String())
+	if err != nil || got != a {
+		t.Errorf("ParseAffine2D(%q) = %v, %v; want %v", a.String(), got, err, a)
+	}
+	if got, err := ParseAffine2D(" [ [1, 0, 0] [0 1 0] ] "); err != nil || got != (Affine2D{}) {
+		t.Errorf("ParseAffine2D() with commas = %v, %v; want the identity", got, err)
+	}
+	_, err = ParseAffine2D("[[1 0 0] [0 1]]")
+	var perr *ParseError
+	if !errors.As(err, &perr) || perr.Offset != 13 {
+		t.Errorf("ParseAffine2D() error = %v; want a *ParseError at offset 13", err)
+	}
+	a64 := NewAffine2D64(2, 0.5, -3, 0.25, 1.5, 1e300)
+	if got, err := ParseAffine2D64(a64.String()); err != nil || got != a64 {
+		t.Errorf("ParseAffine2D64(%q) = %v, %v; want %v", a64.String(), got, err, a64)
+	}
+}
+
+// TestText_RoundTrip tests that MarshalText preserves values exactly.
+func TestText_RoundTrip(t *testing.T) {
+	p := Point{X: float32(math.Pi), Y: -1e-20}
+	text, _ := p.MarshalText()
+	var q Point
+	if err := q.UnmarshalText(text); err != nil || q != p {
+		t.Errorf("UnmarshalText(%s) = %v, %v; want %v", text, q, err, p)
+	}
+
+	a := NewAffine2D(1.1, float32(math.Pi), 1e-7, -0.3, 0.9, 12345.678)
+	text, _ = a.MarshalText()
+	var b Affine2D
+	if err := b.UnmarshalText(text); err != nil || b != a {
+		t.Errorf("UnmarshalText(%s) = %v, %v; want %v", text, b, err, a)
+	}
+
+	p64 := Point64{X: math.Pi, Y: math.E}
+	text, _ = p64.MarshalText()
+	var q64 Point64
+	if err := q64.UnmarshalText(text); err != nil || q64 != p64 {
+		t.Errorf("UnmarshalText(%s) = %v, %v; want %v", text, q64, err, p64)
+	}
+
+	a64 := NewAffine2D64(1.1, math.Pi, 1e-7, -0.3, 0.9, 12345.678)
+	text, _ = a64.MarshalText()
+	var b64 Affine2D64
+	if err := b64.UnmarshalText(text); err != nil || b64 != a64 {
+		t.Errorf("UnmarshalText(%s) = %v, %v; want %v", text, b64, err, a64)
+	}
+}
+
+// TestText_NonFinite tests that MarshalText output with NaN and infinite values parses back.
+func TestText_NonFinite(t *testing.T) {
+	nan, inf := math.NaN(), math.Inf(1)
+	tests := []struct {
+		in  encoding.TextMarshaler
+		out interface {
+			encoding.TextMarshaler
+			encoding.TextUnmarshaler
+		}
+	}{
+		{Point{X: float32(inf), Y: 1}, new(Point)},
+		{Point{X: float32(nan), Y: float32(-inf)}, new(Point)},
+		{Point64{X: -inf, Y: nan}, new(Point64)},
+		{Affine2D{a: float32(nan), c: float32(inf)}, new(Affine2D)},
+		{Affine2D64{b: -inf, e: inf, f: nan}, new(Affine2D64)},
+	}
+	for _, tt := range tests {
+		text, err := tt.in.MarshalText()
+		if err != nil {
+			t.Fatalf("MarshalText() error = %v", err)
+		}
+		if err := tt.out.UnmarshalText(text); err != nil {
+			t.Errorf("UnmarshalText(%s) error = %v", text, err)
+			continue
+		}
+		// NaN is unequal to itself, so the values are compared by their text.
+		if again, _ := tt.out.MarshalText(); string(again) != string(text) {
+			t.Errorf("round trip of %s = %s", text, again)
+		}
+	}
+	if _, err := ParsePoint("(Inf, 0)"); err == nil {
+		t.Errorf("ParsePoint() of an unsigned Inf succeeded")
+	}
+}
+
+// TestAffine_NearIdentityRoundTrip tests that text and JSON preserve diagonal elements
+// that differ from 1 by less than their precision, which the types store as offsets.
+func TestAffine_NearIdentityRoundTrip(t *testing.T) {
+	inv := NewAffine2D(1, 1e-6, -5, -1e-6, 1.000001, -7).Invert()
+	for _, a := range []Affine2D{
+		inv,
+		{a: 1e-9, e: -3e-12},
+		{a: math.SmallestNonzeroFloat32, e: -math.MaxFloat32},
+		{a: 0x1p30, e: -0.75},
+	} {
+		text, _ := a.MarshalText()
+		var b Affine2D
+		if err := b.UnmarshalText(text); err != nil || b != a {
+			t.Errorf("UnmarshalText(%s) = %v, %v; want %#v", text, b, err, a)
+		}
+		data, err := a.MarshalJSON()
+		if err != nil {
+			t.Fatalf("MarshalJSON() error = %v", err)
+		}
+		var c Affine2D
+		if err := c.UnmarshalJSON(data); err != nil || c != a {
+			t.Errorf("UnmarshalJSON(%s) = %v, %v; want %#v", data, c, err, a)
+		}
+	}
+	for _, a := range []Affine2D64{
+		inv.To64(),
+		{a: 1e-20, e: 1e-20},
+		{a: -2.5e-300, e: 0x1p53},
+		{a: math.SmallestNonzeroFloat64, e: 1 - 0x1p-53},
+	} {
+		text, _ := a.MarshalText()
+		var b Affine2D64
+		if err := b.UnmarshalText(text); err != nil || b != a {
+			t.Errorf("UnmarshalText(%.40s) = %v, %v; want %#v", text, b, err, a)
+		}
+		data, err := a.MarshalJSON()
+		if err != nil {
+			t.Fatalf("MarshalJSON() error = %v", err)
+		}
+		var c Affine2D64
+		if err := c.UnmarshalJSON(data); err != nil || c != a {
+			t.Errorf("UnmarshalJSON(%.40s) = %v, %v; want %#v", data, c, err, a)
+		}
+	}
+	if text, _ := (Affine2D64{e: 1e-20}).MarshalText(); string(text) != "[[1 0 0] [0 1.00000000000000000001 0]]" {
+		t.Errorf("MarshalText() = %s; want the exact diagonal", text)
+	}
+}
+
+// TestJSON_RoundTrip tests JSON encoding of values nested in a document.
+func TestJSON_RoundTrip(t *testing.T) {
+	type scene struct {
+		Origin    Point      `json:"origin"`
+		Transform Affine2D   `json:"transform"`
+		Precise   Point64    `json:"precise"`
+		Matrix    Affine2D64 `json:"matrix"`
+	}
+	in := scene{
+		Origin:    Point{X: 1.25, Y: -3},
+		Transform: NewAffine2D(2, 0, 10, 0, 2, 20),
+		Precise:   Point64{X: math.Pi, Y: 0.1},
+		Matrix:    NewAffine2D64(1, 0.5, 0, 0, 1, 0),
+	}
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	want := `{"origin":{"x":1.25,"y":-3},"transform":[2,0,10,0,2,20],` +
+		`"precise":{"x":3.141592653589793,"y":0.1},"matrix":[1,0.5,0,0,1,0]}`
+	if string(data) != want {
+		t.Errorf("json.Marshal() = %s; want %s", data, want)
+	}
+	var out scene
+	if err := json.Unmarshal(data, &out); err != nil || out != in {
+		t.Errorf("json.Unmarshal() = %+v, %v; want %+v", out, err, in)
+	}
+
+	// Objects with capitalized keys and the text form are accepted as well.
+	if err := json.Unmarshal([]byte(`{"origin":{"X":5,"Y":6},"transform":"[[1 0 7] [0 1 8]]"}`), &out); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if out.Origin != (Point{X: 5, Y: 6}) || out.Transform != NewAffine2D(1, 0, 7, 0, 1, 8) {
+		t.Errorf("json.Unmarshal() = %+v", out)
+	}
+	if err := json.Unmarshal([]byte(`{"transform":[1,2,3]}`), &out); err == nil {
+		t.Errorf("json.Unmarshal() of a short array succeeded")
+	}
+}
+
+// TestText_FlagValue tests that the types work as command-line flags through flag.TextVar.
+func TestText_FlagValue(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	var p Point
+	var a Affine2D
+	fs.TextVar(&p, "origin", Point{X: 1, Y: 2}, "origin")
+	fs.TextVar(&a, "transform", Affine2D{}, "transform")
+	if p != (Point{X: 1, Y: 2}) {
+		t.Errorf("default origin = %v; want (1, 2)", p)
+	}
+	if err := fs.Parse([]string{"-origin", "(3, -4.5)", "-transform", "[[2 0 1] [0 2 1]]"}); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if p != (Point{X: 3, Y: -4.5}) || a != NewAffine2D(2, 0, 1, 0, 2, 1) {
+		t.Errorf("flags = %v, %v", p, a)
+	}
+	if got := fs.Lookup("origin").Value.String(); got != "(3, -4.5)" {
+		t.Errorf("flag String() = %q; want %q", got, "(3, -4.5)")
+	}
+	if err := fs.Parse([]string{"-origin", "3,4"}); err == nil {
+		t.Errorf("Parse() of a malformed point succeeded")
+	}
+}
@@ -0,0 +1,170 @@
+package tochka
+
+import (
+	"errors"
+	"math"
+)
+
+var (
+	// ErrLengthMismatch is returned when the source and destination point sets differ in length.
+	ErrLengthMismatch = errors.New("point sets differ in length")
+	// ErrTooFewPoints is returned when there are not enough correspondences to determine a transformation.
+	ErrTooFewPoints = errors.New("too few points")
+	// ErrDegeneratePoints is returned when the source points do not determine a unique transformation,
+	// for example because they are all collinear or coincident.
+	ErrDegeneratePoints = errors.New("degenerate point configuration")
+)
+
+// Residuals holds statistics of the distances between the transformed source points
+// and the destination points of a fit.
+type Residuals struct {
+	// RMS is the root mean square distance.
+	RMS float32
+	// Mean is the mean distance.
+	Mean float32
+	// Max is the largest distance.
+	Max float32
+}
+
+// FitAffine returns the affine transformation that maps src onto dst with the least sum of
+// squared distances, together with the residual statistics of the fit.
+// At least three non-collinear correspondences are required.
+func FitAffine(src, dst []Point) (Affine2D, Residuals, error) {
+	m, err := fitAffine(src, dst)
+	if err != nil {
+		return Affine2D{}, Residuals{}, err
+	}
+	return m.To32(), residuals(m, src, dst), nil
+}
+
+// FitSimilarity returns the similarity transformation (rotation, uniform scale and translation)
+// that maps src onto dst with the least sum of squared distances, together with the residual
+// statistics of the fit. It uses the closed form of the Umeyama method, which never introduces
+// a reflection. At least two distinct correspondences are required.
+func FitSimilarity(src, dst []Point) (Affine2D, Residuals, error) {
+	m, err := fitProcrustes(src, dst, true)
+	if err != nil {
+		return Affine2D{}, Residuals{}, err
+	}
+	return m.To32(), residuals(m, src, dst), nil
+}
+
+// FitRigid returns the rigid transformation (rotation and translation) that maps src onto dst
+// with the least sum of squared distances, together with the residual statistics of the fit.
+// At least two distinct correspondences are required.
+func FitRigid(src, dst []Point) (Affine2D, Residuals, error) {
+	m, err := fitProcrustes(src, dst, false)
+	if err != nil {
+		return Affine2D{}, Residuals{}, err
+	}
+	return m.To32(), residuals(m, src, dst), nil
+}
+
+// fitAffine computes the least squares affine transformation in double precision.
+func fitAffine(src, dst []Point) (Affine2D64, error) {
+	if len(src) != len(dst) {
+		return Affine2D64{}, ErrLengthMismatch
+	}
+	if len(src) < 3 {
+		return Affine2D64{}, ErrTooFewPoints
+	}
+	ms, md := centroid64(src), centroid64(dst)
+
+	// Accumulate the covariance of the source points and the cross-covariance
+	// of the destination and source points.
+	var sxx, sxy, syy, uxx, uxy, uyx, uyy float64
+	for k := range src {
+		s := src[k].To64().Sub(ms)
+		d := dst[k].To64().Sub(md)
+		sxx += s.X * s.X
+		sxy += s.X * s.Y
+		syy += s.Y * s.Y
+		uxx += d.X * s.X
+		uxy += d.X * s.Y
+		uyx += d.Y * s.X
+		uyy += d.Y * s.Y
+	}
+	det := sxx*syy - sxy*sxy
+	if det <= 1e-12*(sxx+syy)*(sxx+syy) {
+		return Affine2D64{}, ErrDegeneratePoints
+	}
+	// Multiply the cross-covariance by the inverse of the covariance.
+	sx := (uxx*syy - uxy*sxy) / det
+	hx := (uxy*sxx - uxx*sxy) / det
+	hy := (uyx*syy - uyy*sxy) / det
+	sy := (uyy*sxx - uyx*sxy) / det
+	return NewAffine2D64(
+		sx, hx, md.X-sx*ms.X-hx*ms.Y,
+		hy, sy, md.Y-hy*ms.X-sy*ms.Y,
+	), nil
+}
+
+// fitProcrustes computes the least squares rigid or, if scale is set, similarity transformation
+// in double precision.
+func fitProcrustes(src, dst []Point, scale bool) (Affine2D64, error) {
+	if len(src) != len(dst) {
+		return Affine2D64{}, ErrLengthMismatch
+	}
+	if len(src) < 2 {
+		return Affine2D64{}, ErrTooFewPoints
+	}
+	ms, md := centroid64(src), centroid64(dst)
+
+	var dot, cross, norm float64
+	for k := range src {
+		s := src[k].To64().Sub(ms)
+		d := dst[k].To64().Sub(md)
+		dot += s.Dot(d)
+		cross += s.Cross(d)
+		norm += s.Dot(s)
+	}
+	if norm == 0 {
+		return Affine2D64{}, ErrDegeneratePoints
+	}
+	// In two dimensions the optimal rotation has a closed form,
+	// the optimal scale is the ratio of the correlation to the source variance.
+	r := math.Hypot(dot, cross)
+	cos, sin := 1.0, 0.0
+	if r != 0 {
+		cos, sin = dot/r, cross/r
+	}
+	c := 1.0
+	if scale {
+		c = r / norm
+	}
+	sx, hx := c*cos, -c*sin
+	hy, sy := c*sin, c*cos
+	return NewAffine2D64(
+		sx, hx, md.X-sx*ms.X-hx*ms.Y,
+		hy, sy, md.Y-hy*ms.X-sy*ms.Y,
+	), nil
+}
+
+// centroid64 returns the mean of the points in double precision.
+func centroid64(points []Point) Point64 {
+	var c Point64
+	for _, p := range points {
+		c = c.Add(p.To64())
+	}
+	return c.Mul(1 / float64(len(points)))
+}
+
+// residuals computes the residual statistics of the transformation m mapping src onto dst.
+func residuals(m Affine2D64, src, dst []Point) Residuals {
+	if len(src) == 0 {
+		return Residuals{}
+	}
+	var sum, sumSq, maxDist float64
+	for k := range src {
+		dist := m.Transform(src[k].To64()).Distance(dst[k].To64())
+		sum += dist
+		sumSq += dist * dist
+		maxDist = math.Max(maxDist, dist)
+	}
+	n := float64(len(src))
+	return Residuals{
+		RMS:  float32(math.Sqrt(sumSq / n)),
+		Mean: float32(sum / n),
+		Max:  float32(maxDist),
+	}
+}
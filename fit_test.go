@@ -0,0 +1,112 @@
+package tochka
+
+import (
+	"errors"
+	"testing"
+)
+
+// fitSource is a small, asymmetric set of source points shared by the fitting tests.
+var fitSource = []Point{
+	{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 5}, {X: 0, Y: 7}, {X: 3, Y: 2}, {X: -4, Y: 6},
+}
+
+// TestFitAffine tests that an exact affine mapping is recovered.
+func TestFitAffine(t *testing.T) {
+	want := NewAffine2D(1.5, 0.3, 10, -0.2, 0.8, -5)
+	dst := transformAll(want, fitSource)
+	got, res, err := FitAffine(fitSource, dst)
+	if err != nil {
+		t.Fatalf("FitAffine() returned an error: %v", err)
+	}
+	if !affineAlmostEqual(got, want, 1e-4) {
+		t.Errorf("FitAffine() = %v; want %v", got, want)
+	}
+	if res.Max > 1e-4 {
+		t.Errorf("FitAffine() residuals = %+v; want zero", res)
+	}
+}
+
+// TestFitAffine_Noise tests the residual statistics of an inexact fit.
+func TestFitAffine_Noise(t *testing.T) {
+	src := []Point{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}}
+	dst := []Point{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 2}}
+	_, res, err := FitAffine(src, dst)
+	if err != nil {
+		t.Fatalf("FitAffine() returned an error: %v", err)
+	}
+	// The single outlying coordinate is spread evenly over the four points.
+	if !almostEqual(res.RMS, 0.25, 1e-5) || !almostEqual(res.Mean, 0.25, 1e-5) || !almostEqual(res.Max, 0.25, 1e-5) {
+		t.Errorf("FitAffine() residuals = %+v; want 0.25 each", res)
+	}
+}
+
+// TestFitSimilarity tests that rotation, uniform scale and translation are recovered.
+func TestFitSimilarity(t *testing.T) {
+	want := Affine2D{}.Scale(Point{}, Point{X: 2.5, Y: 2.5}).Rotate(Point{}, 0.7).Offset(Point{X: -3, Y: 8})
+	dst := transformAll(want, fitSource)
+	got, res, err := FitSimilarity(fitSource, dst)
+	if err != nil {
+		t.Fatalf("FitSimilarity() returned an error: %v", err)
+	}
+	if !affineAlmostEqual(got, want, 1e-4) || res.Max > 1e-4 {
+		t.Errorf("FitSimilarity() = %v, %+v; want %v", got, res, want)
+	}
+}
+
+// TestFitRigid tests that the scale of the destination does not leak into a rigid fit.
+func TestFitRigid(t *testing.T) {
+	rigid := Affine2D{}.Rotate(Point{}, -1.2).Offset(Point{X: 4, Y: 1})
+	got, res, err := FitRigid(fitSource, transformAll(rigid, fitSource))
+	if err != nil {
+		t.Fatalf("FitRigid() returned an error: %v", err)
+	}
+	if !affineAlmostEqual(got, rigid, 1e-4) || res.Max > 1e-4 {
+		t.Errorf("FitRigid() = %v, %+v; want %v", got, res, rigid)
+	}
+
+	scaled := rigid.Scale(Point{}, Point{X: 2, Y: 2})
+	got, res, err = FitRigid(fitSource, transformAll(scaled, fitSource))
+	if err != nil {
+		t.Fatalf("FitRigid() returned an error: %v", err)
+	}
+	if det := got.Determinant(); !almostEqual(det, 1, 1e-5) {
+		t.Errorf("FitRigid() determinant = %v; want 1", det)
+	}
+	if res.RMS == 0 {
+		t.Errorf("FitRigid() residuals = %+v; want non-zero for a scaled target", res)
+	}
+}
+
+// TestFit_Errors tests the validation of the inputs.
+func TestFit_Errors(t *testing.T) {
+	collinear := []Point{{X: 0, Y: 0}, {X: 1, Y: 1}, {X: 2, Y: 2}, {X: 3, Y: 3}}
+	same := []Point{{X: 1, Y: 1}, {X: 1, Y: 1}}
+	tests := []struct {
+		name string
+		fit  func(src, dst []Point) (Affine2D, Residuals, error)
+		src  []Point
+		dst  []Point
+		want error
+	}{
+		{"affine mismatch", FitAffine, fitSource, fitSource[1:], ErrLengthMismatch},
+		{"affine too few", FitAffine, fitSource[:2], fitSource[:2], ErrTooFewPoints},
+		{"affine collinear", FitAffine, collinear, collinear, ErrDegeneratePoints},
+		{"similarity too few", FitSimilarity, fitSource[:1], fitSource[:1], ErrTooFewPoints},
+		{"similarity coincident", FitSimilarity, same, same, ErrDegeneratePoints},
+		{"rigid mismatch", FitRigid, same, fitSource, ErrLengthMismatch},
+	}
+	for _, tt := range tests {
+		if _, _, err := tt.fit(tt.src, tt.dst); !errors.Is(err, tt.want) {
+			t.Errorf("%s: error = %v; want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+// transformAll applies the transformation to every point.
+func transformAll(a Affine2D, points []Point) []Point {
+	out := make([]Point, len(points))
+	for k, p := range points {
+		out[k] = a.Transform(p)
+	}
+	return out
+}
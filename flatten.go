@@ -0,0 +1,123 @@
+package tochka
+
+import "math"
+
+// DefaultFlatness is the tolerance used by the Flatten methods when a non-positive tolerance
+// is specified. It is a quarter of a unit, which is visually exact when units are pixels.
+const DefaultFlatness = 0.25
+
+// maxFlattenDepth limits the recursive subdivision of Bézier curves, and thus the number
+// of segments emitted for a single curve to 1 << maxFlattenDepth.
+const maxFlattenDepth = 16
+
+// Flatten approximates the curve by a polyline whose distance from the curve does not
+// exceed tolerance. The curve is subdivided adaptively until its control points lie within
+// tolerance of the chord, so flat portions produce few segments. The returned points start
+// at P0 and end at P2.
+func (q QuadBezier) Flatten(tolerance float32) []Point {
+	return q.flattenTo([]Point{q.P0}, flatness(tolerance))
+}
+
+// Flatten approximates the curve by a polyline whose distance from the curve does not
+// exceed tolerance. The curve is subdivided adaptively until its control points lie within
+// tolerance of the chord, so flat portions produce few segments. The returned points start
+// at P0 and end at P3.
+func (c CubicBezier) Flatten(tolerance float32) []Point {
+	return c.flattenTo([]Point{c.P0}, flatness(tolerance))
+}
+
+// Flatten approximates the arc by a polyline whose distance from the arc does not exceed
+// tolerance. The arc is divided into equal parametric steps, sized so that the deviation
+// is bounded on the most curved part of the ellipse. The returned points start at the
+// beginning of the arc and end at its end.
+func (a Arc) Flatten(tolerance float32) []Point {
+	return a.flattenTo([]Point{a.At(0)}, flatness(tolerance))
+}
+
+// Flatten approximates the boundary of the ellipse by a ring whose distance from the
+// boundary does not exceed tolerance.
+func (e Ellipse) Flatten(tolerance float32) Ring {
+	points := NewArc(e, 0, 2*math.Pi).Flatten(tolerance)
+	return Ring(points[:len(points)-1])
+}
+
+// flattenTo appends the flattened curve to dst, omitting the starting point.
+func (q QuadBezier) flattenTo(dst []Point, tolerance float64) []Point {
+	return flattenBezier(dst, [4]Point64{q.P0.To64(), q.P1.To64(), q.P2.To64()}, 3, tolerance, 0)
+}
+
+// flattenTo appends the flattened curve to dst, omitting the starting point.
+func (c CubicBezier) flattenTo(dst []Point, tolerance float64) []Point {
+	ctrl := [4]Point64{c.P0.To64(), c.P1.To64(), c.P2.To64(), c.P3.To64()}
+	return flattenBezier(dst, ctrl, 4, tolerance, 0)
+}
+
+// flattenTo appends the flattened arc to dst, omitting the starting point.
+func (a Arc) flattenTo(dst []Point, tolerance float64) []Point {
+	// In the unit frame a chord spanning the angle Δ deviates from the circle by 1 - cos(Δ/2),
+	// and the map onto the ellipse stretches that deviation by at most the major radius.
+	r := math.Max(math.Abs(float64(a.Ellipse.Radii.X)), math.Abs(float64(a.Ellipse.Radii.Y)))
+	sweep := float64(a.Sweep)
+	n := 1
+	if r > 0 && sweep != 0 {
+		step := 2 * math.Acos(math.Max(-1, 1-tolerance/r))
+		n = int(min(math.Ceil(math.Abs(sweep)/step), 1<<maxFlattenDepth))
+	}
+	start := float64(a.Start)
+	for k := 1; k < n; k++ {
+		dst = append(dst, a.at64(start+sweep*float64(k)/float64(n)).To32())
+	}
+	return append(dst, a.At(1))
+}
+
+// flattenBezier appends the flattened Bézier curve with the first n control points of ctrl
+// to dst, omitting the starting point. The curve lies within the convex hull of its control
+// points, so it is flat enough once every control point lies within tolerance of the chord.
+func flattenBezier(dst []Point, ctrl [4]Point64, n int, tolerance float64, depth int) []Point {
+	a, b := ctrl[0], ctrl[n-1]
+	flat := true
+	for _, p := range ctrl[1 : n-1] {
+		if segmentDistance64(p, a, b) > tolerance {
+			flat = false
+			break
+		}
+	}
+	if flat || depth == maxFlattenDepth {
+		return append(dst, b.To32())
+	}
+	left, right := splitBezier(ctrl, n)
+	dst = flattenBezier(dst, left, n, tolerance, depth+1)
+	return flattenBezier(dst, right, n, tolerance, depth+1)
+}
+
+// splitBezier divides the Bézier curve with the first n control points of ctrl
+// at its midpoint using de Casteljau's algorithm.
+func splitBezier(ctrl [4]Point64, n int) (left, right [4]Point64) {
+	for k := 0; k < n; k++ {
+		left[k] = ctrl[0]
+		right[n-1-k] = ctrl[n-1-k]
+		for i := 0; i < n-1-k; i++ {
+			ctrl[i] = lerp64(ctrl[i], ctrl[i+1], 0.5)
+		}
+	}
+	return left, right
+}
+
+// segmentDistance64 returns the distance between p and the segment from a to b in double precision.
+func segmentDistance64(p, a, b Point64) float64 {
+	d := b.Sub(a)
+	t := 0.0
+	if dd := d.Dot(d); dd > 0 {
+		t = math.Min(1, math.Max(0, p.Sub(a).Dot(d)/dd))
+	}
+	return p.Distance(a.Add(d.Mul(t)))
+}
+
+// flatness converts a tolerance to double precision, substituting DefaultFlatness
+// for a non-positive or NaN tolerance.
+func flatness(tolerance float32) float64 {
+	if !(tolerance > 0) {
+		return DefaultFlatness
+	}
+	return float64(tolerance)
+}
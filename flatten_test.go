@@ -0,0 +1,101 @@
+package tochka
+
+import (
+	"math"
+	"testing"
+)
+
+// polylineDeviation returns the largest distance between the curve sampled by at
+// and the polyline, in both directions.
+func polylineDeviation(at func(float32) Point, poly []Point) float64 {
+	const samples = 2000
+	curve := make([]Point64, samples+1)
+	for k := range curve {
+		curve[k] = at(float32(k) / samples).To64()
+	}
+	var worst float64
+	// Every point of the curve must be close to the polyline.
+	for _, p := range curve {
+		best := math.Inf(1)
+		for k := 1; k < len(poly); k++ {
+			best = math.Min(best, segmentDistance64(p, poly[k-1].To64(), poly[k].To64()))
+		}
+		worst = math.Max(worst, best)
+	}
+	// Every point of the polyline must be close to the curve, which the dense
+	// samples approximate far more closely than any tolerance tested.
+	for k := 1; k < len(poly); k++ {
+		for i := 0; i <= 10; i++ {
+			p := lerp64(poly[k-1].To64(), poly[k].To64(), float64(i)/10)
+			best := math.Inf(1)
+			for j := 1; j < len(curve); j++ {
+				best = math.Min(best, segmentDistance64(p, curve[j-1], curve[j]))
+			}
+			worst = math.Max(worst, best)
+		}
+	}
+	return worst
+}
+
+// TestFlatten_Tolerance tests that flattened curves stay within the requested tolerance.
+func TestFlatten_Tolerance(t *testing.T) {
+	curves := []struct {
+		name    string
+		at      func(float32) Point
+		flatten func(float32) []Point
+	}{
+		{"quad", NewQuadBezier(Point{X: 0, Y: 0}, Point{X: 50, Y: 100}, Point{X: 100, Y: 0}).At,
+			NewQuadBezier(Point{X: 0, Y: 0}, Point{X: 50, Y: 100}, Point{X: 100, Y: 0}).Flatten},
+		{"cubic", arch.Transform(NewAffine2D(25, 0, 0, 0, 25, 0)).At,
+			arch.Transform(NewAffine2D(25, 0, 0, 0, 25, 0)).Flatten},
+		{"cusp", NewCubicBezier(Point{X: 0, Y: 0}, Point{X: 100, Y: 100}, Point{X: 0, Y: 100}, Point{X: 100, Y: 0}).At,
+			NewCubicBezier(Point{X: 0, Y: 0}, Point{X: 100, Y: 100}, Point{X: 0, Y: 100}, Point{X: 100, Y: 0}).Flatten},
+		{"arc", NewArc(NewEllipse(Point{X: 10, Y: 10}, 80, 20, 0.3), 0.5, -4).At,
+			NewArc(NewEllipse(Point{X: 10, Y: 10}, 80, 20, 0.3), 0.5, -4).Flatten},
+	}
+	for _, c := range curves {
+		for _, tol := range []float32{2, 0.25, 0.01} {
+			poly := c.flatten(tol)
+			if poly[0] != c.at(0) || poly[len(poly)-1] != c.at(1) {
+				t.Errorf("%s: Flatten(%v) = %v ... %v; want the endpoints of the curve", c.name, tol, poly[0], poly[len(poly)-1])
+			}
+			// The polyline itself is stored in float32, which adds a rounding error.
+			if got := polylineDeviation(c.at, poly); got > float64(tol)+1e-4 {
+				t.Errorf("%s: Flatten(%v) deviates by %v with %d segments", c.name, tol, got, len(poly)-1)
+			}
+		}
+	}
+}
+
+// TestFlatten_SegmentCount tests that flat curves produce few segments and that
+// the segment count grows as the tolerance shrinks.
+func TestFlatten_SegmentCount(t *testing.T) {
+	line := NewCubicBezier(Point{X: 0, Y: 0}, Point{X: 1, Y: 1}, Point{X: 2, Y: 2}, Point{X: 3, Y: 3})
+	if got := line.Flatten(0.01); len(got) != 2 {
+		t.Errorf("Flatten() of a straight curve = %v; want a single segment", got)
+	}
+	c := arch.Transform(NewAffine2D(25, 0, 0, 0, 25, 0))
+	coarse, fine := len(c.Flatten(1)), len(c.Flatten(0.01))
+	if coarse >= fine || fine > 256 {
+		t.Errorf("Flatten() produced %d and %d points for tolerances 1 and 0.01", coarse, fine)
+	}
+	if got, want := len(c.Flatten(0)), len(c.Flatten(DefaultFlatness)); got != want {
+		t.Errorf("Flatten(0) produced %d points; want %d as with DefaultFlatness", got, want)
+	}
+}
+
+// TestEllipse_Flatten tests that a flattened ellipse is a ring approximating its area.
+func TestEllipse_Flatten(t *testing.T) {
+	e := NewEllipse(Point{X: 5, Y: 5}, 40, 10, 0.2)
+	r := e.Flatten(0.05)
+	if r[0] == r[len(r)-1] {
+		t.Errorf("Flatten() repeats the first point at the end")
+	}
+	if r.Orientation() != CounterClockwise {
+		t.Errorf("Flatten() orientation = %v; want counterclockwise", r.Orientation())
+	}
+	// The inscribed polygon loses at most the perimeter times the tolerance in area.
+	if got, want := r.Area(), e.Area(); got > want || want-got > e.Perimeter()*0.05 {
+		t.Errorf("Flatten().Area() = %v; want close to %v", got, want)
+	}
+}
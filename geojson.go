@@ -0,0 +1,394 @@
+package tochka
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrInvalidGeoJSON is returned, wrapped with a description, when a GeoJSON document
+// is valid JSON but not valid GeoJSON.
+var ErrInvalidGeoJSON = errors.New("invalid GeoJSON")
+
+// Feature is a GeoJSON Feature: a geometry with arbitrary properties.
+type Feature struct {
+	// ID is the optional identifier, a string or a float64, or nil if absent.
+	ID any
+	// Geometry is nil for a feature without a location.
+	Geometry Geometry
+	// Properties holds the decoded JSON properties, or nil for null.
+	Properties map[string]any
+	// BBox is the optional bounding box of the feature.
+	BBox *Rect
+}
+
+// FeatureCollection is a GeoJSON FeatureCollection.
+type FeatureCollection struct {
+	Features []Feature
+	// BBox is the optional bounding box of all features.
+	BBox *Rect
+}
+
+// geoJSONObject holds the members of any GeoJSON object while decoding.
+type geoJSONObject struct {
+	Type        string            `json:"type"`
+	Coordinates json.RawMessage   `json:"coordinates"`
+	Geometries  []json.RawMessage `json:"geometries"`
+}
+
+// geoJSONGeometry is the encoded form of a geometry. The members are interfaces so that
+// empty arrays are written while absent members are omitted.
+type geoJSONGeometry struct {
+	Type        string `json:"type"`
+	Coordinates any    `json:"coordinates,omitempty"`
+	Geometries  any    `json:"geometries,omitempty"`
+}
+
+// geoJSONFeature is the encoded and decoded form of a feature.
+type geoJSONFeature struct {
+	Type       string          `json:"type"`
+	ID         any             `json:"id,omitempty"`
+	BBox       []float32       `json:"bbox,omitempty"`
+	Geometry   json.RawMessage `json:"geometry"`
+	Properties map[string]any  `json:"properties"`
+}
+
+// geoJSONFeatureCollection is the encoded and decoded form of a feature collection.
+type geoJSONFeatureCollection struct {
+	Type     string    `json:"type"`
+	BBox     []float32 `json:"bbox,omitempty"`
+	Features []Feature `json:"features"`
+}
+
+// MarshalGeoJSON encodes a geometry as a GeoJSON geometry object. Following the
+// right-hand rule of RFC 7946, outer polygon rings are written counterclockwise and
+// holes clockwise, reversing rings as needed, and every ring is closed by repeating
+// its first vertex. EmptyPoint is written as an empty position, also inside other
+// geometries, and a nil Geometry as JSON null.
+func MarshalGeoJSON(g Geometry) ([]byte, error) {
+	if g == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(encodeGeoJSON(g))
+}
+
+// UnmarshalGeoJSON decodes a GeoJSON geometry object. Positions may carry an altitude
+// or other extra values, which are discarded, and bbox and foreign members are ignored.
+// Polygon rings must be closed and have at least four positions; the closing position is
+// removed, as in Ring. Ring winding is kept as written, as RFC 7946 asks of parsers.
+// An empty position yields EmptyPoint and JSON null a nil Geometry.
+func UnmarshalGeoJSON(data []byte) (Geometry, error) {
+	var obj *geoJSONObject
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+	if obj == nil {
+		return nil, nil
+	}
+	return obj.geometry()
+}
+
+// MarshalJSON implements json.Marshaler, encoding the feature as a GeoJSON Feature
+// with its geometry encoded by MarshalGeoJSON.
+func (f Feature) MarshalJSON() ([]byte, error) {
+	geom, err := MarshalGeoJSON(f.Geometry)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(geoJSONFeature{
+		Type:       "Feature",
+		ID:         f.ID,
+		BBox:       encodeBBox(f.BBox),
+		Geometry:   geom,
+		Properties: f.Properties,
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler, decoding a GeoJSON Feature with its
+// geometry decoded by UnmarshalGeoJSON.
+func (f *Feature) UnmarshalJSON(data []byte) error {
+	var v geoJSONFeature
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if v.Type != "Feature" {
+		return fmt.Errorf("%w: type %q is not Feature", ErrInvalidGeoJSON, v.Type)
+	}
+	bbox, err := decodeBBox(v.BBox)
+	if err != nil {
+		return err
+	}
+	var g Geometry
+	if len(v.Geometry) > 0 {
+		if g, err = UnmarshalGeoJSON(v.Geometry); err != nil {
+			return err
+		}
+	}
+	*f = Feature{ID: v.ID, Geometry: g, Properties: v.Properties, BBox: bbox}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, encoding the collection as a GeoJSON FeatureCollection.
+func (fc FeatureCollection) MarshalJSON() ([]byte, error) {
+	features := fc.Features
+	if features == nil {
+		features = []Feature{}
+	}
+	return json.Marshal(geoJSONFeatureCollection{
+		Type:     "FeatureCollection",
+		BBox:     encodeBBox(fc.BBox),
+		Features: features,
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler, decoding a GeoJSON FeatureCollection.
+func (fc *FeatureCollection) UnmarshalJSON(data []byte) error {
+	var v geoJSONFeatureCollection
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if v.Type != "FeatureCollection" {
+		return fmt.Errorf("%w: type %q is not FeatureCollection", ErrInvalidGeoJSON, v.Type)
+	}
+	bbox, err := decodeBBox(v.BBox)
+	if err != nil {
+		return err
+	}
+	*fc = FeatureCollection{Features: v.Features, BBox: bbox}
+	return nil
+}
+
+// encodeGeoJSON returns the encoded form of a geometry.
+func encodeGeoJSON(g Geometry) geoJSONGeometry {
+	switch g := g.(type) {
+	case Point:
+		return geoJSONGeometry{Type: "Point", Coordinates: encodePosition(g)}
+	case LineString:
+		return geoJSONGeometry{Type: "LineString", Coordinates: encodePositions(g)}
+	case Polygon:
+		return geoJSONGeometry{Type: "Polygon", Coordinates: encodePolygon(g)}
+	case MultiPoint:
+		return geoJSONGeometry{Type: "MultiPoint", Coordinates: encodePositions(g)}
+	case MultiLineString:
+		lines := make([][][]float32, len(g))
+		for k, l := range g {
+			lines[k] = encodePositions(l)
+		}
+		return geoJSONGeometry{Type: "MultiLineString", Coordinates: lines}
+	case MultiPolygon:
+		polys := make([][][][]float32, len(g))
+		for k, p := range g {
+			polys[k] = encodePolygon(p)
+		}
+		return geoJSONGeometry{Type: "MultiPolygon", Coordinates: polys}
+	case GeometryCollection:
+		return geoJSONGeometry{Type: "GeometryCollection", Geometries: encodeGeoJSONGeometries(g)}
+	}
+	// A nil member of a collection is written as an empty collection.
+	return geoJSONGeometry{Type: "GeometryCollection", Geometries: []geoJSONGeometry{}}
+}
+
+// encodeGeoJSONGeometries returns the encoded forms of the members of a collection.
+func encodeGeoJSONGeometries(gc GeometryCollection) []geoJSONGeometry {
+	out := make([]geoJSONGeometry, len(gc))
+	for k, g := range gc {
+		out[k] = encodeGeoJSON(g)
+	}
+	return out
+}
+
+// encodePosition returns a position, which is empty for EmptyPoint.
+func encodePosition(p Point) []float32 {
+	if isEmptyPoint(p) {
+		return []float32{}
+	}
+	return []float32{p.X, p.Y}
+}
+
+// encodePositions returns an array of positions.
+func encodePositions(pts []Point) [][]float32 {
+	out := make([][]float32, len(pts))
+	for k, p := range pts {
+		out[k] = encodePosition(p)
+	}
+	return out
+}
+
+// encodePolygon returns the closed rings of a polygon wound by the right-hand rule.
+func encodePolygon(poly Polygon) [][][]float32 {
+	if len(poly.Outer) == 0 && len(poly.Holes) == 0 {
+		return [][][]float32{}
+	}
+	poly = poly.Orient(CounterClockwise)
+	rings := [][][]float32{encodePositions(closeRing(poly.Outer))}
+	for _, h := range poly.Holes {
+		rings = append(rings, encodePositions(closeRing(h)))
+	}
+	return rings
+}
+
+// encodeBBox returns the bbox member for a rectangle, or nil if there is none.
+func encodeBBox(r *Rect) []float32 {
+	if r == nil {
+		return nil
+	}
+	return []float32{r.Min.X, r.Min.Y, r.Max.X, r.Max.Y}
+}
+
+// decodeBBox converts a bbox member, which holds the minimum values of every axis
+// followed by the maximum values, to a rectangle, or nil if there is none.
+func decodeBBox(v []float32) (*Rect, error) {
+	if v == nil {
+		return nil, nil
+	}
+	if len(v) < 4 || len(v)%2 != 0 {
+		return nil, fmt.Errorf("%w: bbox has %d values", ErrInvalidGeoJSON, len(v))
+	}
+	n := len(v) / 2
+	// The minimum longitude exceeds the maximum across the antimeridian, so the
+	// rectangle is kept as written rather than made canonical.
+	return &Rect{Min: Point{X: v[0], Y: v[1]}, Max: Point{X: v[n], Y: v[n+1]}}, nil
+}
+
+// geometry decodes a geometry object.
+func (obj *geoJSONObject) geometry() (Geometry, error) {
+	if obj.Type == "GeometryCollection" {
+		if obj.Geometries == nil {
+			return nil, fmt.Errorf("%w: GeometryCollection without geometries", ErrInvalidGeoJSON)
+		}
+		gc := make(GeometryCollection, len(obj.Geometries))
+		for k, raw := range obj.Geometries {
+			var member *geoJSONObject
+			if err := json.Unmarshal(raw, &member); err != nil {
+				return nil, err
+			}
+			if member == nil {
+				return nil, fmt.Errorf("%w: null in GeometryCollection", ErrInvalidGeoJSON)
+			}
+			g, err := member.geometry()
+			if err != nil {
+				return nil, err
+			}
+			gc[k] = g
+		}
+		return gc, nil
+	}
+	if obj.Coordinates == nil {
+		if obj.Type == "" {
+			return nil, fmt.Errorf("%w: missing type", ErrInvalidGeoJSON)
+		}
+		return nil, fmt.Errorf("%w: %s without coordinates", ErrInvalidGeoJSON, obj.Type)
+	}
+	switch obj.Type {
+	case "Point":
+		var pos []float64
+		if err := json.Unmarshal(obj.Coordinates, &pos); err != nil {
+			return nil, err
+		}
+		return decodePosition(pos)
+	case "LineString", "MultiPoint":
+		var v [][]float64
+		if err := json.Unmarshal(obj.Coordinates, &v); err != nil {
+			return nil, err
+		}
+		pts, err := decodePositions(v)
+		if obj.Type == "MultiPoint" {
+			return MultiPoint(pts), err
+		}
+		if len(pts) == 1 {
+			return nil, fmt.Errorf("%w: LineString with a single position", ErrInvalidGeoJSON)
+		}
+		return LineString(pts), err
+	case "Polygon":
+		var v [][][]float64
+		if err := json.Unmarshal(obj.Coordinates, &v); err != nil {
+			return nil, err
+		}
+		return decodePolygon(v)
+	case "MultiLineString":
+		var v [][][]float64
+		if err := json.Unmarshal(obj.Coordinates, &v); err != nil {
+			return nil, err
+		}
+		ml := make(MultiLineString, len(v))
+		for k, l := range v {
+			pts, err := decodePositions(l)
+			if err != nil {
+				return nil, err
+			}
+			ml[k] = pts
+		}
+		return ml, nil
+	case "MultiPolygon":
+		var v [][][][]float64
+		if err := json.Unmarshal(obj.Coordinates, &v); err != nil {
+			return nil, err
+		}
+		mp := make(MultiPolygon, len(v))
+		for k, rings := range v {
+			poly, err := decodePolygon(rings)
+			if err != nil {
+				return nil, err
+			}
+			mp[k] = poly
+		}
+		return mp, nil
+	}
+	return nil, fmt.Errorf("%w: unknown geometry type %q", ErrInvalidGeoJSON, obj.Type)
+}
+
+// decodePosition converts a position of at least two values to a point, and an empty
+// position, as encodePosition writes EmptyPoint, back to EmptyPoint.
+func decodePosition(pos []float64) (Point, error) {
+	if len(pos) == 0 {
+		return EmptyPoint(), nil
+	}
+	if len(pos) < 2 {
+		return Point{}, fmt.Errorf("%w: position has %d values", ErrInvalidGeoJSON, len(pos))
+	}
+	if math.Abs(pos[0]) > math.MaxFloat32 || math.Abs(pos[1]) > math.MaxFloat32 {
+		return Point{}, fmt.Errorf("%w: coordinate out of range", ErrInvalidGeoJSON)
+	}
+	return Point{X: float32(pos[0]), Y: float32(pos[1])}, nil
+}
+
+// decodePositions converts an array of positions to points.
+func decodePositions(v [][]float64) ([]Point, error) {
+	if v == nil {
+		return nil, nil
+	}
+	pts := make([]Point, len(v))
+	for k, pos := range v {
+		p, err := decodePosition(pos)
+		if err != nil {
+			return nil, err
+		}
+		pts[k] = p
+	}
+	return pts, nil
+}
+
+// decodePolygon converts an array of closed rings to a polygon.
+func decodePolygon(v [][][]float64) (Polygon, error) {
+	var poly Polygon
+	for k, ring := range v {
+		pts, err := decodePositions(ring)
+		if err != nil {
+			return Polygon{}, err
+		}
+		r, msg := openRing(pts)
+		if msg != "" || len(pts) == 0 {
+			if msg == "" {
+				msg = "ring is empty"
+			}
+			return Polygon{}, fmt.Errorf("%w: %s", ErrInvalidGeoJSON, msg)
+		}
+		if k == 0 {
+			poly.Outer = r
+		} else {
+			poly.Holes = append(poly.Holes, r)
+		}
+	}
+	return poly, nil
+}
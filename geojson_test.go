@@ -0,0 +1,235 @@
+package tochka
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// TestUnmarshalGeoJSON tests every geometry type, extra position values and null.
+func TestUnmarshalGeoJSON(t *testing.T) {
+	tri := Ring{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 0, Y: 4}}
+	hole := Ring{{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 1, Y: 2}}
+	tests := []struct {
+		data string
+		want Geometry
+	}{
+		{`{"type":"Point","coordinates":[1.5,-2]}`, Point{X: 1.5, Y: -2}},
+		{`{"type":"Point","coordinates":[1,2,300],"bbox":[1,2,1,2],"extra":true}`, Point{X: 1, Y: 2}},
+		{`{"type":"LineString","coordinates":[[0,0],[1,1]]}`, LineString{{X: 0, Y: 0}, {X: 1, Y: 1}}},
+		{`{"type":"LineString","coordinates":[]}`, LineString{}},
+		{`{"type":"Polygon","coordinates":[[[0,0],[4,0],[0,4],[0,0]],[[1,1],[2,1],[1,2],[1,1]]]}`, NewPolygon(tri, hole)},
+		{`{"type":"MultiPoint","coordinates":[[1,2],[3,4]]}`, MultiPoint{{X: 1, Y: 2}, {X: 3, Y: 4}}},
+		{`{"type":"MultiLineString","coordinates":[[[0,0],[1,1]]]}`, MultiLineString{{{X: 0, Y: 0}, {X: 1, Y: 1}}}},
+		{`{"type":"MultiPolygon","coordinates":[[[[0,0],[4,0],[0,4],[0,0]]]]}`, MultiPolygon{NewPolygon(tri)}},
+		{
+			`{"type":"GeometryCollection","geometries":[{"type":"Point","coordinates":[1,2]},{"type":"GeometryCollection","geometries":[]}]}`,
+			GeometryCollection{Point{X: 1, Y: 2}, GeometryCollection{}},
+		},
+		{`null`, nil},
+	}
+	for _, tt := range tests {
+		got, err := UnmarshalGeoJSON([]byte(tt.data))
+		if err != nil {
+			t.Errorf("UnmarshalGeoJSON(%s) error = %v", tt.data, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("UnmarshalGeoJSON(%s) = %#v; want %#v", tt.data, got, tt.want)
+		}
+	}
+}
+
+// TestUnmarshalGeoJSON_Errors tests that invalid GeoJSON is rejected with ErrInvalidGeoJSON.
+func TestUnmarshalGeoJSON_Errors(t *testing.T) {
+	for _, data := range []string{
+		`{}`,
+		`{"type":"Circle","coordinates":[0,0]}`,
+		`{"type":"Point"}`,
+		`{"type":"Point","coordinates":[1]}`,
+		`{"type":"Point","coordinates":[1e39,0]}`,
+		`{"type":"LineString","coordinates":[[0,0]]}`,
+		`{"type":"Polygon","coordinates":[[[0,0],[1,0],[0,1],[1,1]]]}`,
+		`{"type":"Polygon","coordinates":[[[0,0],[1,0],[0,0]]]}`,
+		`{"type":"Polygon","coordinates":[[]]}`,
+		`{"type":"GeometryCollection"}`,
+		`{"type":"GeometryCollection","geometries":[null]}`,
+	} {
+		if _, err := UnmarshalGeoJSON([]byte(data)); !errors.Is(err, ErrInvalidGeoJSON) {
+			t.Errorf("UnmarshalGeoJSON(%s) error = %v; want ErrInvalidGeoJSON", data, err)
+		}
+	}
+	if _, err := UnmarshalGeoJSON([]byte(`{"type":"Point","coordinates":"1 2"}`)); err == nil {
+		t.Errorf("UnmarshalGeoJSON() of string coordinates succeeded")
+	}
+}
+
+// TestMarshalGeoJSON tests the encoding of every geometry type and the right-hand rule.
+func TestMarshalGeoJSON(t *testing.T) {
+	cw := square.Reverse()
+	ccwHole := Ring{{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 1, Y: 2}}
+	tests := []struct {
+		g    Geometry
+		want string
+	}{
+		{Point{X: 1.5, Y: -2}, `{"type":"Point","coordinates":[1.5,-2]}`},
+		{EmptyPoint(), `{"type":"Point","coordinates":[]}`},
+		{LineString{{X: 0, Y: 0}, {X: 0.1, Y: 1}}, `{"type":"LineString","coordinates":[[0,0],[0.1,1]]}`},
+		{LineString{}, `{"type":"LineString","coordinates":[]}`},
+		// The clockwise outer ring is reversed and the counterclockwise hole too.
+		{
+			NewPolygon(cw, ccwHole),
+			`{"type":"Polygon","coordinates":[[[0,0],[4,0],[4,4],[0,4],[0,0]],[[1,2],[2,1],[1,1],[1,2]]]}`,
+		},
+		{MultiPoint{{X: 1, Y: 2}}, `{"type":"MultiPoint","coordinates":[[1,2]]}`},
+		{MultiLineString{}, `{"type":"MultiLineString","coordinates":[]}`},
+		{MultiPolygon{{}}, `{"type":"MultiPolygon","coordinates":[[]]}`},
+		{
+			GeometryCollection{Point{X: 1, Y: 2}, GeometryCollection{}},
+			`{"type":"GeometryCollection","geometries":[{"type":"Point","coordinates":[1,2]},{"type":"GeometryCollection","geometries":[]}]}`,
+		},
+		{nil, `null`},
+	}
+	for _, tt := range tests {
+		data, err := MarshalGeoJSON(tt.g)
+		if err != nil {
+			t.Errorf("MarshalGeoJSON(%#v) error = %v", tt.g, err)
+			continue
+		}
+		if string(data) != tt.want {
+			t.Errorf("MarshalGeoJSON(%#v) = %s; want %s", tt.g, data, tt.want)
+		}
+	}
+}
+
+// TestFeatureCollection_JSON tests the round trip of features with properties, identifiers and bboxes.
+func TestFeatureCollection_JSON(t *testing.T) {
+	data := []byte(`{
+		"type": "FeatureCollection",
+		"bbox": [-10, -20, 0, 10, 20, 100],
+		"features": [
+			{
+				"type": "Feature",
+				"id": "a",
+				"geometry": {"type": "Point", "coordinates": [-10, -20, 0]},
+				"properties": {"name": "start", "tags": ["x", "y"], "rank": 1}
+			},
+			{
+				"type": "Feature",
+				"id": 7,
+				"bbox": [0, 0, 10, 20],
+				"geometry": {"type": "LineString", "coordinates": [[0, 0], [10, 20]]},
+				"properties": null
+			},
+			{"type": "Feature", "geometry": null, "properties": {}}
+		]
+	}`)
+	var fc FeatureCollection
+	if err := json.Unmarshal(data, &fc); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	want := FeatureCollection{
+		BBox: &Rect{Min: Point{X: -10, Y: -20}, Max: Point{X: 10, Y: 20}},
+		Features: []Feature{
+			{
+				ID:         "a",
+				Geometry:   Point{X: -10, Y: -20},
+				Properties: map[string]any{"name": "start", "tags": []any{"x", "y"}, "rank": 1.0},
+			},
+			{
+				ID:       7.0,
+				Geometry: LineString{{X: 0, Y: 0}, {X: 10, Y: 20}},
+				BBox:     &Rect{Max: Point{X: 10, Y: 20}},
+			},
+			{Properties: map[string]any{}},
+		},
+	}
+	if !reflect.DeepEqual(fc, want) {
+		t.Fatalf("json.Unmarshal() = %#v; want %#v", fc, want)
+	}
+
+	out, err := json.Marshal(fc)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	var again FeatureCollection
+	if err := json.Unmarshal(out, &again); err != nil || !reflect.DeepEqual(again, want) {
+		t.Errorf("round trip of %s = %#v, %v; want %#v", out, again, err, want)
+	}
+	if got, want := string(mustMarshal(t, FeatureCollection{})), `{"type":"FeatureCollection","features":[]}`; got != want {
+		t.Errorf("json.Marshal() of an empty collection = %s; want %s", got, want)
+	}
+	if got, want := string(mustMarshal(t, Feature{BBox: &Rect{Min: Point{X: 0.1, Y: 0.2}, Max: Point{X: 1.3, Y: 2.7}}})),
+		`{"type":"Feature","bbox":[0.1,0.2,1.3,2.7],"geometry":null,"properties":null}`; got != want {
+		t.Errorf("json.Marshal() of a feature with a bbox = %s; want %s", got, want)
+	}
+	if got, want := string(mustMarshal(t, Feature{})), `{"type":"Feature","geometry":null,"properties":null}`; got != want {
+		t.Errorf("json.Marshal() of an empty feature = %s; want %s", got, want)
+	}
+
+	for _, bad := range []string{
+		`{"type":"Feature","geometry":null,"properties":null}`,
+		`{"type":"FeatureCollection","features":[],"bbox":[1,2,3]}`,
+		`{"type":"FeatureCollection","features":[{"type":"Point","coordinates":[1,2]}]}`,
+		`{"type":"FeatureCollection","features":[{"type":"Feature","geometry":{"type":"Point"},"properties":null}]}`,
+	} {
+		if err := json.Unmarshal([]byte(bad), &fc); !errors.Is(err, ErrInvalidGeoJSON) {
+			t.Errorf("json.Unmarshal(%s) error = %v; want ErrInvalidGeoJSON", bad, err)
+		}
+	}
+}
+
+// TestGeometryBounds tests the bounds of nested geometries, points and empty geometries.
+func TestGeometryBounds(t *testing.T) {
+	g := GeometryCollection{
+		Point{X: -1, Y: 5},
+		MultiPolygon{NewPolygon(square)},
+		MultiPoint{EmptyPoint()},
+	}
+	if got, ok := GeometryBounds(g); !ok || got != (Rect{Min: Point{X: -1}, Max: Point{X: 4, Y: 5}}) {
+		t.Errorf("GeometryBounds() = %v, %v; want (-1, 0)-(4, 5)", got, ok)
+	}
+	if got, ok := GeometryBounds(Point{X: 2, Y: 3}); !ok || got.Min != got.Max {
+		t.Errorf("GeometryBounds() of a point = %v, %v; want a zero-size rectangle", got, ok)
+	}
+	if _, ok := GeometryBounds(GeometryCollection{EmptyPoint(), LineString{}}); ok {
+		t.Errorf("GeometryBounds() of empty geometries reported bounds")
+	}
+}
+
+// TestGeoJSON_EmptyPositions tests the round trip of EmptyPoint inside a MultiPoint
+// and a LineString, which are written as empty positions.
+func TestGeoJSON_EmptyPositions(t *testing.T) {
+	for _, g := range []Geometry{
+		MultiPoint{EmptyPoint(), {X: 1, Y: 2}},
+		LineString{{X: 1, Y: 2}, EmptyPoint()},
+	} {
+		data, err := MarshalGeoJSON(g)
+		if err != nil {
+			t.Fatalf("MarshalGeoJSON(%v) error = %v", g, err)
+		}
+		got, err := UnmarshalGeoJSON(data)
+		if err != nil {
+			t.Fatalf("UnmarshalGeoJSON(%s) error = %v", data, err)
+		}
+		again, err := MarshalGeoJSON(got)
+		if err != nil || reflect.TypeOf(got) != reflect.TypeOf(g) || string(again) != string(data) {
+			t.Errorf("round trip of %s = %#v, %s; want %#v", data, got, again, g)
+		}
+	}
+	got, err := UnmarshalGeoJSON([]byte(`{"type":"MultiPoint","coordinates":[[],[1,2]]}`))
+	if mp, ok := got.(MultiPoint); err != nil || !ok || len(mp) != 2 || !isEmptyPoint(mp[0]) || mp[1] != (Point{X: 1, Y: 2}) {
+		t.Errorf("UnmarshalGeoJSON() = %#v, %v; want an empty point and (1, 2)", got, err)
+	}
+}
+
+// mustMarshal encodes v as JSON, failing the test on error.
+func mustMarshal(t *testing.T, v any) []byte {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	return data
+}
@@ -0,0 +1,126 @@
+package tochka
+
+import "math"
+
+// Geometry is a simple feature geometry of the OGC model, as read and written by the
+// WKT and WKB codecs. It is one of Point, LineString, Polygon, MultiPoint,
+// MultiLineString, MultiPolygon and GeometryCollection.
+//
+// An empty point is represented by a Point with NaN coordinates, see EmptyPoint.
+// Polygon rings follow the Ring convention of not repeating the first vertex;
+// the codecs add and remove the closing vertex required by the formats.
+type Geometry interface {
+	// geometryType returns the name of the geometry type in WKT.
+	geometryType() string
+}
+
+// LineString is an open polyline.
+type LineString []Point
+
+// MultiPoint is a collection of points.
+type MultiPoint []Point
+
+// MultiLineString is a collection of polylines.
+type MultiLineString []LineString
+
+// MultiPolygon is a collection of polygons.
+type MultiPolygon []Polygon
+
+// GeometryCollection is a heterogeneous collection of geometries.
+type GeometryCollection []Geometry
+
+func (Point) geometryType() string              { return "POINT" }
+func (LineString) geometryType() string         { return "LINESTRING" }
+func (Polygon) geometryType() string            { return "POLYGON" }
+func (MultiPoint) geometryType() string         { return "MULTIPOINT" }
+func (MultiLineString) geometryType() string    { return "MULTILINESTRING" }
+func (MultiPolygon) geometryType() string       { return "MULTIPOLYGON" }
+func (GeometryCollection) geometryType() string { return "GEOMETRYCOLLECTION" }
+
+// EmptyPoint returns the empty point, whose coordinates are NaN as in WKB.
+func EmptyPoint() Point {
+	nan := float32(math.NaN())
+	return Point{X: nan, Y: nan}
+}
+
+// GeometryBounds returns the smallest rectangle containing every vertex of g, which is
+// suitable as a GeoJSON bbox. Unlike Rect.Union it keeps degenerate extents, so the
+// bounds of a single point are a zero-size rectangle at the point. It reports false
+// if g has no vertices.
+func GeometryBounds(g Geometry) (Rect, bool) {
+	var r Rect
+	found := false
+	add := func(p Point) {
+		switch {
+		case isEmptyPoint(p):
+		case !found:
+			r, found = Rect{Min: p, Max: p}, true
+		default:
+			r.Min = Point{X: min(r.Min.X, p.X), Y: min(r.Min.Y, p.Y)}
+			r.Max = Point{X: max(r.Max.X, p.X), Y: max(r.Max.Y, p.Y)}
+		}
+	}
+	var walk func(g Geometry)
+	walk = func(g Geometry) {
+		switch g := g.(type) {
+		case Point:
+			add(g)
+		case LineString:
+			for _, p := range g {
+				add(p)
+			}
+		case Polygon:
+			for _, ring := range append([]Ring{g.Outer}, g.Holes...) {
+				for _, p := range ring {
+					add(p)
+				}
+			}
+		case MultiPoint:
+			for _, p := range g {
+				add(p)
+			}
+		case MultiLineString:
+			for _, l := range g {
+				walk(l)
+			}
+		case MultiPolygon:
+			for _, p := range g {
+				walk(p)
+			}
+		case GeometryCollection:
+			for _, h := range g {
+				walk(h)
+			}
+		}
+	}
+	walk(g)
+	return r, found
+}
+
+// isEmptyPoint reports whether p is the empty point.
+func isEmptyPoint(p Point) bool {
+	return p.X != p.X && p.Y != p.Y
+}
+
+// closeRing returns the vertices of r followed by its first vertex, as the
+// formats store rings.
+func closeRing(r Ring) []Point {
+	if len(r) == 0 {
+		return nil
+	}
+	return append(r[:len(r):len(r)], r[0])
+}
+
+// openRing checks that pts form a closed ring of at least four vertices and returns it
+// without the closing vertex. It returns an empty string on success, or the problem.
+func openRing(pts []Point) (Ring, string) {
+	switch {
+	case len(pts) == 0:
+		return nil, ""
+	case len(pts) < 4:
+		return nil, "ring has fewer than 4 points"
+	case pts[0] != pts[len(pts)-1]:
+		return nil, "ring is not closed"
+	}
+	return Ring(pts[:len(pts)-1]), ""
+}
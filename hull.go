@@ -0,0 +1,69 @@
+package tochka
+
+import (
+	"cmp"
+	"slices"
+)
+
+// ConvexHull returns the convex hull of the points computed with Andrew's monotone chain
+// algorithm in O(n log n) time, with turns decided exactly by Orient2D. The hull is
+// counterclockwise, assuming a Y axis pointing up, and starts at the point with the
+// smallest X, and the smallest Y among those. Points lying on the hull edges are omitted,
+// as are duplicates.
+//
+// Degenerate inputs yield degenerate rings: a single point for identical points and the
+// two extreme points for collinear ones. The input slice is not modified.
+func ConvexHull(points []Point) Ring {
+	return convexHull(points, false)
+}
+
+// ConvexHullCollinear is like ConvexHull but keeps the points lying on the hull edges,
+// which is useful when the outline should pass through every boundary sample. For
+// collinear input it returns every distinct point ordered along the line.
+func ConvexHullCollinear(points []Point) Ring {
+	return convexHull(points, true)
+}
+
+// convexHull implements ConvexHull and ConvexHullCollinear.
+func convexHull(points []Point, collinear bool) Ring {
+	pts := slices.Clone(points)
+	slices.SortFunc(pts, func(a, b Point) int {
+		return cmp.Or(cmp.Compare(a.X, b.X), cmp.Compare(a.Y, b.Y))
+	})
+	pts = slices.Compact(pts)
+	if len(pts) < 3 {
+		return Ring(pts)
+	}
+	first, last := pts[0], pts[len(pts)-1]
+	if !slices.ContainsFunc(pts, func(p Point) bool { return Orient2D(first, last, p) != Collinear }) {
+		if collinear {
+			return Ring(pts)
+		}
+		return Ring{first, last}
+	}
+
+	// A chain drops its last vertex while it does not turn left. Collinear vertices are
+	// kept by dropping only on right turns.
+	keep := func(o Orientation) bool {
+		return o == CounterClockwise || collinear && o == Collinear
+	}
+	hull := make(Ring, 0, 2*len(pts))
+	// The lower chain runs left to right.
+	for _, p := range pts {
+		for len(hull) >= 2 && !keep(Orient2D(hull[len(hull)-2], hull[len(hull)-1], p)) {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	// The upper chain runs right to left, without popping into the lower chain.
+	lower := len(hull)
+	for i := len(pts) - 2; i >= 0; i-- {
+		p := pts[i]
+		for len(hull) > lower && !keep(Orient2D(hull[len(hull)-2], hull[len(hull)-1], p)) {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	// The upper chain ends at the first point, which is already in the hull.
+	return hull[:len(hull)-1]
+}
@@ -0,0 +1,110 @@
+package tochka
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// TestConvexHull tests simple and degenerate inputs.
+func TestConvexHull(t *testing.T) {
+	pt := func(x, y float32) Point { return Point{X: x, Y: y} }
+	tests := []struct {
+		name      string
+		points    []Point
+		hull      Ring
+		collinear Ring
+	}{
+		{"empty", nil, nil, nil},
+		{"single point", []Point{pt(1, 2)}, Ring{pt(1, 2)}, Ring{pt(1, 2)}},
+		{"duplicates", []Point{pt(1, 2), pt(1, 2), pt(1, 2)}, Ring{pt(1, 2)}, Ring{pt(1, 2)}},
+		{"two points", []Point{pt(3, 0), pt(0, 0), pt(3, 0)}, Ring{pt(0, 0), pt(3, 0)}, Ring{pt(0, 0), pt(3, 0)}},
+		{
+			"collinear",
+			[]Point{pt(2, 2), pt(0, 0), pt(3, 3), pt(1, 1), pt(2, 2)},
+			Ring{pt(0, 0), pt(3, 3)},
+			Ring{pt(0, 0), pt(1, 1), pt(2, 2), pt(3, 3)},
+		},
+		{
+			"vertical",
+			[]Point{pt(0, 2), pt(0, -1), pt(0, 0)},
+			Ring{pt(0, -1), pt(0, 2)},
+			Ring{pt(0, -1), pt(0, 0), pt(0, 2)},
+		},
+		{
+			"triangle with interior point",
+			[]Point{pt(4, 0), pt(1, 1), pt(0, 4), pt(0, 0)},
+			Ring{pt(0, 0), pt(4, 0), pt(0, 4)},
+			Ring{pt(0, 0), pt(4, 0), pt(0, 4)},
+		},
+		{
+			"square with edge midpoints",
+			[]Point{pt(2, 2), pt(1, 0), pt(0, 1), pt(2, 1), pt(1, 2), pt(0, 0), pt(2, 0), pt(0, 2), pt(1, 1), pt(0, 0)},
+			Ring{pt(0, 0), pt(2, 0), pt(2, 2), pt(0, 2)},
+			Ring{pt(0, 0), pt(1, 0), pt(2, 0), pt(2, 1), pt(2, 2), pt(1, 2), pt(0, 2), pt(0, 1)},
+		},
+	}
+	for _, tt := range tests {
+		input := slices.Clone(tt.points)
+		if got := ConvexHull(tt.points); !reflect.DeepEqual(got, tt.hull) {
+			t.Errorf("%s: ConvexHull() = %v; want %v", tt.name, got, tt.hull)
+		}
+		if got := ConvexHullCollinear(tt.points); !reflect.DeepEqual(got, tt.collinear) {
+			t.Errorf("%s: ConvexHullCollinear() = %v; want %v", tt.name, got, tt.collinear)
+		}
+		if !slices.Equal(input, tt.points) {
+			t.Errorf("%s: the input was modified", tt.name)
+		}
+	}
+}
+
+// TestConvexHull_Random tests that hulls of random points on a coarse grid, which are
+// rich in collinear points, are convex, counterclockwise and contain every point.
+func TestConvexHull_Random(t *testing.T) {
+	r := rand.New(rand.NewPCG(3, 4))
+	for range 200 {
+		points := make([]Point, 3+r.IntN(40))
+		for k := range points {
+			points[k] = Point{X: float32(r.IntN(8)), Y: float32(r.IntN(8))}
+		}
+		for _, collinear := range []bool{false, true} {
+			hull := convexHull(points, collinear)
+			if len(hull) < 3 {
+				continue
+			}
+			sorted := slices.Clone(hull)
+			slices.SortFunc(sorted, func(a, b Point) int {
+				return cmp.Or(cmp.Compare(a.X, b.X), cmp.Compare(a.Y, b.Y))
+			})
+			if len(slices.Compact(sorted)) != len(hull) {
+				t.Fatalf("hull %v of %v repeats a vertex", hull, points)
+			}
+			if hull.Orientation() != CounterClockwise {
+				t.Fatalf("hull %v of %v is not counterclockwise", hull, points)
+			}
+			for k := range hull {
+				a, b, c := hull[k], hull[(k+1)%len(hull)], hull[(k+2)%len(hull)]
+				if turn := Orient2D(a, b, c); turn == Clockwise || turn == Collinear && !collinear {
+					t.Fatalf("hull %v of %v turns wrongly at %v", hull, points, b)
+				}
+			}
+			for _, p := range points {
+				onEdge := false
+				for k := range hull {
+					a, b := hull[k], hull[(k+1)%len(hull)]
+					turn := Orient2D(a, b, p)
+					if turn == Clockwise {
+						t.Fatalf("hull %v of %v excludes %v", hull, points, p)
+					}
+					onEdge = onEdge || turn == Collinear &&
+						min(a.X, b.X) <= p.X && p.X <= max(a.X, b.X) && min(a.Y, b.Y) <= p.Y && p.Y <= max(a.Y, b.Y)
+				}
+				if collinear && onEdge && !slices.Contains(hull, p) {
+					t.Fatalf("collinear hull %v of %v misses the boundary point %v", hull, points, p)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,278 @@
+package tochka
+
+import "math"
+
+// parallelEpsilon is the relative tolerance below which two directions are treated as parallel
+// and a point is treated as lying on a line. It matches the precision of float32 coordinates.
+const parallelEpsilon = 1e-7
+
+// IntersectionKind describes how two linear primitives intersect.
+type IntersectionKind int
+
+const (
+	// NoIntersection means that the primitives do not meet.
+	NoIntersection IntersectionKind = iota
+	// PointIntersection means that the primitives meet in a single point.
+	PointIntersection
+	// OverlapIntersection means that the primitives are collinear and share a portion of their length.
+	OverlapIntersection
+)
+
+// Intersection describes the intersection of two linear primitives.
+// Parameters are expressed along each primitive, where a parameter t denotes the point
+// Origin + t*Dir of a ray or line and the point A + t*(B-A) of a segment.
+type Intersection struct {
+	// Kind tells how the primitives intersect. The remaining fields are only meaningful
+	// if Kind is not NoIntersection.
+	Kind IntersectionKind
+	// Point is the hit point, or the start of the shared portion for an overlap.
+	Point Point
+	// T and U are the parameters of Point along the first and the second primitive.
+	T, U float32
+	// End is the end of the shared portion for an overlap.
+	End Point
+	// EndT and EndU are the parameters of End along the first and the second primitive.
+	EndT, EndU float32
+}
+
+// Segment represents the line segment between the points A and B.
+type Segment struct {
+	A, B Point
+}
+
+// Ray represents the half-line starting at Origin and extending in the direction Dir.
+type Ray struct {
+	Origin, Dir Point
+}
+
+// Line represents the infinite line through Origin with the direction Dir.
+type Line struct {
+	Origin, Dir Point
+}
+
+// NewSegment creates a segment between the points a and b.
+func NewSegment(a, b Point) Segment {
+	return Segment{A: a, B: b}
+}
+
+// Length returns the length of the segment.
+func (s Segment) Length() float32 {
+	return s.A.Distance(s.B)
+}
+
+// Midpoint returns the point halfway between A and B.
+func (s Segment) Midpoint() Point {
+	return Point{X: (s.A.X + s.B.X) / 2, Y: (s.A.Y + s.B.Y) / 2}
+}
+
+// At returns the point at parameter t, where t = 0 yields A and t = 1 yields B.
+func (s Segment) At(t float32) Point {
+	return s.A.Add(s.B.Sub(s.A).Mul(t))
+}
+
+// Line returns the infinite line through the segment.
+func (s Segment) Line() Line {
+	return Line{Origin: s.A, Dir: s.B.Sub(s.A)}
+}
+
+// Project returns the parameter of the orthogonal projection of p onto the line through the segment.
+// The result lies in [0, 1] if the projection falls within the segment.
+func (s Segment) Project(p Point) float32 {
+	return float32(project64(s.A, s.B.Sub(s.A), p))
+}
+
+// ClosestPoint returns the point of the segment closest to p.
+func (s Segment) ClosestPoint(p Point) Point {
+	return s.At(clamp(s.Project(p), 0, 1))
+}
+
+// Distance returns the distance between p and the closest point of the segment.
+func (s Segment) Distance(p Point) float32 {
+	return s.ClosestPoint(p).Distance(p)
+}
+
+// Bounds returns the axis-aligned bounds of the segment.
+func (s Segment) Bounds() Rect {
+	return boundsOf([]Point{s.A, s.B})
+}
+
+// Transform applies the transformation a to the segment.
+func (s Segment) Transform(a Affine2D) Segment {
+	return Segment{A: a.Transform(s.A), B: a.Transform(s.B)}
+}
+
+// Intersect computes the intersection of the segment with another segment.
+func (s Segment) Intersect(o Segment) Intersection {
+	return intersect(s.A, s.B.Sub(s.A), 0, 1, o.A, o.B.Sub(o.A), 0, 1)
+}
+
+// At returns the point at parameter t, that is Origin + t*Dir.
+func (r Ray) At(t float32) Point {
+	return r.Origin.Add(r.Dir.Mul(t))
+}
+
+// Project returns the parameter of the orthogonal projection of p onto the line through the ray.
+// The result is negative if the projection falls behind the origin.
+func (r Ray) Project(p Point) float32 {
+	return float32(project64(r.Origin, r.Dir, p))
+}
+
+// ClosestPoint returns the point of the ray closest to p.
+func (r Ray) ClosestPoint(p Point) Point {
+	return r.At(max(r.Project(p), 0))
+}
+
+// Distance returns the distance between p and the closest point of the ray.
+func (r Ray) Distance(p Point) float32 {
+	return r.ClosestPoint(p).Distance(p)
+}
+
+// Transform applies the transformation a to the ray.
+func (r Ray) Transform(a Affine2D) Ray {
+	linear, _ := a.Split()
+	return Ray{Origin: a.Transform(r.Origin), Dir: linear.Transform(r.Dir)}
+}
+
+// IntersectSegment computes the intersection of the ray with a segment.
+// T is expressed along the ray and U along the segment.
+func (r Ray) IntersectSegment(s Segment) Intersection {
+	return intersect(r.Origin, r.Dir, 0, math.Inf(1), s.A, s.B.Sub(s.A), 0, 1)
+}
+
+// At returns the point at parameter t, that is Origin + t*Dir.
+func (l Line) At(t float32) Point {
+	return l.Origin.Add(l.Dir.Mul(t))
+}
+
+// Project returns the parameter of the orthogonal projection of p onto the line.
+func (l Line) Project(p Point) float32 {
+	return float32(project64(l.Origin, l.Dir, p))
+}
+
+// ClosestPoint returns the orthogonal projection of p onto the line.
+func (l Line) ClosestPoint(p Point) Point {
+	return l.At(l.Project(p))
+}
+
+// Distance returns the distance between p and the line.
+func (l Line) Distance(p Point) float32 {
+	return l.ClosestPoint(p).Distance(p)
+}
+
+// Transform applies the transformation a to the line.
+func (l Line) Transform(a Affine2D) Line {
+	linear, _ := a.Split()
+	return Line{Origin: a.Transform(l.Origin), Dir: linear.Transform(l.Dir)}
+}
+
+// Intersect computes the intersection of the line with another line.
+// Coincident lines are reported as an OverlapIntersection whose Point and End
+// are the points of the first line at parameters 0 and 1.
+func (l Line) Intersect(o Line) Intersection {
+	inf := math.Inf(1)
+	in := intersect(l.Origin, l.Dir, -inf, inf, o.Origin, o.Dir, -inf, inf)
+	if in.Kind == OverlapIntersection {
+		in.Point, in.T, in.U = l.Origin, 0, float32(project64(o.Origin, o.Dir, l.Origin))
+		end := l.At(1)
+		in.End, in.EndT, in.EndU = end, 1, float32(project64(o.Origin, o.Dir, end))
+	}
+	return in
+}
+
+// project64 returns the parameter of the orthogonal projection of p onto the line origin + t*dir
+// in double precision. The parameter is zero for a degenerate direction.
+func project64(origin, dir, p Point) float64 {
+	d := dir.To64()
+	dd := d.Dot(d)
+	if dd == 0 {
+		return 0
+	}
+	return p.To64().Sub(origin.To64()).Dot(d) / dd
+}
+
+// intersect computes the intersection of the parametric primitives p + t*d with t in [tmin, tmax]
+// and q + u*e with u in [umin, umax]. The computation is carried out in double precision.
+func intersect(p, d Point, tmin, tmax float64, q, e Point, umin, umax float64) Intersection {
+	p64, d64, q64, e64 := p.To64(), d.To64(), q.To64(), e.To64()
+	dd, ee := d64.Dot(d64), e64.Dot(e64)
+	r := q64.Sub(p64)
+
+	switch {
+	case dd == 0 && ee == 0:
+		if r != (Point64{}) || !inRange(0, tmin, tmax) || !inRange(0, umin, umax) {
+			return Intersection{}
+		}
+		return pointIntersection(p64, 0, 0)
+	case dd == 0:
+		// The first primitive degenerates to the point p.
+		u := -r.Dot(e64) / ee
+		if !inRange(0, tmin, tmax) || !inRange(u, umin, umax) || !onLine(r.Mul(-1), e64) {
+			return Intersection{}
+		}
+		return pointIntersection(p64, 0, u)
+	case ee == 0:
+		// The second primitive degenerates to the point q.
+		t := r.Dot(d64) / dd
+		if !inRange(t, tmin, tmax) || !inRange(0, umin, umax) || !onLine(r, d64) {
+			return Intersection{}
+		}
+		return pointIntersection(q64, t, 0)
+	}
+
+	denom := d64.Cross(e64)
+	if math.Abs(denom) > parallelEpsilon*math.Sqrt(dd*ee) {
+		t := r.Cross(e64) / denom
+		u := r.Cross(d64) / denom
+		if !inRange(t, tmin, tmax) || !inRange(u, umin, umax) {
+			return Intersection{}
+		}
+		return pointIntersection(p64.Add(d64.Mul(t)), t, u)
+	}
+	if !onLine(r, d64) {
+		return Intersection{} // parallel
+	}
+
+	// The primitives are collinear, map the range of the second one onto the first.
+	t0, k := r.Dot(d64)/dd, e64.Dot(d64)/dd
+	lo, hi := t0+umin*k, t0+umax*k
+	if k < 0 {
+		lo, hi = hi, lo
+	}
+	lo, hi = math.Max(lo, tmin), math.Min(hi, tmax)
+	if lo > hi {
+		return Intersection{}
+	}
+	paramU := func(t float64) float64 { return (t - t0) / k }
+	if lo == hi {
+		return pointIntersection(p64.Add(d64.Mul(lo)), lo, paramU(lo))
+	}
+	return Intersection{
+		Kind:  OverlapIntersection,
+		Point: p64.Add(d64.Mul(lo)).To32(),
+		T:     float32(lo),
+		U:     float32(paramU(lo)),
+		End:   p64.Add(d64.Mul(hi)).To32(),
+		EndT:  float32(hi),
+		EndU:  float32(paramU(hi)),
+	}
+}
+
+// pointIntersection returns an intersection in the single point p with the parameters t and u.
+func pointIntersection(p Point64, t, u float64) Intersection {
+	return Intersection{Kind: PointIntersection, Point: p.To32(), T: float32(t), U: float32(u)}
+}
+
+// onLine reports whether the offset r lies on the line with direction d, within parallelEpsilon.
+func onLine(r, d Point64) bool {
+	return math.Abs(r.Cross(d)) <= parallelEpsilon*r.Magnitude()*d.Magnitude()
+}
+
+// inRange reports whether v lies in the closed interval [lo, hi].
+func inRange(v, lo, hi float64) bool {
+	return lo <= v && v <= hi
+}
+
+// clamp restricts v to the closed interval [lo, hi].
+func clamp(v, lo, hi float32) float32 {
+	return min(max(v, lo), hi)
+}
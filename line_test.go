@@ -0,0 +1,175 @@
+package tochka
+
+import (
+	"math"
+	"testing"
+)
+
+// TestSegment_Basics tests length, midpoint, projection and distance of a segment.
+func TestSegment_Basics(t *testing.T) {
+	s := NewSegment(Point{X: 0, Y: 0}, Point{X: 4, Y: 0})
+	if s.Length() != 4 || s.Midpoint() != (Point{X: 2, Y: 0}) {
+		t.Errorf("Length() = %v, Midpoint() = %v; want 4, (2, 0)", s.Length(), s.Midpoint())
+	}
+	tests := []struct {
+		p       Point
+		t       float32
+		closest Point
+		dist    float32
+	}{
+		{Point{X: 1, Y: 3}, 0.25, Point{X: 1, Y: 0}, 3},
+		{Point{X: -3, Y: 4}, -0.75, Point{X: 0, Y: 0}, 5},
+		{Point{X: 7, Y: -4}, 1.75, Point{X: 4, Y: 0}, 5},
+	}
+	for _, tt := range tests {
+		if got := s.Project(tt.p); got != tt.t {
+			t.Errorf("Project(%v) = %v; want %v", tt.p, got, tt.t)
+		}
+		if got := s.ClosestPoint(tt.p); got != tt.closest {
+			t.Errorf("ClosestPoint(%v) = %v; want %v", tt.p, got, tt.closest)
+		}
+		if got := s.Distance(tt.p); got != tt.dist {
+			t.Errorf("Distance(%v) = %v; want %v", tt.p, got, tt.dist)
+		}
+	}
+	if got, want := s.Bounds(), NewRect(0, 0, 4, 0); got != want {
+		t.Errorf("Bounds() = %v; want %v", got, want)
+	}
+}
+
+// TestRayLine_Distance tests the closest points of rays and lines.
+func TestRayLine_Distance(t *testing.T) {
+	r := Ray{Origin: Point{X: 0, Y: 0}, Dir: Point{X: 1, Y: 0}}
+	if got := r.ClosestPoint(Point{X: -3, Y: 4}); got != (Point{}) {
+		t.Errorf("Ray.ClosestPoint() = %v; want the origin", got)
+	}
+	if got := r.Distance(Point{X: 10, Y: 2}); got != 2 {
+		t.Errorf("Ray.Distance() = %v; want 2", got)
+	}
+	l := Line{Origin: Point{X: 0, Y: 0}, Dir: Point{X: 1, Y: 1}}
+	if got := l.ClosestPoint(Point{X: -2, Y: 0}); got != (Point{X: -1, Y: -1}) {
+		t.Errorf("Line.ClosestPoint() = %v; want (-1, -1)", got)
+	}
+	if got := l.Distance(Point{X: 0, Y: 2}); !almostEqual(got, math.Sqrt2, 1e-6) {
+		t.Errorf("Line.Distance() = %v; want √2", got)
+	}
+}
+
+// TestSegment_Intersect tests crossing, touching, parallel and collinear segments.
+func TestSegment_Intersect(t *testing.T) {
+	tests := []struct {
+		name string
+		s, o Segment
+		want Intersection
+	}{
+		{
+			"crossing",
+			NewSegment(Point{X: 0, Y: 0}, Point{X: 4, Y: 4}),
+			NewSegment(Point{X: 0, Y: 4}, Point{X: 4, Y: 0}),
+			Intersection{Kind: PointIntersection, Point: Point{X: 2, Y: 2}, T: 0.5, U: 0.5},
+		},
+		{
+			"touching endpoints",
+			NewSegment(Point{X: 0, Y: 0}, Point{X: 1, Y: 1}),
+			NewSegment(Point{X: 1, Y: 1}, Point{X: 2, Y: 0}),
+			Intersection{Kind: PointIntersection, Point: Point{X: 1, Y: 1}, T: 1, U: 0},
+		},
+		{
+			"missing",
+			NewSegment(Point{X: 0, Y: 0}, Point{X: 1, Y: 1}),
+			NewSegment(Point{X: 3, Y: 0}, Point{X: 2, Y: 1}),
+			Intersection{},
+		},
+		{
+			"parallel",
+			NewSegment(Point{X: 0, Y: 0}, Point{X: 2, Y: 0}),
+			NewSegment(Point{X: 0, Y: 1}, Point{X: 2, Y: 1}),
+			Intersection{},
+		},
+		{
+			"collinear disjoint",
+			NewSegment(Point{X: 0, Y: 0}, Point{X: 1, Y: 0}),
+			NewSegment(Point{X: 2, Y: 0}, Point{X: 3, Y: 0}),
+			Intersection{},
+		},
+		{
+			"collinear overlap",
+			NewSegment(Point{X: 0, Y: 0}, Point{X: 4, Y: 0}),
+			NewSegment(Point{X: 6, Y: 0}, Point{X: 2, Y: 0}),
+			Intersection{
+				Kind:  OverlapIntersection,
+				Point: Point{X: 2, Y: 0}, T: 0.5, U: 1,
+				End: Point{X: 4, Y: 0}, EndT: 1, EndU: 0.5,
+			},
+		},
+		{
+			"collinear touching",
+			NewSegment(Point{X: 0, Y: 0}, Point{X: 2, Y: 2}),
+			NewSegment(Point{X: 2, Y: 2}, Point{X: 3, Y: 3}),
+			Intersection{Kind: PointIntersection, Point: Point{X: 2, Y: 2}, T: 1, U: 0},
+		},
+		{
+			"degenerate on segment",
+			NewSegment(Point{X: 1, Y: 1}, Point{X: 1, Y: 1}),
+			NewSegment(Point{X: 0, Y: 0}, Point{X: 2, Y: 2}),
+			Intersection{Kind: PointIntersection, Point: Point{X: 1, Y: 1}, T: 0, U: 0.5},
+		},
+	}
+	for _, tt := range tests {
+		if got := tt.s.Intersect(tt.o); got != tt.want {
+			t.Errorf("%s: Intersect() = %+v; want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestRay_IntersectSegment tests the intersection of a ray with a segment.
+func TestRay_IntersectSegment(t *testing.T) {
+	r := Ray{Origin: Point{X: 0, Y: 0}, Dir: Point{X: 2, Y: 0}}
+	s := NewSegment(Point{X: 4, Y: -1}, Point{X: 4, Y: 1})
+	want := Intersection{Kind: PointIntersection, Point: Point{X: 4, Y: 0}, T: 2, U: 0.5}
+	if got := r.IntersectSegment(s); got != want {
+		t.Errorf("IntersectSegment() = %+v; want %+v", got, want)
+	}
+	if got := (Ray{Origin: Point{X: 5, Y: 0}, Dir: Point{X: 1, Y: 0}}).IntersectSegment(s); got.Kind != NoIntersection {
+		t.Errorf("IntersectSegment() behind the origin = %+v; want none", got)
+	}
+	collinear := NewSegment(Point{X: -2, Y: 0}, Point{X: 6, Y: 0})
+	got := r.IntersectSegment(collinear)
+	if got.Kind != OverlapIntersection || got.Point != (Point{}) || got.End != (Point{X: 6, Y: 0}) || got.EndT != 3 {
+		t.Errorf("IntersectSegment() collinear = %+v; want overlap from the origin to (6, 0)", got)
+	}
+}
+
+// TestLine_Intersect tests crossing, parallel and coincident lines.
+func TestLine_Intersect(t *testing.T) {
+	l := Line{Origin: Point{X: 0, Y: 1}, Dir: Point{X: 1, Y: 0}}
+	o := Line{Origin: Point{X: 3, Y: -5}, Dir: Point{X: 0, Y: 2}}
+	want := Intersection{Kind: PointIntersection, Point: Point{X: 3, Y: 1}, T: 3, U: 3}
+	if got := l.Intersect(o); got != want {
+		t.Errorf("Intersect() = %+v; want %+v", got, want)
+	}
+	if got := l.Intersect(Line{Origin: Point{X: 0, Y: 2}, Dir: Point{X: -1, Y: 0}}); got.Kind != NoIntersection {
+		t.Errorf("Intersect() parallel = %+v; want none", got)
+	}
+	got := l.Intersect(Line{Origin: Point{X: 5, Y: 1}, Dir: Point{X: -2, Y: 0}})
+	if got.Kind != OverlapIntersection || got.Point != l.Origin || got.U != 2.5 || got.EndU != 2 {
+		t.Errorf("Intersect() coincident = %+v; want overlap", got)
+	}
+}
+
+// TestLinear_Transform tests that primitives follow an affine transformation.
+func TestLinear_Transform(t *testing.T) {
+	a := Affine2D{}.Rotate(Point{}, math.Pi/2).Offset(Point{X: 10, Y: 0})
+	s := NewSegment(Point{X: 1, Y: 0}, Point{X: 2, Y: 0}).Transform(a)
+	if !pointAlmostEqual(s.A, Point{X: 10, Y: 1}, 1e-6) || !pointAlmostEqual(s.B, Point{X: 10, Y: 2}, 1e-6) {
+		t.Errorf("Segment.Transform() = %v", s)
+	}
+	r := Ray{Origin: Point{X: 1, Y: 0}, Dir: Point{X: 1, Y: 0}}.Transform(a)
+	if !pointAlmostEqual(r.Origin, Point{X: 10, Y: 1}, 1e-6) || !pointAlmostEqual(r.Dir, Point{X: 0, Y: 1}, 1e-6) {
+		t.Errorf("Ray.Transform() = %v", r)
+	}
+	l := Line{Origin: Point{}, Dir: Point{X: 0, Y: 1}}.Transform(a)
+	if !pointAlmostEqual(l.Origin, Point{X: 10, Y: 0}, 1e-6) || !pointAlmostEqual(l.Dir, Point{X: -1, Y: 0}, 1e-6) {
+		t.Errorf("Line.Transform() = %v", l)
+	}
+}
@@ -0,0 +1,399 @@
+package tochka
+
+import (
+	"iter"
+	"math"
+)
+
+// Verb identifies the kind of a path segment.
+type Verb uint8
+
+const (
+	// VerbMove starts a new subpath at End.
+	VerbMove Verb = iota
+	// VerbLine draws a straight line from Start to End.
+	VerbLine
+	// VerbQuad draws a quadratic Bézier curve from Start to End with the control point Ctrl[0].
+	VerbQuad
+	// VerbCubic draws a cubic Bézier curve from Start to End with the control points Ctrl[0] and Ctrl[1].
+	VerbCubic
+	// VerbArc draws the elliptical arc Arc from Start to End.
+	VerbArc
+	// VerbClose draws a straight line from Start back to the start of the subpath at End
+	// and ends the subpath.
+	VerbClose
+)
+
+// PathSegment describes a single command of a path as produced by Path.Segments.
+type PathSegment struct {
+	Verb Verb
+	// Start is the current point before the command. For VerbMove it is the end
+	// of the previous subpath.
+	Start Point
+	// End is the current point after the command.
+	End Point
+	// Ctrl holds the control points of Bézier curves.
+	Ctrl [2]Point
+	// Arc holds the arc drawn by VerbArc. Its endpoints match Start and End
+	// up to rounding, in which case Start and End are authoritative.
+	Arc Arc
+}
+
+// Quad returns the segment as a quadratic Bézier curve. It is only meaningful for VerbQuad.
+func (s PathSegment) Quad() QuadBezier {
+	return QuadBezier{P0: s.Start, P1: s.Ctrl[0], P2: s.End}
+}
+
+// Cubic returns the segment as a cubic Bézier curve. It is only meaningful for VerbCubic.
+func (s PathSegment) Cubic() CubicBezier {
+	return CubicBezier{P0: s.Start, P1: s.Ctrl[0], P2: s.Ctrl[1], P3: s.End}
+}
+
+// Bounds returns the tight axis-aligned bounds of the segment. The bounds of a VerbMove
+// segment contain only its End.
+func (s PathSegment) Bounds() Rect {
+	switch s.Verb {
+	case VerbMove:
+		return Rect{Min: s.End, Max: s.End}
+	case VerbQuad:
+		return s.Quad().Bounds()
+	case VerbCubic:
+		return s.Cubic().Bounds()
+	case VerbArc:
+		b := s.Arc.Bounds()
+		return boundsOf([]Point{b.Min, b.Max, s.Start, s.End})
+	}
+	return boundsOf([]Point{s.Start, s.End})
+}
+
+// Path is a sequence of drawing commands forming any number of open or closed subpaths.
+// The zero value is an empty path ready to use. Drawing commands issued before the first
+// MoveTo start at the origin, and drawing commands following Close start a new subpath
+// at the point where the closed subpath started.
+type Path struct {
+	verbs  []Verb
+	points []Point
+	arcs   []Arc
+	// start is the first point of the current subpath and cur is the current point.
+	start, cur Point
+	// open reports whether a subpath is in progress.
+	open bool
+}
+
+// NewPath creates an empty path.
+func NewPath() *Path {
+	return &Path{}
+}
+
+// Len returns the number of commands in the path.
+func (p *Path) Len() int {
+	return len(p.verbs)
+}
+
+// Empty reports whether the path has no commands.
+func (p *Path) Empty() bool {
+	return len(p.verbs) == 0
+}
+
+// CurrentPoint returns the point where the next drawing command starts.
+func (p *Path) CurrentPoint() Point {
+	return p.cur
+}
+
+// Reset removes all commands from the path while keeping the allocated storage.
+func (p *Path) Reset() {
+	*p = Path{verbs: p.verbs[:0], points: p.points[:0], arcs: p.arcs[:0]}
+}
+
+// MoveTo starts a new subpath at pt.
+func (p *Path) MoveTo(pt Point) {
+	p.verbs = append(p.verbs, VerbMove)
+	p.points = append(p.points, pt)
+	p.start, p.cur, p.open = pt, pt, true
+}
+
+// LineTo draws a straight line from the current point to pt.
+func (p *Path) LineTo(pt Point) {
+	p.begin()
+	p.verbs = append(p.verbs, VerbLine)
+	p.points = append(p.points, pt)
+	p.cur = pt
+}
+
+// QuadTo draws a quadratic Bézier curve from the current point to pt with the control point ctrl.
+func (p *Path) QuadTo(ctrl, pt Point) {
+	p.begin()
+	p.verbs = append(p.verbs, VerbQuad)
+	p.points = append(p.points, ctrl, pt)
+	p.cur = pt
+}
+
+// CubicTo draws a cubic Bézier curve from the current point to pt with the control points c1 and c2.
+func (p *Path) CubicTo(c1, c2, pt Point) {
+	p.begin()
+	p.verbs = append(p.verbs, VerbCubic)
+	p.points = append(p.points, c1, c2, pt)
+	p.cur = pt
+}
+
+// ArcTo draws an elliptical arc from the current point to pt following the endpoint
+// parameterization of SVG. The ellipse has the specified radii and its X axis is rotated
+// by rotation radians. Of the four arcs satisfying these constraints, largeArc selects
+// one spanning more than 180 degrees and sweep selects one running in the direction of
+// increasing angles. Radii too small to reach pt are scaled up uniformly; a zero radius
+// draws a straight line, and an arc ending at the current point is omitted.
+func (p *Path) ArcTo(radii Point, rotation float32, largeArc, sweep bool, pt Point) {
+	if pt == p.cur {
+		return
+	}
+	a, ok := arcFromEndpoints(p.cur, pt, radii, rotation, largeArc, sweep)
+	if !ok {
+		p.LineTo(pt)
+		return
+	}
+	p.arcTo(a, pt)
+}
+
+// Close draws a straight line back to the start of the current subpath and ends the subpath.
+// It does nothing if no subpath is in progress.
+func (p *Path) Close() {
+	if !p.open {
+		return
+	}
+	p.verbs = append(p.verbs, VerbClose)
+	p.cur, p.open = p.start, false
+}
+
+// AddArc draws the arc a. It is connected to the current subpath by a straight line,
+// or starts a new subpath if none is in progress.
+func (p *Path) AddArc(a Arc) {
+	from := a.At(0)
+	if !p.open {
+		p.MoveTo(from)
+	} else if from != p.cur {
+		p.LineTo(from)
+	}
+	p.arcTo(a, a.At(1))
+}
+
+// AddEllipse adds the ellipse e as a closed subpath starting at the end of its X axis.
+func (p *Path) AddEllipse(e Ellipse) {
+	p.MoveTo(NewArc(e, 0, 0).At(0))
+	p.arcTo(NewArc(e, 0, 2*math.Pi), p.cur)
+	p.Close()
+}
+
+// AddRect adds the rectangle r as a closed subpath wound counterclockwise,
+// assuming a Y axis pointing up.
+func (p *Path) AddRect(r Rect) {
+	p.MoveTo(r.Min)
+	p.LineTo(Point{X: r.Max.X, Y: r.Min.Y})
+	p.LineTo(r.Max)
+	p.LineTo(Point{X: r.Min.X, Y: r.Max.Y})
+	p.Close()
+}
+
+// AddRing adds the ring r as a closed subpath. Empty rings are ignored.
+func (p *Path) AddRing(r Ring) {
+	if len(r) == 0 {
+		return
+	}
+	p.MoveTo(r[0])
+	for _, pt := range r[1:] {
+		p.LineTo(pt)
+	}
+	p.Close()
+}
+
+// AddPolygon adds the outer ring and the holes of the polygon as closed subpaths.
+func (p *Path) AddPolygon(poly Polygon) {
+	p.AddRing(poly.Outer)
+	for _, h := range poly.Holes {
+		p.AddRing(h)
+	}
+}
+
+// Segments returns an iterator over the commands of the path.
+func (p *Path) Segments() iter.Seq[PathSegment] {
+	return func(yield func(PathSegment) bool) {
+		var start, cur Point
+		pi, ai := 0, 0
+		for _, v := range p.verbs {
+			s := PathSegment{Verb: v, Start: cur}
+			switch v {
+			case VerbMove:
+				s.End = p.points[pi]
+				start = s.End
+				pi++
+			case VerbLine:
+				s.End = p.points[pi]
+				pi++
+			case VerbQuad:
+				s.Ctrl[0], s.End = p.points[pi], p.points[pi+1]
+				pi += 2
+			case VerbCubic:
+				s.Ctrl[0], s.Ctrl[1], s.End = p.points[pi], p.points[pi+1], p.points[pi+2]
+				pi += 3
+			case VerbArc:
+				s.Arc, s.End = p.arcs[ai], p.points[pi]
+				ai++
+				pi++
+			case VerbClose:
+				s.End = start
+			}
+			cur = s.End
+			if !yield(s) {
+				return
+			}
+		}
+	}
+}
+
+// Bounds returns the tight axis-aligned bounds of the path, including the points
+// of MoveTo commands.
+func (p *Path) Bounds() Rect {
+	var points []Point
+	for s := range p.Segments() {
+		b := s.Bounds()
+		points = append(points, b.Min, b.Max)
+	}
+	return boundsOf(points)
+}
+
+// Transform returns a copy of the path with the transformation a applied to every command.
+// Béziers are transformed through their control points and arcs exactly.
+func (p *Path) Transform(a Affine2D) *Path {
+	out := &Path{
+		verbs:  append([]Verb(nil), p.verbs...),
+		points: make([]Point, len(p.points)),
+		arcs:   make([]Arc, len(p.arcs)),
+		start:  a.Transform(p.start),
+		cur:    a.Transform(p.cur),
+		open:   p.open,
+	}
+	a.TransformPoints(out.points, p.points)
+	for k, arc := range p.arcs {
+		out.arcs[k] = arc.Transform(a)
+	}
+	return out
+}
+
+// Flatten approximates every subpath by a ring whose distance from the path does not exceed
+// tolerance, as needed for filling. Open subpaths are closed implicitly and subpaths
+// consisting of a lone MoveTo are omitted. A non-positive tolerance selects DefaultFlatness.
+func (p *Path) Flatten(tolerance float32) []Ring {
+	var rings []Ring
+	for _, line := range p.flatten(flatness(tolerance)) {
+		if len(line) < 2 {
+			continue
+		}
+		if line[len(line)-1] == line[0] {
+			line = line[:len(line)-1]
+		}
+		rings = append(rings, Ring(line))
+	}
+	return rings
+}
+
+// Polylines approximates every subpath by a polyline whose distance from the path does not
+// exceed tolerance, as needed for stroking. Closed subpaths end with their first point and
+// subpaths consisting of a lone MoveTo are omitted. A non-positive tolerance selects DefaultFlatness.
+func (p *Path) Polylines(tolerance float32) [][]Point {
+	var lines [][]Point
+	for _, line := range p.flatten(flatness(tolerance)) {
+		if len(line) >= 2 {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// flatten returns the flattened subpaths of the path.
+func (p *Path) flatten(tolerance float64) [][]Point {
+	var lines [][]Point
+	var line []Point
+	for s := range p.Segments() {
+		switch s.Verb {
+		case VerbMove:
+			if line != nil {
+				lines = append(lines, line)
+			}
+			line = []Point{s.End}
+			continue
+		case VerbLine:
+			line = append(line, s.End)
+		case VerbQuad:
+			line = s.Quad().flattenTo(line, tolerance)
+		case VerbCubic:
+			line = s.Cubic().flattenTo(line, tolerance)
+		case VerbArc:
+			line = s.Arc.flattenTo(line, tolerance)
+			line[len(line)-1] = s.End
+		case VerbClose:
+			if line[len(line)-1] != s.End {
+				line = append(line, s.End)
+			}
+			lines = append(lines, line)
+			line = nil
+		}
+	}
+	if line != nil {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// begin starts a subpath at the current point if none is in progress.
+func (p *Path) begin() {
+	if !p.open {
+		p.MoveTo(p.cur)
+	}
+}
+
+// arcTo appends the arc a ending at pt to the current subpath.
+func (p *Path) arcTo(a Arc, pt Point) {
+	p.begin()
+	p.verbs = append(p.verbs, VerbArc)
+	p.points = append(p.points, pt)
+	p.arcs = append(p.arcs, a)
+	p.cur = pt
+}
+
+// arcFromEndpoints converts the endpoint parameterization of an elliptical arc used by SVG
+// to the center parameterization, following the SVG implementation notes. It reports false
+// if either radius is zero, in which case the arc degenerates to a straight line.
+func arcFromEndpoints(from, to, radii Point, rotation float32, largeArc, sweep bool) (Arc, bool) {
+	rx, ry := math.Abs(float64(radii.X)), math.Abs(float64(radii.Y))
+	if rx == 0 || ry == 0 {
+		return Arc{}, false
+	}
+	p0, p1 := from.To64(), to.To64()
+	sin, cos := math.Sincos(float64(rotation))
+	// Express the half-difference of the endpoints in the frame of the ellipse axes.
+	h := p0.Sub(p1).Mul(0.5)
+	x1, y1 := cos*h.X+sin*h.Y, -sin*h.X+cos*h.Y
+	// Scale up radii that are too small for the ellipse to pass through both endpoints.
+	if l := x1*x1/(rx*rx) + y1*y1/(ry*ry); l > 1 {
+		rx, ry = rx*math.Sqrt(l), ry*math.Sqrt(l)
+	}
+	num := rx*rx*ry*ry - rx*rx*y1*y1 - ry*ry*x1*x1
+	den := rx*rx*y1*y1 + ry*ry*x1*x1
+	coef := math.Sqrt(math.Max(0, num/den))
+	if largeArc == sweep {
+		coef = -coef
+	}
+	cx, cy := coef*rx*y1/ry, -coef*ry*x1/rx
+	mid := p0.Add(p1).Mul(0.5)
+	center := Point64{X: cos*cx - sin*cy + mid.X, Y: sin*cx + cos*cy + mid.Y}
+	start := math.Atan2((y1-cy)/ry, (x1-cx)/rx)
+	end := math.Atan2((-y1-cy)/ry, (-x1-cx)/rx)
+	delta := end - start
+	switch {
+	case sweep && delta < 0:
+		delta += 2 * math.Pi
+	case !sweep && delta > 0:
+		delta -= 2 * math.Pi
+	}
+	e := Ellipse{Center: center.To32(), Radii: Point{X: float32(rx), Y: float32(ry)}, Rotation: rotation}
+	return Arc{Ellipse: e, Start: float32(start), Sweep: float32(delta)}, true
+}
@@ -0,0 +1,188 @@
+package tochka
+
+import (
+	"math"
+	"testing"
+)
+
+// TestPath_Segments tests that the commands of a path are iterated with their points.
+func TestPath_Segments(t *testing.T) {
+	var p Path
+	p.LineTo(Point{X: 1, Y: 0})
+	p.QuadTo(Point{X: 2, Y: 0}, Point{X: 2, Y: 1})
+	p.CubicTo(Point{X: 2, Y: 2}, Point{X: 1, Y: 2}, Point{X: 0, Y: 2})
+	p.Close()
+	p.LineTo(Point{X: -1, Y: 0})
+	p.MoveTo(Point{X: 5, Y: 5})
+
+	want := []PathSegment{
+		{Verb: VerbMove, End: Point{}},
+		{Verb: VerbLine, End: Point{X: 1, Y: 0}},
+		{Verb: VerbQuad, Start: Point{X: 1, Y: 0}, Ctrl: [2]Point{{X: 2, Y: 0}}, End: Point{X: 2, Y: 1}},
+		{Verb: VerbCubic, Start: Point{X: 2, Y: 1}, Ctrl: [2]Point{{X: 2, Y: 2}, {X: 1, Y: 2}}, End: Point{X: 0, Y: 2}},
+		{Verb: VerbClose, Start: Point{X: 0, Y: 2}, End: Point{}},
+		// A drawing command after Close starts a new subpath where the closed one started.
+		{Verb: VerbMove, End: Point{}},
+		{Verb: VerbLine, End: Point{X: -1, Y: 0}},
+		{Verb: VerbMove, Start: Point{X: -1, Y: 0}, End: Point{X: 5, Y: 5}},
+	}
+	var got []PathSegment
+	for s := range p.Segments() {
+		got = append(got, s)
+	}
+	if len(got) != len(want) || p.Len() != len(want) {
+		t.Fatalf("Segments() yielded %d segments, Len() = %d; want %d", len(got), p.Len(), len(want))
+	}
+	for k := range want {
+		if got[k] != want[k] {
+			t.Errorf("segment %d = %+v; want %+v", k, got[k], want[k])
+		}
+	}
+	if p.CurrentPoint() != (Point{X: 5, Y: 5}) {
+		t.Errorf("CurrentPoint() = %v; want (5, 5)", p.CurrentPoint())
+	}
+	p.Reset()
+	if !p.Empty() || p.CurrentPoint() != (Point{}) {
+		t.Errorf("Reset() left %d commands at %v", p.Len(), p.CurrentPoint())
+	}
+}
+
+// TestPath_ArcTo tests the conversion of SVG arcs to the center parameterization.
+func TestPath_ArcTo(t *testing.T) {
+	tests := []struct {
+		name         string
+		large, sweep bool
+		center       Point
+		sign         float64
+	}{
+		{"small positive", false, true, Point{X: 1, Y: 0}, 1},
+		{"small negative", false, false, Point{X: 0, Y: 1}, -1},
+		{"large positive", true, true, Point{X: 0, Y: 1}, 1},
+		{"large negative", true, false, Point{X: 1, Y: 0}, -1},
+	}
+	for _, tt := range tests {
+		var p Path
+		p.MoveTo(Point{X: 1, Y: 1})
+		p.ArcTo(Point{X: 1, Y: 1}, 0, tt.large, tt.sweep, Point{X: 0, Y: 0})
+		var arc PathSegment
+		for s := range p.Segments() {
+			arc = s
+		}
+		if arc.Verb != VerbArc {
+			t.Fatalf("%s: last segment = %v; want an arc", tt.name, arc.Verb)
+		}
+		a := arc.Arc
+		if !pointAlmostEqual(a.Ellipse.Center, tt.center, 1e-6) {
+			t.Errorf("%s: center = %v; want %v", tt.name, a.Ellipse.Center, tt.center)
+		}
+		if math.Copysign(1, float64(a.Sweep)) != tt.sign || (math.Abs(float64(a.Sweep)) > math.Pi) != tt.large {
+			t.Errorf("%s: sweep = %v", tt.name, a.Sweep)
+		}
+		if !pointAlmostEqual(a.At(0), Point{X: 1, Y: 1}, 1e-6) || !pointAlmostEqual(a.At(1), Point{}, 1e-6) {
+			t.Errorf("%s: arc runs from %v to %v", tt.name, a.At(0), a.At(1))
+		}
+	}
+
+	// Radii too small to span the endpoints are scaled up to a half ellipse.
+	var p Path
+	p.MoveTo(Point{X: -4, Y: 0})
+	p.ArcTo(Point{X: 1, Y: 0.5}, 0, false, true, Point{X: 4, Y: 0})
+	for s := range p.Segments() {
+		if s.Verb == VerbArc && (s.Arc.Ellipse.Radii != Point{X: 4, Y: 2} || !almostEqual(s.Arc.Sweep, math.Pi, 1e-6)) {
+			t.Errorf("ArcTo() with small radii = %+v; want a half ellipse with radii (4, 2)", s.Arc)
+		}
+	}
+	// A zero radius yields a line.
+	p.ArcTo(Point{X: 0, Y: 1}, 0, false, true, Point{X: 5, Y: 5})
+	var last PathSegment
+	for s := range p.Segments() {
+		last = s
+	}
+	if last.Verb != VerbLine || last.End != (Point{X: 5, Y: 5}) {
+		t.Errorf("ArcTo() with a zero radius = %+v; want a line", last)
+	}
+}
+
+// TestPath_Bounds tests that the bounds of a path follow its curves tightly.
+func TestPath_Bounds(t *testing.T) {
+	var p Path
+	p.MoveTo(Point{X: 0, Y: 0})
+	p.CubicTo(Point{X: 0, Y: 4}, Point{X: 4, Y: 4}, Point{X: 4, Y: 0})
+	p.AddEllipse(NewEllipse(Point{X: 10, Y: 0}, 2, 1, 0))
+	if got, want := p.Bounds(), NewRect(0, -1, 12, 3); !pointAlmostEqual(got.Min, want.Min, 1e-6) || !pointAlmostEqual(got.Max, want.Max, 1e-6) {
+		t.Errorf("Bounds() = %v; want %v", got, want)
+	}
+	if got := (&Path{}).Bounds(); !got.Empty() {
+		t.Errorf("Bounds() of an empty path = %v; want empty", got)
+	}
+}
+
+// TestPath_Transform tests that transforming a path transforms its flattened geometry.
+func TestPath_Transform(t *testing.T) {
+	var p Path
+	p.MoveTo(Point{X: 0, Y: 0})
+	p.QuadTo(Point{X: 2, Y: 3}, Point{X: 4, Y: 0})
+	p.ArcTo(Point{X: 2, Y: 1}, 0.3, false, false, Point{X: 0, Y: 0})
+	p.Close()
+	a := Affine2D{}.Rotate(Point{}, 0.5).Scale(Point{}, Point{X: 3, Y: -2}).Offset(Point{X: 1, Y: 1})
+	q := p.Transform(a)
+	if q.Len() != p.Len() || q.CurrentPoint() != a.Transform(p.CurrentPoint()) {
+		t.Fatalf("Transform() has %d commands at %v", q.Len(), q.CurrentPoint())
+	}
+	// Every point of the transformed path must lie close to the transformed original.
+	want := p.Polylines(0.001)[0]
+	for k := range want {
+		want[k] = a.Transform(want[k])
+	}
+	for _, pt := range q.Polylines(0.01)[0] {
+		best := math.Inf(1)
+		for k := 1; k < len(want); k++ {
+			best = math.Min(best, segmentDistance64(pt.To64(), want[k-1].To64(), want[k].To64()))
+		}
+		if best > 0.02 {
+			t.Errorf("Transform() point %v is %v away from the transformed path", pt, best)
+		}
+	}
+}
+
+// TestPath_Flatten tests the rings and polylines produced from a path with several subpaths.
+func TestPath_Flatten(t *testing.T) {
+	var p Path
+	p.AddRect(NewRect(0, 0, 10, 10))
+	p.AddEllipse(NewEllipse(Point{X: 5, Y: 5}, 2, 2, 0))
+	p.MoveTo(Point{X: 20, Y: 0})
+	p.LineTo(Point{X: 30, Y: 0})
+	p.MoveTo(Point{X: 40, Y: 0})
+
+	rings := p.Flatten(0.01)
+	if len(rings) != 3 {
+		t.Fatalf("Flatten() = %d rings; want 3", len(rings))
+	}
+	if got := rings[0]; len(got) != 4 || got.Area() != 100 {
+		t.Errorf("Flatten() rectangle = %v; want 4 vertices enclosing 100", got)
+	}
+	if got, want := rings[1].Area(), float32(4*math.Pi); got > want || want-got > 0.2 {
+		t.Errorf("Flatten() circle area = %v; want close to %v", got, want)
+	}
+	if rings[1][0] == rings[1][len(rings[1])-1] {
+		t.Errorf("Flatten() circle repeats its first point")
+	}
+
+	lines := p.Polylines(0.01)
+	if len(lines) != 3 {
+		t.Fatalf("Polylines() = %d polylines; want 3", len(lines))
+	}
+	if got := lines[0]; len(got) != 5 || got[0] != got[4] {
+		t.Errorf("Polylines() rectangle = %v; want a closed polyline of 5 points", got)
+	}
+	if got := lines[2]; len(got) != 2 || got[1] != (Point{X: 30, Y: 0}) {
+		t.Errorf("Polylines() open subpath = %v", got)
+	}
+	// Holes added from a polygon keep their winding, so non-zero filling excludes them.
+	var poly Path
+	poly.AddPolygon(NewPolygon(square, Ring{{X: 1, Y: 1}, {X: 1, Y: 3}, {X: 3, Y: 3}, {X: 3, Y: 1}}))
+	r := poly.Flatten(0)
+	if got := NewPolygon(r[0], r[1:]...).Area(); got != 12 {
+		t.Errorf("Flatten() polygon area = %v; want 12", got)
+	}
+}
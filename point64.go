@@ -0,0 +1,88 @@
+package tochka
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"math"
+)
+
+// Point64 represents a point in a two-dimensional coordinate system with X and Y coordinates
+// stored in double precision. It mirrors the Point API for pipelines where float32 precision
+// is not sufficient.
+type Point64 struct {
+	X, Y float64
+}
+
+// NewPoint64 creates and returns a new double precision point with the specified x and y coordinates.
+func NewPoint64(x, y float64) Point64 {
+	return Point64{X: x, Y: y}
+}
+
+// To64 converts the point to double precision. The conversion is lossless.
+func (p Point) To64() Point64 {
+	return Point64{X: float64(p.X), Y: float64(p.Y)}
+}
+
+// To32 converts the point to single precision, rounding each coordinate to the nearest float32.
+func (p Point64) To32() Point {
+	return Point{X: float32(p.X), Y: float32(p.Y)}
+}
+
+// Add returns a new point obtained by adding the current point to another.
+func (p Point64) Add(point Point64) Point64 {
+	return Point64{X: p.X + point.X, Y: p.Y + point.Y}
+}
+
+// Sub returns a new point whose coordinates are the difference between the current point and another.
+func (p Point64) Sub(point Point64) Point64 {
+	return Point64{X: p.X - point.X, Y: p.Y - point.Y}
+}
+
+// Mul returns a new point with coordinates multiplied by a given factor s.
+func (p Point64) Mul(s float64) Point64 {
+	return Point64{X: p.X * s, Y: p.Y * s}
+}
+
+// Div returns a new point with coordinates divided by a given factor s.
+func (p Point64) Div(s float64) (Point64, error) {
+	if s == 0 {
+		return Point64{}, errors.New("division by zero")
+	}
+	return Point64{X: p.X / s, Y: p.Y / s}, nil
+}
+
+// Distance returns the distance between the current point and a given point.
+func (p Point64) Distance(point Point64) float64 {
+	dx := p.X - point.X
+	dy := p.Y - point.Y
+	return math.Sqrt(dx*dx + dy*dy)
+}
+
+// Dot returns the dot product of two vectors.
+func (p Point64) Dot(point Point64) float64 {
+	return p.X*point.X + p.Y*point.Y
+}
+
+// Cross returns the pseudovector (determinant) product of two vectors in 2D.
+func (p Point64) Cross(point Point64) float64 {
+	return p.X*point.Y - p.Y*point.X
+}
+
+// Magnitude returns the length of the vector.
+func (p Point64) Magnitude() float64 {
+	return math.Sqrt(p.X*p.X + p.Y*p.Y)
+}
+
+// Round rounds the point's coordinates to the nearest integers and returns an image.Point object.
+func (p Point64) Round() image.Point {
+	return image.Point{
+		X: int(math.Round(p.X)),
+		Y: int(math.Round(p.Y)),
+	}
+}
+
+// String returns a string representation of the point in the format "(X, Y)".
+func (p Point64) String() string {
+	return fmt.Sprintf("(%.6f, %.6f)", p.X, p.Y)
+}
@@ -0,0 +1,80 @@
+package tochka
+
+import (
+	"image"
+	"testing"
+)
+
+// TestNewPoint64 checks the creation of a new double precision point.
+func TestNewPoint64(t *testing.T) {
+	p := NewPoint64(1.5, 2.5)
+	if p.X != 1.5 || p.Y != 2.5 {
+		t.Errorf("NewPoint64(1.5, 2.5) = %v; want (1.5, 2.5)", p)
+	}
+}
+
+// TestPoint64_Conversion checks that conversion to double precision is lossless.
+func TestPoint64_Conversion(t *testing.T) {
+	p := NewPoint(0.1, -1e-7)
+	if got := p.To64().To32(); got != p {
+		t.Errorf("To64().To32() = %v; want %v", got, p)
+	}
+	if got := p.To64(); got.X != float64(p.X) || got.Y != float64(p.Y) {
+		t.Errorf("To64() = %v; want (%v, %v)", got, float64(p.X), float64(p.Y))
+	}
+}
+
+// TestPoint64_Semantics checks that Point64 produces the same results as Point.
+func TestPoint64_Semantics(t *testing.T) {
+	p1, p2 := NewPoint(1, 2), NewPoint(3, 4)
+	q1, q2 := p1.To64(), p2.To64()
+
+	if got, want := q1.Add(q2).To32(), p1.Add(p2); got != want {
+		t.Errorf("Add() = %v; want %v", got, want)
+	}
+	if got, want := q1.Sub(q2).To32(), p1.Sub(p2); got != want {
+		t.Errorf("Sub() = %v; want %v", got, want)
+	}
+	if got, want := q1.Mul(2).To32(), p1.Mul(2); got != want {
+		t.Errorf("Mul() = %v; want %v", got, want)
+	}
+	if got, want := float32(q1.Dot(q2)), p1.Dot(p2); got != want {
+		t.Errorf("Dot() = %v; want %v", got, want)
+	}
+	if got, want := float32(q1.Cross(q2)), p1.Cross(p2); got != want {
+		t.Errorf("Cross() = %v; want %v", got, want)
+	}
+	if got, want := float32(q2.Magnitude()), p2.Magnitude(); got != want {
+		t.Errorf("Magnitude() = %v; want %v", got, want)
+	}
+	if got, want := float32(q1.Distance(q2)), p1.Distance(p2); got != want {
+		t.Errorf("Distance() = %v; want %v", got, want)
+	}
+	if got, want := q1.String(), p1.String(); got != want {
+		t.Errorf("String() = %v; want %v", got, want)
+	}
+}
+
+// TestPoint64_Div checks the division of a point by a scalar, including division by zero.
+func TestPoint64_Div(t *testing.T) {
+	p := NewPoint64(4, 6)
+	result, err := p.Div(2)
+	if err != nil {
+		t.Fatalf("Div() returned an error: %v", err)
+	}
+	if expected := NewPoint64(2, 3); result != expected {
+		t.Fatalf("Div() = %v; want %v", result, expected)
+	}
+
+	if _, err = p.Div(0); err == nil {
+		t.Fatalf("Div() did not return an error for division by zero")
+	}
+}
+
+// TestPoint64_Round checks the rounding of a point, including cases with negative values.
+func TestPoint64_Round(t *testing.T) {
+	p := NewPoint64(-1.5, 2.5)
+	if result, expected := p.Round(), (image.Point{X: -2, Y: 3}); result != expected {
+		t.Errorf("Round() = %v; want %v", result, expected)
+	}
+}
@@ -0,0 +1,337 @@
+package tochka
+
+import "math"
+
+// Orientation describes the winding direction of a ring or the turn direction of three points.
+// Directions assume a Y axis pointing up; with a Y axis pointing down, as on screen,
+// counterclockwise rings appear clockwise.
+type Orientation int
+
+const (
+	// Clockwise denotes a negative signed area or a right turn.
+	Clockwise Orientation = -1
+	// Collinear denotes a degenerate ring with zero area or three points on one line.
+	Collinear Orientation = 0
+	// CounterClockwise denotes a positive signed area or a left turn.
+	CounterClockwise Orientation = 1
+)
+
+// FillRule selects how the interior of a polygon is determined from its rings.
+type FillRule int
+
+const (
+	// EvenOdd treats a point as inside if a ray from it crosses the rings an odd number of times.
+	EvenOdd FillRule = iota
+	// NonZero treats a point as inside if the rings wind around it a non-zero number of times.
+	NonZero
+)
+
+// Ring is a closed sequence of vertices. The edge from the last vertex back to the first
+// is implied, so the first vertex should not be repeated at the end.
+type Ring []Point
+
+// Polygon represents a polygon with an outer boundary and optional holes.
+type Polygon struct {
+	Outer Ring
+	Holes []Ring
+}
+
+// NewPolygon creates a polygon with the specified outer ring and holes.
+func NewPolygon(outer Ring, holes ...Ring) Polygon {
+	return Polygon{Outer: outer, Holes: holes}
+}
+
+// SignedArea returns the area enclosed by the ring computed with the shoelace formula.
+// The result is positive for counterclockwise rings and negative for clockwise rings.
+func (r Ring) SignedArea() float32 {
+	return float32(r.signedArea64())
+}
+
+// Area returns the absolute area enclosed by the ring.
+func (r Ring) Area() float32 {
+	return float32(math.Abs(r.signedArea64()))
+}
+
+// Orientation returns the winding direction of the ring.
+func (r Ring) Orientation() Orientation {
+	switch a := r.signedArea64(); {
+	case a > 0:
+		return CounterClockwise
+	case a < 0:
+		return Clockwise
+	}
+	return Collinear
+}
+
+// Reverse returns a copy of the ring with the opposite winding direction.
+func (r Ring) Reverse() Ring {
+	if r == nil {
+		return nil
+	}
+	out := make(Ring, len(r))
+	for k, p := range r {
+		out[len(r)-1-k] = p
+	}
+	return out
+}
+
+// Perimeter returns the length of the ring, including the closing edge.
+func (r Ring) Perimeter() float32 {
+	var sum float64
+	for k := range r {
+		sum += r[k].To64().Distance(r[(k+1)%len(r)].To64())
+	}
+	return float32(sum)
+}
+
+// Centroid returns the center of mass of the area enclosed by the ring.
+// For a ring with zero area, the mean of its vertices is returned.
+func (r Ring) Centroid() Point {
+	c, area := r.centroid64()
+	if area == 0 {
+		return r.vertexMean()
+	}
+	return c.To32()
+}
+
+// Winding returns the winding number of the ring around p, which is positive
+// for counterclockwise rings enclosing p.
+func (r Ring) Winding(p Point) int {
+	w := 0
+	for k := range r {
+		a, b := r[k], r[(k+1)%len(r)]
+		if a.Y <= p.Y {
+			if b.Y > p.Y && isLeft(a, b, p) > 0 {
+				w++ // upward crossing with p on the left
+			}
+		} else if b.Y <= p.Y && isLeft(a, b, p) < 0 {
+			w-- // downward crossing with p on the right
+		}
+	}
+	return w
+}
+
+// Contains reports whether p lies inside the ring according to the fill rule.
+// Points exactly on the boundary may be reported either way.
+func (r Ring) Contains(p Point, rule FillRule) bool {
+	return inside(r.Winding(p), rule)
+}
+
+// IsConvex reports whether the ring is a convex polygon. Collinear vertices are allowed,
+// but the ring must turn in a single direction and wind around its interior exactly once.
+func (r Ring) IsConvex() bool {
+	n := len(r)
+	if n < 3 {
+		return false
+	}
+	var sign float64
+	var turn float64
+	for k := range r {
+		a, b, c := r[k].To64(), r[(k+1)%n].To64(), r[(k+2)%n].To64()
+		e1, e2 := b.Sub(a), c.Sub(b)
+		cross := e1.Cross(e2)
+		if cross != 0 {
+			if sign != 0 && (cross > 0) != (sign > 0) {
+				return false
+			}
+			sign = cross
+		}
+		turn += math.Atan2(cross, e1.Dot(e2))
+	}
+	// A star-shaped ring turns in a single direction but winds around more than once.
+	return sign != 0 && math.Abs(math.Abs(turn)-2*math.Pi) < 1e-6
+}
+
+// Bounds returns the axis-aligned bounds of the ring.
+func (r Ring) Bounds() Rect {
+	return boundsOf(r)
+}
+
+// Transform applies the transformation a to every vertex of the ring.
+// A transformation with a negative determinant reverses the winding direction.
+func (r Ring) Transform(a Affine2D) Ring {
+	if r == nil {
+		return nil
+	}
+	out := make(Ring, len(r))
+	a.TransformPoints(out, r)
+	return out
+}
+
+// SignedArea returns the sum of the signed areas of all rings. If the holes are wound
+// opposite to the outer ring, this is the net area of the polygon with the sign of
+// the outer ring.
+func (p Polygon) SignedArea() float32 {
+	sum := p.Outer.signedArea64()
+	for _, h := range p.Holes {
+		sum += h.signedArea64()
+	}
+	return float32(sum)
+}
+
+// Area returns the area of the polygon, that is the area of the outer ring minus the areas
+// of the holes, regardless of their winding direction.
+func (p Polygon) Area() float32 {
+	sum := math.Abs(p.Outer.signedArea64())
+	for _, h := range p.Holes {
+		sum -= math.Abs(h.signedArea64())
+	}
+	return float32(sum)
+}
+
+// Centroid returns the center of mass of the polygon, with the holes removed.
+// For a polygon with zero area, the mean of the outer vertices is returned.
+func (p Polygon) Centroid() Point {
+	c, area := p.Outer.centroid64()
+	area = math.Abs(area)
+	sum := c.Mul(area)
+	for _, h := range p.Holes {
+		hc, ha := h.centroid64()
+		ha = math.Abs(ha)
+		sum = sum.Sub(hc.Mul(ha))
+		area -= ha
+	}
+	if area == 0 {
+		return p.Outer.vertexMean()
+	}
+	return sum.Mul(1 / area).To32()
+}
+
+// Orientation returns the winding direction of the outer ring.
+func (p Polygon) Orientation() Orientation {
+	return p.Outer.Orientation()
+}
+
+// Orient returns a copy of the polygon whose outer ring winds in the direction o and whose holes
+// wind in the opposite direction. Rings that already have the requested direction are shared.
+func (p Polygon) Orient(o Orientation) Polygon {
+	out := Polygon{Outer: orientRing(p.Outer, o)}
+	if p.Holes != nil {
+		out.Holes = make([]Ring, len(p.Holes))
+		for k, h := range p.Holes {
+			out.Holes[k] = orientRing(h, -o)
+		}
+	}
+	return out
+}
+
+// Reverse returns a copy of the polygon with every ring wound in the opposite direction.
+func (p Polygon) Reverse() Polygon {
+	out := Polygon{Outer: p.Outer.Reverse()}
+	if p.Holes != nil {
+		out.Holes = make([]Ring, len(p.Holes))
+		for k, h := range p.Holes {
+			out.Holes[k] = h.Reverse()
+		}
+	}
+	return out
+}
+
+// Perimeter returns the total length of all rings.
+func (p Polygon) Perimeter() float32 {
+	sum := p.Outer.Perimeter()
+	for _, h := range p.Holes {
+		sum += h.Perimeter()
+	}
+	return sum
+}
+
+// Contains reports whether pt lies inside the polygon according to the fill rule.
+// With EvenOdd, holes are excluded regardless of their winding direction; with NonZero,
+// holes are excluded only if they wind opposite to the outer ring.
+// Points exactly on the boundary may be reported either way.
+func (p Polygon) Contains(pt Point, rule FillRule) bool {
+	// Every crossing changes the winding number by one, so its parity
+	// also serves the even-odd rule.
+	w := p.Outer.Winding(pt)
+	for _, h := range p.Holes {
+		w += h.Winding(pt)
+	}
+	return inside(w, rule)
+}
+
+// IsConvex reports whether the polygon is convex, which requires a convex outer ring and no holes.
+func (p Polygon) IsConvex() bool {
+	return len(p.Holes) == 0 && p.Outer.IsConvex()
+}
+
+// Bounds returns the axis-aligned bounds of the polygon.
+func (p Polygon) Bounds() Rect {
+	return p.Outer.Bounds()
+}
+
+// Transform applies the transformation a to every ring of the polygon.
+func (p Polygon) Transform(a Affine2D) Polygon {
+	out := Polygon{Outer: p.Outer.Transform(a)}
+	if p.Holes != nil {
+		out.Holes = make([]Ring, len(p.Holes))
+		for k, h := range p.Holes {
+			out.Holes[k] = h.Transform(a)
+		}
+	}
+	return out
+}
+
+// signedArea64 computes the signed area in double precision. Coordinates are taken
+// relative to the first vertex to reduce cancellation far from the origin.
+func (r Ring) signedArea64() float64 {
+	if len(r) < 3 {
+		return 0
+	}
+	o := r[0].To64()
+	var sum float64
+	for k := 1; k < len(r)-1; k++ {
+		sum += r[k].To64().Sub(o).Cross(r[k+1].To64().Sub(o))
+	}
+	return sum / 2
+}
+
+// centroid64 returns the centroid and the signed area of the ring in double precision.
+func (r Ring) centroid64() (Point64, float64) {
+	if len(r) < 3 {
+		return Point64{}, 0
+	}
+	o := r[0].To64()
+	var cx, cy, area float64
+	for k := 1; k < len(r)-1; k++ {
+		a, b := r[k].To64().Sub(o), r[k+1].To64().Sub(o)
+		cross := a.Cross(b)
+		area += cross
+		cx += (a.X + b.X) * cross
+		cy += (a.Y + b.Y) * cross
+	}
+	if area == 0 {
+		return Point64{}, 0
+	}
+	return Point64{X: cx / (3 * area), Y: cy / (3 * area)}.Add(o), area / 2
+}
+
+// vertexMean returns the mean of the vertices of the ring.
+func (r Ring) vertexMean() Point {
+	if len(r) == 0 {
+		return Point{}
+	}
+	return centroid64(r).To32()
+}
+
+// orientRing returns the ring wound in the direction o, reversing it if necessary.
+func orientRing(r Ring, o Orientation) Ring {
+	if ro := r.Orientation(); ro != Collinear && ro != o {
+		return r.Reverse()
+	}
+	return r
+}
+
+// isLeft returns a positive value if p lies to the left of the directed line from a to b,
+// a negative value if it lies to the right, and zero if the three points are collinear.
+func isLeft(a, b, p Point) float64 {
+	return b.To64().Sub(a.To64()).Cross(p.To64().Sub(a.To64()))
+}
+
+// inside applies the fill rule to a winding number.
+func inside(winding int, rule FillRule) bool {
+	if rule == EvenOdd {
+		return winding%2 != 0
+	}
+	return winding != 0
+}
@@ -0,0 +1,155 @@
+package tochka
+
+import "testing"
+
+// square is a counterclockwise 4x4 square used by the polygon tests.
+var square = Ring{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 0, Y: 4}}
+
+// TestRing_Area tests the signed area, orientation and reversal of a ring.
+func TestRing_Area(t *testing.T) {
+	if got := square.SignedArea(); got != 16 {
+		t.Errorf("SignedArea() = %v; want 16", got)
+	}
+	rev := square.Reverse()
+	if got := rev.SignedArea(); got != -16 {
+		t.Errorf("Reverse().SignedArea() = %v; want -16", got)
+	}
+	if square.Orientation() != CounterClockwise || rev.Orientation() != Clockwise {
+		t.Errorf("Orientation() = %v, %v; want counterclockwise, clockwise", square.Orientation(), rev.Orientation())
+	}
+	if got := (Ring{{X: 0, Y: 0}, {X: 1, Y: 1}, {X: 2, Y: 2}}).Orientation(); got != Collinear {
+		t.Errorf("Orientation() of a degenerate ring = %v; want collinear", got)
+	}
+	if got := square.Perimeter(); got != 16 {
+		t.Errorf("Perimeter() = %v; want 16", got)
+	}
+	// Far from the origin the shoelace formula must not lose the area to cancellation.
+	far := square.Transform(NewAffine2D(1, 0, 1e5, 0, 1, 1e5))
+	if got := far.Area(); got != 16 {
+		t.Errorf("Area() far from the origin = %v; want 16", got)
+	}
+}
+
+// TestRing_Centroid tests the centroid of an L-shaped ring.
+func TestRing_Centroid(t *testing.T) {
+	l := Ring{{X: 0, Y: 0}, {X: 2, Y: 0}, {X: 2, Y: 1}, {X: 1, Y: 1}, {X: 1, Y: 2}, {X: 0, Y: 2}}
+	// The L consists of a 2x1 rectangle centered at (1, 0.5) and a 1x1 square centered at (0.5, 1.5).
+	want := Point{X: (2*1 + 0.5) / 3, Y: (2*0.5 + 1.5) / 3}
+	if got := l.Centroid(); !pointAlmostEqual(got, want, 1e-6) {
+		t.Errorf("Centroid() = %v; want %v", got, want)
+	}
+	if got := l.Reverse().Centroid(); !pointAlmostEqual(got, want, 1e-6) {
+		t.Errorf("Centroid() of the reversed ring = %v; want %v", got, want)
+	}
+	if got := (Ring{{X: 0, Y: 0}, {X: 2, Y: 2}}).Centroid(); got != (Point{X: 1, Y: 1}) {
+		t.Errorf("Centroid() of a degenerate ring = %v; want (1, 1)", got)
+	}
+}
+
+// TestRing_IsConvex tests convex, concave and self-intersecting rings.
+func TestRing_IsConvex(t *testing.T) {
+	tests := []struct {
+		name string
+		r    Ring
+		want bool
+	}{
+		{"square", square, true},
+		{"clockwise square", square.Reverse(), true},
+		{"collinear vertex", Ring{{X: 0, Y: 0}, {X: 2, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 0, Y: 4}}, true},
+		{"concave", Ring{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 2, Y: 1}, {X: 4, Y: 4}, {X: 0, Y: 4}}, false},
+		{"pentagram", Ring{{X: 0, Y: 3}, {X: 2, Y: -3}, {X: -3, Y: 1}, {X: 3, Y: 1}, {X: -2, Y: -3}}, false},
+		{"degenerate", Ring{{X: 0, Y: 0}, {X: 1, Y: 0}}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.r.IsConvex(); got != tt.want {
+			t.Errorf("%s: IsConvex() = %v; want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestPolygon_Holes tests area, centroid and orientation of a polygon with a hole.
+func TestPolygon_Holes(t *testing.T) {
+	hole := Ring{{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 2, Y: 2}, {X: 1, Y: 2}}
+	p := NewPolygon(square, hole)
+	if got := p.Area(); got != 15 {
+		t.Errorf("Area() = %v; want 15", got)
+	}
+	if got := p.SignedArea(); got != 17 {
+		t.Errorf("SignedArea() with equally wound hole = %v; want 17", got)
+	}
+	o := p.Orient(CounterClockwise)
+	if got := o.SignedArea(); got != 15 {
+		t.Errorf("Orient().SignedArea() = %v; want 15", got)
+	}
+	if o.Holes[0].Orientation() != Clockwise {
+		t.Errorf("Orient() left the hole %v", o.Holes[0].Orientation())
+	}
+	if got := p.Orient(Clockwise).Orientation(); got != Clockwise {
+		t.Errorf("Orient(Clockwise).Orientation() = %v", got)
+	}
+	// Removing the unit hole at (1.5, 1.5) from the square centered at (2, 2).
+	want := Point{X: (16*2 - 1.5) / 15, Y: (16*2 - 1.5) / 15}
+	if got := p.Centroid(); !pointAlmostEqual(got, want, 1e-6) {
+		t.Errorf("Centroid() = %v; want %v", got, want)
+	}
+	if got := p.Perimeter(); got != 20 {
+		t.Errorf("Perimeter() = %v; want 20", got)
+	}
+	if p.IsConvex() || !NewPolygon(square).IsConvex() {
+		t.Errorf("IsConvex() failed")
+	}
+}
+
+// TestPolygon_Contains tests the even-odd and non-zero fill rules.
+func TestPolygon_Contains(t *testing.T) {
+	hole := Ring{{X: 1, Y: 1}, {X: 3, Y: 1}, {X: 3, Y: 3}, {X: 1, Y: 3}}
+	same := NewPolygon(square, hole)
+	opposite := NewPolygon(square, hole.Reverse())
+	tests := []struct {
+		name string
+		p    Polygon
+		pt   Point
+		rule FillRule
+		want bool
+	}{
+		{"outside", same, Point{X: 5, Y: 2}, EvenOdd, false},
+		{"inside", same, Point{X: 0.5, Y: 2}, EvenOdd, true},
+		{"hole even-odd", same, Point{X: 2, Y: 2}, EvenOdd, false},
+		{"hole non-zero same winding", same, Point{X: 2, Y: 2}, NonZero, true},
+		{"hole non-zero opposite winding", opposite, Point{X: 2, Y: 2}, NonZero, false},
+		{"hole even-odd opposite winding", opposite, Point{X: 2, Y: 2}, EvenOdd, false},
+	}
+	for _, tt := range tests {
+		if got := tt.p.Contains(tt.pt, tt.rule); got != tt.want {
+			t.Errorf("%s: Contains(%v) = %v; want %v", tt.name, tt.pt, got, tt.want)
+		}
+	}
+
+	// A self-overlapping ring that winds twice around its center.
+	twice := Ring{
+		{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 0, Y: 4},
+		{X: 1, Y: 1}, {X: 3, Y: 1}, {X: 3, Y: 3}, {X: 0, Y: 3}, {X: 0, Y: 1},
+	}
+	if twice.Contains(Point{X: 2, Y: 2}, EvenOdd) || !twice.Contains(Point{X: 2, Y: 2}, NonZero) {
+		t.Errorf("Contains() does not distinguish the fill rules: winding %d", twice.Winding(Point{X: 2, Y: 2}))
+	}
+}
+
+// TestPolygon_Transform tests that transformations apply to every ring.
+func TestPolygon_Transform(t *testing.T) {
+	p := NewPolygon(square, Ring{{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 2, Y: 2}})
+	a := NewAffine2D(2, 0, 1, 0, 3, -1)
+	q := p.Transform(a)
+	if got := q.Area(); got != 6*p.Area() {
+		t.Errorf("Area() after transform = %v; want %v", got, 6*p.Area())
+	}
+	if q.Holes[0][2] != a.Transform(p.Holes[0][2]) {
+		t.Errorf("Transform() did not transform the holes: %v", q.Holes)
+	}
+	if got := p.Transform(NewAffine2D(-1, 0, 0, 0, 1, 0)).Orientation(); got != Clockwise {
+		t.Errorf("reflected Orientation() = %v; want clockwise", got)
+	}
+	if got, want := q.Bounds(), NewRect(1, -1, 9, 11); got != want {
+		t.Errorf("Bounds() = %v; want %v", got, want)
+	}
+}
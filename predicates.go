@@ -0,0 +1,170 @@
+package tochka
+
+import (
+	"math"
+	"math/big"
+)
+
+// Error bounds of the floating-point filters, from Shewchuk, "Adaptive Precision
+// Floating-Point Arithmetic and Fast Robust Geometric Predicates". The machine
+// epsilon is half an ulp of 1 in float64.
+const (
+	epsilon64     = 0x1p-53
+	ccwErrBoundA  = (3 + 16*epsilon64) * epsilon64
+	iccErrBoundA  = (10 + 96*epsilon64) * epsilon64
+	minFilterSize = 0x1p-900
+)
+
+// Orient2D reports on which side of the directed line from a to b the point c lies:
+// CounterClockwise if a, b, c turn left, Clockwise if they turn right, and Collinear if
+// the points lie on one line, assuming a Y axis pointing up.
+//
+// Unlike the sign of a cross product evaluated in floating point, the answer is exact
+// for all finite inputs. The determinant is evaluated in float64 and recomputed with
+// exact arithmetic only when its error bound does not establish the sign, so nearly
+// all calls cost a few multiplications.
+func Orient2D(a, b, c Point) Orientation {
+	return orient2D(float64(a.X), float64(a.Y), float64(b.X), float64(b.Y), float64(c.X), float64(c.Y))
+}
+
+// Orient2D64 is the Point64 counterpart of Orient2D.
+func Orient2D64(a, b, c Point64) Orientation {
+	return orient2D(a.X, a.Y, b.X, b.Y, c.X, c.Y)
+}
+
+// InCircle reports whether d lies inside the circle through a, b and c, which must be
+// in counterclockwise order: it returns 1 if d is inside, -1 if d is outside and 0 if
+// the four points are cocircular. The sign is reversed if a, b, c are clockwise.
+//
+// Like Orient2D, the answer is exact for all finite inputs, with an exact fallback only
+// when the float64 evaluation cannot establish the sign.
+func InCircle(a, b, c, d Point) int {
+	return inCircle(
+		float64(a.X), float64(a.Y), float64(b.X), float64(b.Y),
+		float64(c.X), float64(c.Y), float64(d.X), float64(d.Y),
+	)
+}
+
+// InCircle64 is the Point64 counterpart of InCircle.
+func InCircle64(a, b, c, d Point64) int {
+	return inCircle(a.X, a.Y, b.X, b.Y, c.X, c.Y, d.X, d.Y)
+}
+
+// orient2D implements Orient2D with a floating-point filter and an exact fallback.
+func orient2D(ax, ay, bx, by, cx, cy float64) Orientation {
+	detLeft := (ax - cx) * (by - cy)
+	detRight := (ay - cy) * (bx - cx)
+	det := detLeft - detRight
+	var detSum float64
+	switch {
+	case detLeft > 0 && detRight > 0:
+		detSum = detLeft + detRight
+	case detLeft < 0 && detRight < 0:
+		detSum = -detLeft - detRight
+	default:
+		// The terms have opposite signs or one is zero, so the sign of the difference is
+		// right unless it overflowed or a product underflowed to zero.
+		underflow := detLeft == 0 && ax != cx && by != cy || detRight == 0 && ay != cy && bx != cx
+		if !underflow && !math.IsInf(det, 0) && !math.IsNaN(det) {
+			return Orientation(sign(det))
+		}
+	}
+	if filtered(det, ccwErrBoundA*detSum, detSum) {
+		return Orientation(sign(det))
+	}
+	if !finite(ax, ay, bx, by, cx, cy) {
+		return Collinear
+	}
+	l := exactMul(exactDiff(ax, cx), exactDiff(by, cy))
+	r := exactMul(exactDiff(ay, cy), exactDiff(bx, cx))
+	return Orientation(l.Cmp(r))
+}
+
+// inCircle implements InCircle with a floating-point filter and an exact fallback.
+func inCircle(ax, ay, bx, by, cx, cy, dx, dy float64) int {
+	adx, ady := ax-dx, ay-dy
+	bdx, bdy := bx-dx, by-dy
+	cdx, cdy := cx-dx, cy-dy
+
+	bdxcdy, cdxbdy := bdx*cdy, cdx*bdy
+	alift := adx*adx + ady*ady
+	cdxady, adxcdy := cdx*ady, adx*cdy
+	blift := bdx*bdx + bdy*bdy
+	adxbdy, bdxady := adx*bdy, bdx*ady
+	clift := cdx*cdx + cdy*cdy
+
+	det := alift*(bdxcdy-cdxbdy) + blift*(cdxady-adxcdy) + clift*(adxbdy-bdxady)
+	permanent := (math.Abs(bdxcdy)+math.Abs(cdxbdy))*alift +
+		(math.Abs(cdxady)+math.Abs(adxcdy))*blift +
+		(math.Abs(adxbdy)+math.Abs(bdxady))*clift
+	if filtered(det, iccErrBoundA*permanent, permanent) {
+		return sign(det)
+	}
+	if !finite(ax, ay, bx, by, cx, cy, dx, dy) {
+		return 0
+	}
+
+	eadx, eady := exactDiff(ax, dx), exactDiff(ay, dy)
+	ebdx, ebdy := exactDiff(bx, dx), exactDiff(by, dy)
+	ecdx, ecdy := exactDiff(cx, dx), exactDiff(cy, dy)
+	lift := func(x, y *big.Float) *big.Float {
+		return exactAdd(exactMul(x, x), exactMul(y, y))
+	}
+	det2 := func(x1, y1, x2, y2 *big.Float) *big.Float {
+		return exactSub(exactMul(x1, y2), exactMul(x2, y1))
+	}
+	e := exactMul(lift(eadx, eady), det2(ebdx, ebdy, ecdx, ecdy))
+	e = exactAdd(e, exactMul(lift(ebdx, ebdy), det2(ecdx, ecdy, eadx, eady)))
+	e = exactAdd(e, exactMul(lift(ecdx, ecdy), det2(eadx, eady, ebdx, ebdy)))
+	return e.Sign()
+}
+
+// filtered reports whether the floating-point determinant has a certain sign given its
+// error bound. Sizes so small that rounding may have underflowed are never trusted.
+func filtered(det, errBound, size float64) bool {
+	if math.IsInf(errBound, 0) || math.IsNaN(det) || size < minFilterSize && size != 0 {
+		return false
+	}
+	return det > errBound || -det > errBound
+}
+
+// finite reports whether all values are finite.
+func finite(v ...float64) bool {
+	for _, x := range v {
+		if math.IsInf(x, 0) || math.IsNaN(x) {
+			return false
+		}
+	}
+	return true
+}
+
+// sign returns -1, 0 or 1 according to the sign of x.
+func sign(x float64) int {
+	switch {
+	case x > 0:
+		return 1
+	case x < 0:
+		return -1
+	}
+	return 0
+}
+
+// exactDiff returns a-b without rounding.
+func exactDiff(a, b float64) *big.Float {
+	return exactSub(big.NewFloat(a), big.NewFloat(b))
+}
+
+// exactSub returns x-y without rounding.
+func exactSub(x, y *big.Float) *big.Float {
+	return new(big.Float).SetPrec(big.MaxPrec).Sub(x, y)
+}
+
+// exactAdd returns x+y without rounding.
+func exactAdd(x, y *big.Float) *big.Float {
+	return new(big.Float).SetPrec(big.MaxPrec).Add(x, y)
+}
+
+// exactMul returns x*y without rounding.
+func exactMul(x, y *big.Float) *big.Float {
+	return new(big.Float).SetPrec(big.MaxPrec).Mul(x, y)
+}
@@ -0,0 +1,147 @@
+package tochka
+
+import (
+	"math"
+	"math/big"
+	"math/rand/v2"
+	"testing"
+)
+
+// ratOrient2D returns the exact orientation of a, b, c computed with rationals.
+func ratOrient2D(ax, ay, bx, by, cx, cy float64) Orientation {
+	r := func(v float64) *big.Rat { return new(big.Rat).SetFloat64(v) }
+	sub := func(a, b float64) *big.Rat { return new(big.Rat).Sub(r(a), r(b)) }
+	l := new(big.Rat).Mul(sub(ax, cx), sub(by, cy))
+	m := new(big.Rat).Mul(sub(ay, cy), sub(bx, cx))
+	return Orientation(l.Cmp(m))
+}
+
+// ratInCircle returns the exact in-circle sign of d computed with rationals.
+func ratInCircle(a, b, c, d Point64) int {
+	r := func(v float64) *big.Rat { return new(big.Rat).SetFloat64(v) }
+	rows := make([][3]*big.Rat, 3)
+	for k, p := range []Point64{a, b, c} {
+		x, y := new(big.Rat).Sub(r(p.X), r(d.X)), new(big.Rat).Sub(r(p.Y), r(d.Y))
+		lift := new(big.Rat).Add(new(big.Rat).Mul(x, x), new(big.Rat).Mul(y, y))
+		rows[k] = [3]*big.Rat{x, y, lift}
+	}
+	minor := func(i, j int) *big.Rat {
+		return new(big.Rat).Sub(new(big.Rat).Mul(rows[i][0], rows[j][1]), new(big.Rat).Mul(rows[j][0], rows[i][1]))
+	}
+	det := new(big.Rat).Mul(rows[0][2], minor(1, 2))
+	det.Add(det, new(big.Rat).Mul(rows[1][2], minor(2, 0)))
+	det.Add(det, new(big.Rat).Mul(rows[2][2], minor(0, 1)))
+	return det.Sign()
+}
+
+// TestOrient2D tests simple turns and the nearly collinear grid of Shewchuk's paper,
+// on which the float32 cross product gives wrong signs.
+func TestOrient2D(t *testing.T) {
+	a, b := Point{X: 0, Y: 0}, Point{X: 1, Y: 0}
+	if got := Orient2D(a, b, Point{X: 0.5, Y: 1}); got != CounterClockwise {
+		t.Errorf("Orient2D() of a left turn = %v; want CounterClockwise", got)
+	}
+	if got := Orient2D(a, b, Point{X: 0.5, Y: -1}); got != Clockwise {
+		t.Errorf("Orient2D() of a right turn = %v; want Clockwise", got)
+	}
+	if got := Orient2D(a, b, Point{X: 7, Y: 0}); got != Collinear {
+		t.Errorf("Orient2D() of collinear points = %v; want Collinear", got)
+	}
+
+	ulp := float32(math.Nextafter32(0.5, 1) - 0.5)
+	q, r := Point{X: 12, Y: 12}, Point{X: 24, Y: 24}
+	wrong := 0
+	for i := range 64 {
+		for j := range 64 {
+			p := Point{X: 0.5 + float32(i)*ulp, Y: 0.5 + float32(j)*ulp}
+			want := ratOrient2D(float64(p.X), float64(p.Y), 12, 12, 24, 24)
+			if got := Orient2D(p, q, r); got != want {
+				t.Fatalf("Orient2D(%v, %v, %v) = %v; want %v", p, q, r, got, want)
+			}
+			if naive := sign(float64(q.Sub(p).Cross(r.Sub(p)))); Orientation(naive) != want {
+				wrong++
+			}
+		}
+	}
+	if wrong == 0 {
+		t.Errorf("the float32 cross product was right on the whole grid; the test is not demanding")
+	}
+}
+
+// TestOrient2D64 tests extreme magnitudes where products overflow or underflow.
+func TestOrient2D64(t *testing.T) {
+	tests := [][6]float64{
+		{0, 0, 1e-200, 1e-200, 2e-200, 2.0000000000000001e-200},
+		{1e-300, 0, 0, 1e-300, -1e-300, 0},
+		{1e300, 1e300, -1e300, -1e300, 0, 1e-300},
+		{math.MaxFloat64, 0, -math.MaxFloat64, 0, 0, 1},
+		{0.1, 0.1, 0.3, 0.3, 0.7, 0.7000000000000001},
+	}
+	for _, v := range tests {
+		want := ratOrient2D(v[0], v[1], v[2], v[3], v[4], v[5])
+		got := Orient2D64(Point64{X: v[0], Y: v[1]}, Point64{X: v[2], Y: v[3]}, Point64{X: v[4], Y: v[5]})
+		if got != want {
+			t.Errorf("Orient2D64(%v) = %v; want %v", v, got, want)
+		}
+	}
+	r := rand.New(rand.NewPCG(5, 6))
+	for range 1000 {
+		// Points on a line through the origin, perturbed by a few ulps.
+		s := math.Ldexp(1, r.IntN(200)-100)
+		v := [6]float64{s, 3 * s, 2 * s, 6 * s, 5 * s, 15 * s}
+		for k := range v {
+			for range r.IntN(3) {
+				v[k] = math.Nextafter(v[k], math.Inf(r.IntN(2)*2-1))
+			}
+		}
+		want := ratOrient2D(v[0], v[1], v[2], v[3], v[4], v[5])
+		if got := Orient2D64(Point64{X: v[0], Y: v[1]}, Point64{X: v[2], Y: v[3]}, Point64{X: v[4], Y: v[5]}); got != want {
+			t.Fatalf("Orient2D64(%v) = %v; want %v", v, got, want)
+		}
+	}
+}
+
+// TestInCircle tests points inside, outside and on a circle, including nearly
+// cocircular points that need the exact fallback.
+func TestInCircle(t *testing.T) {
+	a, b, c := Point{X: 0, Y: 0}, Point{X: 1, Y: 0}, Point{X: 0, Y: 1}
+	tests := []struct {
+		d    Point
+		want int
+	}{
+		{Point{X: 0.5, Y: 0.5}, 1},
+		{Point{X: 2, Y: 2}, -1},
+		{Point{X: 1, Y: 1}, 0},
+	}
+	for _, tt := range tests {
+		if got := InCircle(a, b, c, tt.d); got != tt.want {
+			t.Errorf("InCircle(%v) = %d; want %d", tt.d, got, tt.want)
+		}
+		if got := InCircle(a, c, b, tt.d); got != -tt.want {
+			t.Errorf("InCircle() with clockwise points of %v = %d; want %d", tt.d, got, -tt.want)
+		}
+	}
+
+	r := rand.New(rand.NewPCG(7, 8))
+	for range 1000 {
+		// Four points on a circle of radius 100, rounded to float32 and perturbed by an ulp.
+		var p [4]Point
+		for k := range p {
+			s, c := math.Sincos(float64(k)*math.Pi/2 + r.Float64())
+			p[k] = Point{X: float32(300 + 100*c), Y: float32(200 + 100*s)}
+			if r.IntN(2) == 0 {
+				p[k].X = math.Nextafter32(p[k].X, float32(math.Inf(r.IntN(2)*2-1)))
+			}
+		}
+		want := ratInCircle(p[0].To64(), p[1].To64(), p[2].To64(), p[3].To64())
+		if got := InCircle(p[0], p[1], p[2], p[3]); got != want {
+			t.Fatalf("InCircle(%v) = %d; want %d", p, got, want)
+		}
+	}
+	// Cocircular points far from the origin, exactly representable.
+	o := Point64{X: 1e15, Y: -1e15}
+	q := [4]Point64{o.Add(Point64{X: 3, Y: 4}), o.Add(Point64{X: -4, Y: 3}), o.Add(Point64{X: -3, Y: -4}), o.Add(Point64{X: 5, Y: 0})}
+	if got := InCircle64(q[0], q[1], q[2], q[3]); got != 0 {
+		t.Errorf("InCircle64() of cocircular points = %d; want 0", got)
+	}
+}
@@ -0,0 +1,257 @@
+package tochka
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrDegenerateQuad is returned when a quadrilateral has three or more collinear vertices
+// and cannot be mapped by a projective transformation.
+var ErrDegenerateQuad = errors.New("degenerate quadrilateral")
+
+// Projective2D represents a projective transformation (homography) in a 2D coordinate system.
+// It contains the elements of a full 3x3 matrix, which unlike Affine2D can express perspective.
+// Like Affine2D, the diagonal is stored as an offset from the identity, so the zero value
+// is the identity transformation.
+type Projective2D struct {
+	a, b, c float32
+	d, e, f float32
+	g, h, i float32
+}
+
+// NewProjective2D creates a new projective transformation.
+// sx, hx, ox are elements of the first row of the matrix (scaling along X, shear along X, translation along X).
+// hy, sy, oy are elements of the second row of the matrix (shear along Y, scaling along Y, translation along Y).
+// px, py, w are elements of the third row of the matrix (perspective along X and Y, homogeneous scale).
+func NewProjective2D(sx, hx, ox, hy, sy, oy, px, py, w float32) Projective2D {
+	return Projective2D{
+		a: sx - 1, b: hx, c: ox,
+		d: hy, e: sy - 1, f: oy,
+		g: px, h: py, i: w - 1,
+	}
+}
+
+// ToProjective converts the affine transformation to an equivalent projective transformation.
+func (a Affine2D) ToProjective() Projective2D {
+	return Projective2D{
+		a: a.a, b: a.b, c: a.c,
+		d: a.d, e: a.e, f: a.f,
+	}
+}
+
+// ToAffine converts the projective transformation to an affine transformation.
+// The conversion succeeds only if the transformation has no perspective component,
+// otherwise ok is false.
+func (p Projective2D) ToAffine() (a Affine2D, ok bool) {
+	if p.g != 0 || p.h != 0 || p.i+1 == 0 {
+		return Affine2D{}, false
+	}
+	m := p.mat3()
+	m.scale(1 / m[8])
+	sx, hx, ox, hy, sy, oy := m[0], m[1], m[2], m[3], m[4], m[5]
+	return NewAffine2D64(sx, hx, ox, hy, sy, oy).To32(), true
+}
+
+// QuadToQuad returns the projective transformation that maps the four vertices of src
+// onto the corresponding vertices of dst. The vertices of each quadrilateral must be given
+// in order along its boundary. Returns ErrDegenerateQuad if either quadrilateral is degenerate.
+func QuadToQuad(src, dst [4]Point) (Projective2D, error) {
+	s, ok := squareToQuad(src)
+	if !ok {
+		return Projective2D{}, ErrDegenerateQuad
+	}
+	d, ok := squareToQuad(dst)
+	if !ok {
+		return Projective2D{}, ErrDegenerateQuad
+	}
+	inv, ok := s.invert()
+	if !ok {
+		return Projective2D{}, ErrDegenerateQuad
+	}
+	m := d.mul(inv)
+	if m[8] == 0 {
+		return Projective2D{}, ErrDegenerateQuad
+	}
+	m.scale(1 / m[8])
+	return m.projective(), nil
+}
+
+// Mul multiplies the current matrix by another matrix.
+// B is the other transformation matrix, which is applied first.
+// Returns the result of the multiplication.
+func (A Projective2D) Mul(B Projective2D) Projective2D {
+	return A.mat3().mul(B.mat3()).projective()
+}
+
+// Invert computes the inverse transformation for the current matrix.
+// If the matrix is singular, the identity transformation is returned;
+// use TryInvert to detect that case.
+func (p Projective2D) Invert() Projective2D {
+	inv, err := p.TryInvert(DefaultTolerance)
+	if err != nil {
+		return Projective2D{} // matrix is singular
+	}
+	return inv
+}
+
+// TryInvert computes the inverse transformation for the current matrix.
+// Since a projective matrix is only defined up to scale, the determinant is compared with
+// the cube of the largest element; tolerance is the smallest accepted ratio of the two.
+// Returns ErrSingularMatrix if the matrix cannot be inverted reliably.
+// The computation is carried out in double precision.
+func (p Projective2D) TryInvert(tolerance float32) (Projective2D, error) {
+	m := p.mat3()
+	var norm float64
+	for _, v := range m {
+		norm = math.Max(norm, math.Abs(v))
+	}
+	det := m.determinant()
+	if det == 0 || math.IsNaN(det) || math.Abs(det) < float64(tolerance)*norm*norm*norm {
+		return Projective2D{}, ErrSingularMatrix
+	}
+	inv, _ := m.invert()
+	return inv.projective(), nil
+}
+
+// Determinant returns the determinant of the transformation matrix.
+func (p Projective2D) Determinant() float32 {
+	return float32(p.mat3().determinant())
+}
+
+// Transform applies the current transformation to a given point.
+// p is the point to which the transformation is applied.
+// Points mapped to infinity yield infinite or NaN coordinates.
+func (p Projective2D) Transform(pt Point) Point {
+	x, y := float64(pt.X), float64(pt.Y)
+	m := p.mat3()
+	w := m[6]*x + m[7]*y + m[8]
+	return Point{
+		X: float32((m[0]*x + m[1]*y + m[2]) / w),
+		Y: float32((m[3]*x + m[4]*y + m[5]) / w),
+	}
+}
+
+// Elems returns the elements of the transformation matrix.
+func (p Projective2D) Elems() (sx, hx, ox, hy, sy, oy, px, py, w float32) {
+	return p.a + 1, p.b, p.c, p.d, p.e + 1, p.f, p.g, p.h, p.i + 1
+}
+
+// String returns the string representation of the transformation matrix.
+// Format: "[[sx hx ox] [hy sy oy] [px py w]]".
+func (p Projective2D) String() string {
+	sx, hx, ox, hy, sy, oy, px, py, w := p.Elems()
+	var b strings.Builder
+	b.WriteString("[")
+	for r, row := range [3][3]float32{{sx, hx, ox}, {hy, sy, oy}, {px, py, w}} {
+		if r > 0 {
+			b.WriteString(" ")
+		}
+		b.WriteString("[")
+		for c, v := range row {
+			if c > 0 {
+				b.WriteString(" ")
+			}
+			b.WriteString(strconv.FormatFloat(float64(v), 'g', 6, 32))
+		}
+		b.WriteString("]")
+	}
+	b.WriteString("]")
+	return b.String()
+}
+
+// mat3 is a row-major 3x3 matrix in double precision used for intermediate computations.
+type mat3 [9]float64
+
+// mat3 returns the full matrix of the transformation.
+func (p Projective2D) mat3() mat3 {
+	return mat3{
+		float64(p.a) + 1, float64(p.b), float64(p.c),
+		float64(p.d), float64(p.e) + 1, float64(p.f),
+		float64(p.g), float64(p.h), float64(p.i) + 1,
+	}
+}
+
+// projective converts the matrix back to a projective transformation.
+func (m mat3) projective() Projective2D {
+	return Projective2D{
+		a: float32(m[0] - 1), b: float32(m[1]), c: float32(m[2]),
+		d: float32(m[3]), e: float32(m[4] - 1), f: float32(m[5]),
+		g: float32(m[6]), h: float32(m[7]), i: float32(m[8] - 1),
+	}
+}
+
+// mul returns the product m * n.
+func (m mat3) mul(n mat3) (r mat3) {
+	for row := 0; row < 3; row++ {
+		for col := 0; col < 3; col++ {
+			r[row*3+col] = m[row*3]*n[col] + m[row*3+1]*n[3+col] + m[row*3+2]*n[6+col]
+		}
+	}
+	return r
+}
+
+// scale multiplies every element of the matrix by s.
+func (m *mat3) scale(s float64) {
+	for k := range m {
+		m[k] *= s
+	}
+}
+
+// determinant returns the determinant of the matrix.
+func (m mat3) determinant() float64 {
+	return m[0]*(m[4]*m[8]-m[5]*m[7]) -
+		m[1]*(m[3]*m[8]-m[5]*m[6]) +
+		m[2]*(m[3]*m[7]-m[4]*m[6])
+}
+
+// invert returns the inverse of the matrix computed from its adjugate.
+// ok is false if the matrix is singular.
+func (m mat3) invert() (r mat3, ok bool) {
+	det := m.determinant()
+	if det == 0 {
+		return mat3{}, false
+	}
+	r = mat3{
+		m[4]*m[8] - m[5]*m[7], m[2]*m[7] - m[1]*m[8], m[1]*m[5] - m[2]*m[4],
+		m[5]*m[6] - m[3]*m[8], m[0]*m[8] - m[2]*m[6], m[2]*m[3] - m[0]*m[5],
+		m[3]*m[7] - m[4]*m[6], m[1]*m[6] - m[0]*m[7], m[0]*m[4] - m[1]*m[3],
+	}
+	r.scale(1 / det)
+	return r, true
+}
+
+// squareToQuad returns the matrix mapping the unit square (0,0), (1,0), (1,1), (0,1)
+// onto the quadrilateral q. ok is false if the quadrilateral is degenerate.
+func squareToQuad(q [4]Point) (m mat3, ok bool) {
+	x0, y0 := float64(q[0].X), float64(q[0].Y)
+	x1, y1 := float64(q[1].X), float64(q[1].Y)
+	x2, y2 := float64(q[2].X), float64(q[2].Y)
+	x3, y3 := float64(q[3].X), float64(q[3].Y)
+
+	dx3, dy3 := x0-x1+x2-x3, y0-y1+y2-y3
+	if dx3 == 0 && dy3 == 0 {
+		// The quadrilateral is a parallelogram, the mapping is affine.
+		m = mat3{
+			x1 - x0, x3 - x0, x0,
+			y1 - y0, y3 - y0, y0,
+			0, 0, 1,
+		}
+		return m, m.determinant() != 0
+	}
+	dx1, dy1 := x1-x2, y1-y2
+	dx2, dy2 := x3-x2, y3-y2
+	den := dx1*dy2 - dx2*dy1
+	if den == 0 {
+		return mat3{}, false
+	}
+	g := (dx3*dy2 - dx2*dy3) / den
+	h := (dx1*dy3 - dx3*dy1) / den
+	m = mat3{
+		x1 - x0 + g*x1, x3 - x0 + h*x3, x0,
+		y1 - y0 + g*y1, y3 - y0 + h*y3, y0,
+		g, h, 1,
+	}
+	return m, m.determinant() != 0
+}
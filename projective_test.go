@@ -0,0 +1,123 @@
+package tochka
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestNewProjective2D tests that the constructor creates the identity for the identity elements.
+func TestNewProjective2D(t *testing.T) {
+	p := NewProjective2D(1, 0, 0, 0, 1, 0, 0, 0, 1)
+	if p != (Projective2D{}) {
+		t.Errorf("Expected identity, got %v", p)
+	}
+}
+
+// TestProjective2D_FromAffine tests that an affine transformation keeps its behaviour.
+func TestProjective2D_FromAffine(t *testing.T) {
+	a := NewAffine2D(2, 0.5, 3, -0.25, 1.5, -1)
+	p := a.ToProjective()
+	pt := Point{X: 4, Y: -7}
+	if got, want := p.Transform(pt), a.Transform(pt); !pointAlmostEqual(got, want, 1e-5) {
+		t.Errorf("Transform() = %v; want %v", got, want)
+	}
+	back, ok := p.ToAffine()
+	if !ok || back != a {
+		t.Errorf("ToAffine() = %v, %v; want %v, true", back, ok, a)
+	}
+	if _, ok := NewProjective2D(1, 0, 0, 0, 1, 0, 0.1, 0, 1).ToAffine(); ok {
+		t.Errorf("ToAffine() succeeded for a perspective transformation")
+	}
+}
+
+// TestProjective2D_Transform tests the perspective division.
+func TestProjective2D_Transform(t *testing.T) {
+	p := NewProjective2D(1, 0, 0, 0, 1, 0, 1, 0, 1)
+	if got, want := p.Transform(Point{X: 1, Y: 4}), (Point{X: 0.5, Y: 2}); got != want {
+		t.Errorf("Transform() = %v; want %v", got, want)
+	}
+}
+
+// TestProjective2D_Mul tests that multiplication composes transformations.
+func TestProjective2D_Mul(t *testing.T) {
+	a := NewProjective2D(2, 0, 1, 0, 1, 0, 0.1, 0, 1)
+	b := NewProjective2D(1, 0.5, 0, 0, 1, 2, 0, 0.2, 1)
+	pt := Point{X: 1.5, Y: -0.5}
+	if got, want := a.Mul(b).Transform(pt), a.Transform(b.Transform(pt)); !pointAlmostEqual(got, want, 1e-5) {
+		t.Errorf("Mul().Transform() = %v; want %v", got, want)
+	}
+	aff := NewAffine2D(4, 0, 3, 0, 4, 3).Mul(NewAffine2D(2, 0, 2, 0, 2, 2))
+	got := NewAffine2D(4, 0, 3, 0, 4, 3).ToProjective().Mul(NewAffine2D(2, 0, 2, 0, 2, 2).ToProjective())
+	if got != aff.ToProjective() {
+		t.Errorf("Mul() = %v; want %v", got, aff.ToProjective())
+	}
+}
+
+// TestProjective2D_Invert tests inversion and singular matrix detection.
+func TestProjective2D_Invert(t *testing.T) {
+	p := NewProjective2D(2, 0.3, 1, -0.2, 1.5, 4, 0.01, -0.02, 1)
+	pt := Point{X: 3, Y: 5}
+	if got := p.Invert().Transform(p.Transform(pt)); !pointAlmostEqual(got, pt, 1e-4) {
+		t.Errorf("Invert round trip = %v; want %v", got, pt)
+	}
+	singular := NewProjective2D(1, 2, 3, 2, 4, 6, 0, 0, 1)
+	if _, err := singular.TryInvert(DefaultTolerance); !errors.Is(err, ErrSingularMatrix) {
+		t.Errorf("TryInvert() error = %v; want %v", err, ErrSingularMatrix)
+	}
+	if inv := singular.Invert(); inv != (Projective2D{}) {
+		t.Errorf("Invert() = %v; want identity for a singular matrix", inv)
+	}
+	if det := NewProjective2D(2, 0, 0, 0, 3, 0, 0, 0, 4).Determinant(); det != 24 {
+		t.Errorf("Determinant() = %v; want 24", det)
+	}
+}
+
+// TestQuadToQuad tests that the four correspondences are honoured.
+func TestQuadToQuad(t *testing.T) {
+	src := [4]Point{{X: 0, Y: 0}, {X: 100, Y: 0}, {X: 100, Y: 50}, {X: 0, Y: 50}}
+	dst := [4]Point{{X: 10, Y: 12}, {X: 95, Y: 3}, {X: 120, Y: 70}, {X: -5, Y: 40}}
+	p, err := QuadToQuad(src, dst)
+	if err != nil {
+		t.Fatalf("QuadToQuad() returned an error: %v", err)
+	}
+	for k := range src {
+		if got := p.Transform(src[k]); !pointAlmostEqual(got, dst[k], 1e-3) {
+			t.Errorf("Transform(%v) = %v; want %v", src[k], got, dst[k])
+		}
+	}
+
+	// A parallelogram to parallelogram mapping is affine.
+	dst = [4]Point{{X: 1, Y: 1}, {X: 201, Y: 1}, {X: 211, Y: 101}, {X: 11, Y: 101}}
+	p, err = QuadToQuad(src, dst)
+	if err != nil {
+		t.Fatalf("QuadToQuad() returned an error: %v", err)
+	}
+	if _, ok := p.ToAffine(); !ok {
+		t.Errorf("QuadToQuad() = %v; want an affine transformation", p)
+	}
+}
+
+// TestQuadToQuad_Degenerate tests that collinear vertices are rejected.
+func TestQuadToQuad_Degenerate(t *testing.T) {
+	src := [4]Point{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}, {X: 0, Y: 1}}
+	dst := [4]Point{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}, {X: 0, Y: 1}}
+	if _, err := QuadToQuad(src, dst); !errors.Is(err, ErrDegenerateQuad) {
+		t.Errorf("QuadToQuad() error = %v; want %v", err, ErrDegenerateQuad)
+	}
+	if _, err := QuadToQuad(dst, src); !errors.Is(err, ErrDegenerateQuad) {
+		t.Errorf("QuadToQuad() error = %v; want %v", err, ErrDegenerateQuad)
+	}
+}
+
+// TestProjective2D_String tests the string representation of the matrix.
+func TestProjective2D_String(t *testing.T) {
+	p := NewProjective2D(1, 0, 2, 0, 1, 3, 0.5, 0, 1)
+	if got, want := p.String(), "[[1 0 2] [0 1 3] [0.5 0 1]]"; got != want {
+		t.Errorf("String() = %s; want %s", got, want)
+	}
+}
+
+// pointAlmostEqual compares the coordinates of two points within a given precision.
+func pointAlmostEqual(a, b Point, epsilon float32) bool {
+	return almostEqual(a.X, b.X, epsilon) && almostEqual(a.Y, b.Y, epsilon)
+}
@@ -0,0 +1,176 @@
+package tochka
+
+import (
+	"errors"
+	"math/rand/v2"
+	"slices"
+)
+
+// DefaultRANSACIterations is the number of iterations FitRANSAC runs when none is specified.
+const DefaultRANSACIterations = 1000
+
+var (
+	// ErrInvalidThreshold is returned when the RANSAC inlier threshold is not positive.
+	ErrInvalidThreshold = errors.New("inlier threshold must be positive")
+	// ErrNoConsensus is returned when RANSAC finds no model supported by enough inliers.
+	ErrNoConsensus = errors.New("no consensus model found")
+	// ErrInvalidModel is returned when the RANSAC model is not one of the FitModel constants.
+	ErrInvalidModel = errors.New("unknown fit model")
+)
+
+// FitModel selects the kind of transformation estimated by FitRANSAC.
+type FitModel int
+
+const (
+	// ModelAffine estimates a general affine transformation, see FitAffine.
+	ModelAffine FitModel = iota
+	// ModelSimilarity estimates rotation, uniform scale and translation, see FitSimilarity.
+	ModelSimilarity
+	// ModelRigid estimates rotation and translation, see FitRigid.
+	ModelRigid
+)
+
+// RANSACOptions configures FitRANSAC.
+type RANSACOptions struct {
+	// Model is the kind of transformation to estimate.
+	Model FitModel
+	// Threshold is the largest distance between a transformed source point and its
+	// destination for the correspondence to count as an inlier. It must be positive.
+	Threshold float32
+	// Iterations is the number of random samples to evaluate.
+	// If zero or negative, DefaultRANSACIterations is used.
+	Iterations int
+	// Seed initializes the random number generator, so that runs are reproducible.
+	Seed uint64
+}
+
+// RANSACResult holds the outcome of FitRANSAC.
+type RANSACResult struct {
+	// Transform is the estimated transformation, refined by least squares on the inliers.
+	Transform Affine2D
+	// Inliers reports for every correspondence whether it is an inlier of Transform.
+	Inliers []bool
+	// Score is the number of inliers.
+	Score int
+	// Residuals holds the residual statistics of Transform over the inliers.
+	Residuals Residuals
+}
+
+// FitRANSAC robustly estimates the transformation mapping src onto dst in the presence of outliers.
+// It repeatedly fits the model to a minimal random sample of correspondences, keeps the model with
+// the most inliers (breaking ties by the smaller inlier error) and refines it by least squares on
+// its inliers. Returns ErrNoConsensus if no sample produced a model with enough inliers.
+func FitRANSAC(src, dst []Point, opts RANSACOptions) (RANSACResult, error) {
+	if len(src) != len(dst) {
+		return RANSACResult{}, ErrLengthMismatch
+	}
+	if opts.Threshold <= 0 {
+		return RANSACResult{}, ErrInvalidThreshold
+	}
+	fit, size := fitAffine, 3
+	switch opts.Model {
+	case ModelAffine:
+	case ModelSimilarity:
+		fit, size = func(src, dst []Point) (Affine2D64, error) { return fitProcrustes(src, dst, true) }, 2
+	case ModelRigid:
+		fit, size = func(src, dst []Point) (Affine2D64, error) { return fitProcrustes(src, dst, false) }, 2
+	default:
+		return RANSACResult{}, ErrInvalidModel
+	}
+	if len(src) < size {
+		return RANSACResult{}, ErrTooFewPoints
+	}
+	iterations := opts.Iterations
+	if iterations <= 0 {
+		iterations = DefaultRANSACIterations
+	}
+	rng := rand.New(rand.NewPCG(opts.Seed, opts.Seed))
+	threshold := float64(opts.Threshold)
+
+	var (
+		best      Affine2D64
+		bestScore int
+		bestError float64
+		found     bool
+		sampleSrc = make([]Point, size)
+		sampleDst = make([]Point, size)
+		indices   = make([]int, size)
+	)
+	for it := 0; it < iterations; it++ {
+		sampleIndices(rng, len(src), indices)
+		for k, idx := range indices {
+			sampleSrc[k], sampleDst[k] = src[idx], dst[idx]
+		}
+		m, err := fit(sampleSrc, sampleDst)
+		if err != nil {
+			continue
+		}
+		score, sumErr := consensus(m, src, dst, threshold, nil)
+		if score > bestScore || (score == bestScore && sumErr < bestError) {
+			best, bestScore, bestError, found = m, score, sumErr, true
+		}
+	}
+	if !found || bestScore < size {
+		return RANSACResult{}, ErrNoConsensus
+	}
+
+	// Refine the best model on its inliers and keep the refinement only if it
+	// does not lose support.
+	inliers := make([]bool, len(src))
+	consensus(best, src, dst, threshold, inliers)
+	inSrc, inDst := selectInliers(src, dst, inliers)
+	if refined, err := fit(inSrc, inDst); err == nil {
+		refinedInliers := make([]bool, len(src))
+		if score, _ := consensus(refined, src, dst, threshold, refinedInliers); score >= bestScore {
+			best, bestScore, inliers = refined, score, refinedInliers
+			inSrc, inDst = selectInliers(src, dst, inliers)
+		}
+	}
+	return RANSACResult{
+		Transform: best.To32(),
+		Inliers:   inliers,
+		Score:     bestScore,
+		Residuals: residuals(best, inSrc, inDst),
+	}, nil
+}
+
+// sampleIndices fills indices with distinct random indices in [0, n).
+func sampleIndices(rng *rand.Rand, n int, indices []int) {
+	for k := 0; k < len(indices); {
+		idx := rng.IntN(n)
+		if slices.Contains(indices[:k], idx) {
+			continue
+		}
+		indices[k] = idx
+		k++
+	}
+}
+
+// consensus counts the correspondences that m maps within threshold and returns the count
+// together with the sum of their distances. If inliers is not nil, it records which
+// correspondences are inliers.
+func consensus(m Affine2D64, src, dst []Point, threshold float64, inliers []bool) (score int, sumErr float64) {
+	for k := range src {
+		dist := m.Transform(src[k].To64()).Distance(dst[k].To64())
+		ok := dist <= threshold
+		if ok {
+			score++
+			sumErr += dist
+		}
+		if inliers != nil {
+			inliers[k] = ok
+		}
+	}
+	return score, sumErr
+}
+
+// selectInliers returns the correspondences marked as inliers.
+func selectInliers(src, dst []Point, inliers []bool) (inSrc, inDst []Point) {
+	for k, ok := range inliers {
+		if ok {
+			inSrc = append(inSrc, src[k])
+			inDst = append(inDst, dst[k])
+		}
+	}
+	return inSrc, inDst
+}
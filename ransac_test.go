@@ -0,0 +1,99 @@
+package tochka
+
+import (
+	"errors"
+	"math/rand/v2"
+	"testing"
+)
+
+// ransacData returns correspondences generated by want, where every outlierStep-th
+// destination is replaced by a far away point.
+func ransacData(want Affine2D, n, outlierStep int) (src, dst []Point, outliers []bool) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	src = make([]Point, n)
+	dst = make([]Point, n)
+	outliers = make([]bool, n)
+	for k := range src {
+		src[k] = Point{X: rng.Float32() * 100, Y: rng.Float32() * 100}
+		dst[k] = want.Transform(src[k])
+		if k%outlierStep == 0 {
+			dst[k] = dst[k].Add(Point{X: 50 + rng.Float32()*50, Y: -40})
+			outliers[k] = true
+		}
+	}
+	return src, dst, outliers
+}
+
+// TestFitRANSAC tests that every model is recovered despite outliers and that the inlier mask is exact.
+func TestFitRANSAC(t *testing.T) {
+	tests := []struct {
+		name  string
+		model FitModel
+		want  Affine2D
+	}{
+		{"affine", ModelAffine, NewAffine2D(1.2, 0.3, 5, -0.1, 0.9, -7)},
+		{"similarity", ModelSimilarity, Affine2D{}.Scale(Point{}, Point{X: 1.5, Y: 1.5}).Rotate(Point{}, 0.4).Offset(Point{X: 3, Y: 4})},
+		{"rigid", ModelRigid, Affine2D{}.Rotate(Point{}, -0.8).Offset(Point{X: -10, Y: 2})},
+	}
+	for _, tt := range tests {
+		src, dst, outliers := ransacData(tt.want, 60, 3)
+		res, err := FitRANSAC(src, dst, RANSACOptions{Model: tt.model, Threshold: 0.01, Iterations: 200, Seed: 42})
+		if err != nil {
+			t.Fatalf("%s: FitRANSAC() returned an error: %v", tt.name, err)
+		}
+		if !affineAlmostEqual(res.Transform, tt.want, 1e-3) {
+			t.Errorf("%s: FitRANSAC() = %v; want %v", tt.name, res.Transform, tt.want)
+		}
+		if res.Score != 40 {
+			t.Errorf("%s: Score = %d; want 40", tt.name, res.Score)
+		}
+		for k := range outliers {
+			if res.Inliers[k] == outliers[k] {
+				t.Errorf("%s: Inliers[%d] = %v; want %v", tt.name, k, res.Inliers[k], !outliers[k])
+			}
+		}
+		if res.Residuals.Max > 0.01 {
+			t.Errorf("%s: Residuals = %+v; want within threshold", tt.name, res.Residuals)
+		}
+	}
+}
+
+// TestFitRANSAC_Deterministic tests that the same seed produces the same result.
+func TestFitRANSAC_Deterministic(t *testing.T) {
+	src, dst, _ := ransacData(NewAffine2D(1, 0.2, 1, 0, 1, 1), 30, 2)
+	for k := range dst {
+		dst[k] = dst[k].Add(Point{X: float32(k%5) * 0.01})
+	}
+	opts := RANSACOptions{Threshold: 0.03, Iterations: 20, Seed: 7}
+	a, errA := FitRANSAC(src, dst, opts)
+	b, errB := FitRANSAC(src, dst, opts)
+	if errA != nil || errB != nil {
+		t.Fatalf("FitRANSAC() returned errors: %v, %v", errA, errB)
+	}
+	if a.Transform != b.Transform || a.Score != b.Score {
+		t.Errorf("FitRANSAC() is not deterministic: %v (%d) vs %v (%d)", a.Transform, a.Score, b.Transform, b.Score)
+	}
+}
+
+// TestFitRANSAC_Errors tests the validation of the inputs.
+func TestFitRANSAC_Errors(t *testing.T) {
+	src := []Point{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 1}}
+	collinear := []Point{{X: 0, Y: 0}, {X: 1, Y: 1}, {X: 2, Y: 2}}
+	tests := []struct {
+		name     string
+		src, dst []Point
+		opts     RANSACOptions
+		want     error
+	}{
+		{"mismatch", src, src[:2], RANSACOptions{Threshold: 1}, ErrLengthMismatch},
+		{"threshold", src, src, RANSACOptions{}, ErrInvalidThreshold},
+		{"model", src, src, RANSACOptions{Model: FitModel(99), Threshold: 1}, ErrInvalidModel},
+		{"too few", src[:2], src[:2], RANSACOptions{Threshold: 1}, ErrTooFewPoints},
+		{"no consensus", collinear, collinear, RANSACOptions{Threshold: 1}, ErrNoConsensus},
+	}
+	for _, tt := range tests {
+		if _, err := FitRANSAC(tt.src, tt.dst, tt.opts); !errors.Is(err, tt.want) {
+			t.Errorf("%s: error = %v; want %v", tt.name, err, tt.want)
+		}
+	}
+}
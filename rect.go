@@ -0,0 +1,203 @@
+package tochka
+
+import (
+	"image"
+	"math"
+)
+
+// Rect represents an axis-aligned rectangle with float32 coordinates.
+// It contains the points with Min.X <= X < Max.X and Min.Y <= Y < Max.Y, mirroring image.Rectangle.
+// A rectangle is well-formed if Min.X <= Max.X and Min.Y <= Max.Y.
+type Rect struct {
+	Min, Max Point
+}
+
+// NewRect creates a rectangle with the specified corners. The corners are swapped
+// if necessary so that the rectangle is well-formed.
+func NewRect(x0, y0, x1, y1 float32) Rect {
+	return Rect{Min: Point{X: x0, Y: y0}, Max: Point{X: x1, Y: y1}}.Canon()
+}
+
+// RectFromImage converts an integer image.Rectangle to a Rect.
+func RectFromImage(r image.Rectangle) Rect {
+	return Rect{
+		Min: Point{X: float32(r.Min.X), Y: float32(r.Min.Y)},
+		Max: Point{X: float32(r.Max.X), Y: float32(r.Max.Y)},
+	}
+}
+
+// RoundOut returns the smallest image.Rectangle that contains the rectangle.
+func (r Rect) RoundOut() image.Rectangle {
+	return image.Rectangle{
+		Min: image.Point{X: int(math.Floor(float64(r.Min.X))), Y: int(math.Floor(float64(r.Min.Y)))},
+		Max: image.Point{X: int(math.Ceil(float64(r.Max.X))), Y: int(math.Ceil(float64(r.Max.Y)))},
+	}
+}
+
+// RoundIn returns the largest image.Rectangle that is contained in the rectangle.
+// The result is empty if the rectangle does not contain a whole pixel.
+func (r Rect) RoundIn() image.Rectangle {
+	ir := image.Rectangle{
+		Min: image.Point{X: int(math.Ceil(float64(r.Min.X))), Y: int(math.Ceil(float64(r.Min.Y)))},
+		Max: image.Point{X: int(math.Floor(float64(r.Max.X))), Y: int(math.Floor(float64(r.Max.Y)))},
+	}
+	if ir.Empty() {
+		return image.Rectangle{}
+	}
+	return ir
+}
+
+// Dx returns the width of the rectangle.
+func (r Rect) Dx() float32 {
+	return r.Max.X - r.Min.X
+}
+
+// Dy returns the height of the rectangle.
+func (r Rect) Dy() float32 {
+	return r.Max.Y - r.Min.Y
+}
+
+// Size returns the width and height of the rectangle.
+func (r Rect) Size() Point {
+	return r.Max.Sub(r.Min)
+}
+
+// Center returns the center point of the rectangle.
+func (r Rect) Center() Point {
+	return Point{X: (r.Min.X + r.Max.X) / 2, Y: (r.Min.Y + r.Max.Y) / 2}
+}
+
+// Add returns the rectangle translated by p.
+func (r Rect) Add(p Point) Rect {
+	return Rect{Min: r.Min.Add(p), Max: r.Max.Add(p)}
+}
+
+// Sub returns the rectangle translated by -p.
+func (r Rect) Sub(p Point) Rect {
+	return Rect{Min: r.Min.Sub(p), Max: r.Max.Sub(p)}
+}
+
+// Inset returns the rectangle inset by n, which may be negative. If either of the
+// rectangle's dimensions is less than 2*n, the center of that dimension is used instead.
+func (r Rect) Inset(n float32) Rect {
+	if r.Dx() < 2*n {
+		r.Min.X = (r.Min.X + r.Max.X) / 2
+		r.Max.X = r.Min.X
+	} else {
+		r.Min.X += n
+		r.Max.X -= n
+	}
+	if r.Dy() < 2*n {
+		r.Min.Y = (r.Min.Y + r.Max.Y) / 2
+		r.Max.Y = r.Min.Y
+	} else {
+		r.Min.Y += n
+		r.Max.Y -= n
+	}
+	return r
+}
+
+// Intersect returns the largest rectangle contained by both r and s.
+// If the two rectangles do not overlap, the zero rectangle is returned.
+func (r Rect) Intersect(s Rect) Rect {
+	r.Min.X = max(r.Min.X, s.Min.X)
+	r.Min.Y = max(r.Min.Y, s.Min.Y)
+	r.Max.X = min(r.Max.X, s.Max.X)
+	r.Max.Y = min(r.Max.Y, s.Max.Y)
+	if r.Empty() {
+		return Rect{}
+	}
+	return r
+}
+
+// Union returns the smallest rectangle that contains both r and s.
+// Empty rectangles are ignored.
+func (r Rect) Union(s Rect) Rect {
+	if r.Empty() {
+		return s
+	}
+	if s.Empty() {
+		return r
+	}
+	r.Min.X = min(r.Min.X, s.Min.X)
+	r.Min.Y = min(r.Min.Y, s.Min.Y)
+	r.Max.X = max(r.Max.X, s.Max.X)
+	r.Max.Y = max(r.Max.Y, s.Max.Y)
+	return r
+}
+
+// Empty reports whether the rectangle contains no points.
+func (r Rect) Empty() bool {
+	return r.Min.X >= r.Max.X || r.Min.Y >= r.Max.Y
+}
+
+// Eq reports whether r and s contain the same set of points. All empty rectangles are considered equal.
+func (r Rect) Eq(s Rect) bool {
+	return r == s || r.Empty() && s.Empty()
+}
+
+// Overlaps reports whether r and s have a non-empty intersection.
+func (r Rect) Overlaps(s Rect) bool {
+	return !r.Empty() && !s.Empty() &&
+		r.Min.X < s.Max.X && s.Min.X < r.Max.X &&
+		r.Min.Y < s.Max.Y && s.Min.Y < r.Max.Y
+}
+
+// In reports whether every point in r is in s.
+func (r Rect) In(s Rect) bool {
+	if r.Empty() {
+		return true
+	}
+	return s.Min.X <= r.Min.X && r.Max.X <= s.Max.X &&
+		s.Min.Y <= r.Min.Y && r.Max.Y <= s.Max.Y
+}
+
+// Contains reports whether the rectangle contains the point p.
+func (r Rect) Contains(p Point) bool {
+	return r.Min.X <= p.X && p.X < r.Max.X &&
+		r.Min.Y <= p.Y && p.Y < r.Max.Y
+}
+
+// Canon returns the canonical version of the rectangle, whose corners are swapped
+// if necessary so that it is well-formed.
+func (r Rect) Canon() Rect {
+	if r.Max.X < r.Min.X {
+		r.Min.X, r.Max.X = r.Max.X, r.Min.X
+	}
+	if r.Max.Y < r.Min.Y {
+		r.Min.Y, r.Max.Y = r.Max.Y, r.Min.Y
+	}
+	return r
+}
+
+// String returns a string representation of the rectangle in the format "(X, Y)-(X, Y)".
+func (r Rect) String() string {
+	return r.Min.String() + "-" + r.Max.String()
+}
+
+// TransformRect applies the current transformation to the corners of r and returns
+// the axis-aligned bounds of the result.
+func (a Affine2D) TransformRect(r Rect) Rect {
+	corners := [4]Point{
+		a.Transform(r.Min),
+		a.Transform(Point{X: r.Max.X, Y: r.Min.Y}),
+		a.Transform(r.Max),
+		a.Transform(Point{X: r.Min.X, Y: r.Max.Y}),
+	}
+	return boundsOf(corners[:])
+}
+
+// boundsOf returns the smallest well-formed rectangle whose closure contains all points.
+func boundsOf(points []Point) Rect {
+	if len(points) == 0 {
+		return Rect{}
+	}
+	b := Rect{Min: points[0], Max: points[0]}
+	for _, p := range points[1:] {
+		b.Min.X = min(b.Min.X, p.X)
+		b.Min.Y = min(b.Min.Y, p.Y)
+		b.Max.X = max(b.Max.X, p.X)
+		b.Max.Y = max(b.Max.Y, p.Y)
+	}
+	return b
+}
@@ -0,0 +1,137 @@
+package tochka
+
+import (
+	"image"
+	"math"
+	"testing"
+)
+
+// TestNewRect checks that the constructor canonicalizes the corners.
+func TestNewRect(t *testing.T) {
+	r := NewRect(5, 1, 2, 4)
+	want := Rect{Min: Point{X: 2, Y: 1}, Max: Point{X: 5, Y: 4}}
+	if r != want {
+		t.Errorf("NewRect() = %v; want %v", r, want)
+	}
+	if r.Dx() != 3 || r.Dy() != 3 || r.Size() != (Point{X: 3, Y: 3}) || r.Center() != (Point{X: 3.5, Y: 2.5}) {
+		t.Errorf("dimensions of %v are wrong: %v, %v, %v, %v", r, r.Dx(), r.Dy(), r.Size(), r.Center())
+	}
+}
+
+// TestRect_Empty checks the empty rectangle semantics.
+func TestRect_Empty(t *testing.T) {
+	tests := []struct {
+		r    Rect
+		want bool
+	}{
+		{Rect{}, true},
+		{NewRect(0, 0, 1, 1), false},
+		{NewRect(0, 0, 0, 1), true},
+		{Rect{Min: Point{X: 2, Y: 2}, Max: Point{X: 1, Y: 3}}, true},
+	}
+	for _, tt := range tests {
+		if got := tt.r.Empty(); got != tt.want {
+			t.Errorf("%v.Empty() = %v; want %v", tt.r, got, tt.want)
+		}
+	}
+	if !NewRect(1, 1, 1, 5).Eq(NewRect(3, 3, 4, 3)) {
+		t.Errorf("Eq() = false for two empty rectangles")
+	}
+}
+
+// TestRect_UnionIntersect checks the union and intersection of rectangles.
+func TestRect_UnionIntersect(t *testing.T) {
+	r, s := NewRect(0, 0, 4, 4), NewRect(2, 1, 6, 3)
+	if got, want := r.Union(s), NewRect(0, 0, 6, 4); got != want {
+		t.Errorf("Union() = %v; want %v", got, want)
+	}
+	if got, want := r.Intersect(s), NewRect(2, 1, 4, 3); got != want {
+		t.Errorf("Intersect() = %v; want %v", got, want)
+	}
+	if got := r.Intersect(NewRect(5, 5, 6, 6)); got != (Rect{}) {
+		t.Errorf("Intersect() = %v; want the zero rectangle", got)
+	}
+	if got := r.Union(Rect{Min: Point{X: 10, Y: 10}, Max: Point{X: 10, Y: 10}}); got != r {
+		t.Errorf("Union() with an empty rectangle = %v; want %v", got, r)
+	}
+}
+
+// TestRect_Contains checks the half-open containment rule.
+func TestRect_Contains(t *testing.T) {
+	r := NewRect(0, 0, 2, 2)
+	for _, p := range []Point{{X: 0, Y: 0}, {X: 1.5, Y: 1.999}} {
+		if !r.Contains(p) {
+			t.Errorf("Contains(%v) = false; want true", p)
+		}
+	}
+	for _, p := range []Point{{X: 2, Y: 1}, {X: 1, Y: 2}, {X: -0.1, Y: 1}} {
+		if r.Contains(p) {
+			t.Errorf("Contains(%v) = true; want false", p)
+		}
+	}
+	if !NewRect(0.5, 0.5, 1, 1).In(r) || r.In(NewRect(0.5, 0.5, 1, 1)) {
+		t.Errorf("In() failed")
+	}
+}
+
+// TestRect_Overlaps checks overlap detection, including touching edges.
+func TestRect_Overlaps(t *testing.T) {
+	r := NewRect(0, 0, 2, 2)
+	if !r.Overlaps(NewRect(1, 1, 3, 3)) {
+		t.Errorf("Overlaps() = false for overlapping rectangles")
+	}
+	if r.Overlaps(NewRect(2, 0, 3, 2)) {
+		t.Errorf("Overlaps() = true for rectangles sharing an edge")
+	}
+	if r.Overlaps(Rect{}) {
+		t.Errorf("Overlaps() = true for an empty rectangle")
+	}
+}
+
+// TestRect_Inset checks insetting, including collapsing to the center.
+func TestRect_Inset(t *testing.T) {
+	r := NewRect(0, 0, 10, 4)
+	if got, want := r.Inset(1), NewRect(1, 1, 9, 3); got != want {
+		t.Errorf("Inset(1) = %v; want %v", got, want)
+	}
+	if got, want := r.Inset(-1), NewRect(-1, -1, 11, 5); got != want {
+		t.Errorf("Inset(-1) = %v; want %v", got, want)
+	}
+	if got, want := r.Inset(3), NewRect(3, 2, 7, 2); got != want {
+		t.Errorf("Inset(3) = %v; want %v", got, want)
+	}
+}
+
+// TestRect_Image checks conversion to and from image.Rectangle.
+func TestRect_Image(t *testing.T) {
+	ir := image.Rect(-1, 2, 3, 4)
+	if got := RectFromImage(ir).RoundOut(); got != ir {
+		t.Errorf("RectFromImage().RoundOut() = %v; want %v", got, ir)
+	}
+	r := NewRect(-0.5, 0.2, 2.7, 3)
+	if got, want := r.RoundOut(), image.Rect(-1, 0, 3, 3); got != want {
+		t.Errorf("RoundOut() = %v; want %v", got, want)
+	}
+	if got, want := r.RoundIn(), image.Rect(0, 1, 2, 3); got != want {
+		t.Errorf("RoundIn() = %v; want %v", got, want)
+	}
+	if got := NewRect(0.2, 0.2, 0.8, 5).RoundIn(); got != (image.Rectangle{}) {
+		t.Errorf("RoundIn() = %v; want the zero rectangle", got)
+	}
+}
+
+// TestAffine2D_TransformRect checks the bounds of a transformed rectangle.
+func TestAffine2D_TransformRect(t *testing.T) {
+	r := NewRect(0, 0, 2, 2)
+	a := Affine2D{}.Rotate(Point{X: 1, Y: 1}, math.Pi/4)
+	got := a.TransformRect(r)
+	h := float32(math.Sqrt2)
+	want := NewRect(1-h, 1-h, 1+h, 1+h)
+	if !pointAlmostEqual(got.Min, want.Min, 1e-5) || !pointAlmostEqual(got.Max, want.Max, 1e-5) {
+		t.Errorf("TransformRect() = %v; want %v", got, want)
+	}
+	flip := NewAffine2D(-1, 0, 0, 0, 1, 0)
+	if got, want := flip.TransformRect(r), NewRect(-2, 0, 0, 2); got != want {
+		t.Errorf("TransformRect() = %v; want %v", got, want)
+	}
+}
@@ -0,0 +1,160 @@
+package tochka
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// ParseError reports malformed text input together with the byte offset at which
+// the problem was detected.
+type ParseError struct {
+	// Offset is the byte offset of the problem in the input.
+	Offset int
+	// Msg describes the problem.
+	Msg string
+}
+
+// Error returns the description of the problem followed by its offset.
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s at offset %d", e.Msg, e.Offset)
+}
+
+// textScanner reads numbers and separators of SVG and CSS syntax from a string.
+type textScanner struct {
+	s   string
+	pos int
+}
+
+// number parses a number at the current position.
+func (sc *textScanner) number() (float64, error) {
+	start, i := sc.pos, sc.pos
+	s := sc.s
+	if i < len(s) && (s[i] == '+' || s[i] == '-') {
+		i++
+	}
+	digits := 0
+	for ; i < len(s) && isDigit(s[i]); i++ {
+		digits++
+	}
+	if i < len(s) && s[i] == '.' {
+		i++
+		for ; i < len(s) && isDigit(s[i]); i++ {
+			digits++
+		}
+	}
+	if digits == 0 {
+		return 0, sc.errorf("expected number")
+	}
+	// An exponent is only part of the number if digits follow it.
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		j := i + 1
+		if j < len(s) && (s[j] == '+' || s[j] == '-') {
+			j++
+		}
+		if j < len(s) && isDigit(s[j]) {
+			for i = j; i < len(s) && isDigit(s[i]); i++ {
+			}
+		}
+	}
+	v, err := strconv.ParseFloat(s[start:i], 64)
+	if err != nil {
+		return 0, sc.errorf("number out of range")
+	}
+	sc.pos = i
+	return v, nil
+}
+
+// flag parses an arc flag, which is a single 0 or 1 that needs no separator from what follows.
+func (sc *textScanner) flag() (float64, error) {
+	if sc.pos < len(sc.s) {
+		switch sc.s[sc.pos] {
+		case '0':
+			sc.pos++
+			return 0, nil
+		case '1':
+			sc.pos++
+			return 1, nil
+		}
+	}
+	return 0, sc.errorf("expected flag")
+}
+
+// atNumber reports whether a number starts at the current position.
+func (sc *textScanner) atNumber() bool {
+	if sc.pos == len(sc.s) {
+		return false
+	}
+	c := sc.s[sc.pos]
+	return isDigit(c) || c == '+' || c == '-' || c == '.'
+}
+
+// skipSpace skips whitespace.
+func (sc *textScanner) skipSpace() {
+	for sc.pos < len(sc.s) && isSpace(sc.s[sc.pos]) {
+		sc.pos++
+	}
+}
+
+// skipSeparator skips whitespace with at most one comma.
+func (sc *textScanner) skipSeparator() {
+	sc.skipSpace()
+	if sc.pos < len(sc.s) && sc.s[sc.pos] == ',' {
+		sc.pos++
+		sc.skipSpace()
+	}
+}
+
+// errorf returns a *ParseError at the current position.
+func (sc *textScanner) errorf(format string, args ...any) error {
+	return &ParseError{Offset: sc.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// isSpace reports whether c is whitespace in SVG and CSS syntax.
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+// isDigit reports whether c is a decimal digit.
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// appendNumber appends the shortest text of v rounded to precision decimal places to dst,
+// without trailing zeros or a leading zero before the decimal point. A negative precision
+// uses the fewest digits that represent v rounded to float32 exactly.
+func appendNumber(dst []byte, v float64, precision int) []byte {
+	var s []byte
+	if precision < 0 {
+		s = strconv.AppendFloat(nil, float64(float32(v)), 'f', -1, 32)
+	} else {
+		s = strconv.AppendFloat(nil, roundTo(v, precision), 'f', precision, 64)
+		if bytes.IndexByte(s, '.') >= 0 {
+			for s[len(s)-1] == '0' {
+				s = s[:len(s)-1]
+			}
+			if s[len(s)-1] == '.' {
+				s = s[:len(s)-1]
+			}
+		}
+	}
+	switch {
+	case string(s) == "-0":
+		s = s[1:]
+	case len(s) > 1 && s[0] == '0' && s[1] == '.':
+		s = s[1:]
+	case len(s) > 2 && s[0] == '-' && s[1] == '0' && s[2] == '.':
+		s = append(s[:1], s[2:]...)
+	}
+	return append(dst, s...)
+}
+
+// roundTo rounds v to precision decimal places, or to float32 with a negative precision.
+func roundTo(v float64, precision int) float64 {
+	if precision < 0 {
+		return float64(float32(v))
+	}
+	scale := math.Pow10(precision)
+	return math.Round(v*scale) / scale
+}
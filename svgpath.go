@@ -0,0 +1,409 @@
+package tochka
+
+import (
+	"bytes"
+	"math"
+)
+
+// ParseSVGPath parses SVG path data, as found in the d attribute of a path element, into
+// a Path. All absolute and relative commands are supported, along with the compact number
+// syntax in which separators are omitted where unambiguous, as in "M.5.5l-1e2-3" or
+// "a1 1 0 00 1 1". Arc rotations are given in degrees.
+//
+// Malformed input yields a *ParseError locating the problem. As in SVG renderers, the path
+// holding the commands parsed before the error is returned along with it.
+func ParseSVGPath(d string) (*Path, error) {
+	ps := svgParser{textScanner: textScanner{s: d}, path: NewPath()}
+	err := ps.parse()
+	return ps.path, err
+}
+
+// svgArgs is the number of arguments taken by each uppercase SVG path command.
+var svgArgs = map[byte]int{'M': 2, 'L': 2, 'H': 1, 'V': 1, 'C': 6, 'S': 4, 'Q': 4, 'T': 2, 'A': 7}
+
+// svgParser holds the state of ParseSVGPath. Current points are tracked in double
+// precision so that long runs of relative commands do not accumulate rounding errors.
+type svgParser struct {
+	textScanner
+	path *Path
+	// cur is the current point and start is the first point of the current subpath.
+	cur, start Point64
+	// ctrl is the last control point of the previous command, which S and T reflect
+	// if the previous command was of the same family, as recorded by prev.
+	ctrl Point64
+	prev byte
+}
+
+// parse parses the whole path data.
+func (ps *svgParser) parse() error {
+	var cmd byte
+	for {
+		ps.skipSpace()
+		if ps.pos == len(ps.s) {
+			return nil
+		}
+		c := ps.s[ps.pos]
+		switch {
+		case isSVGCommand(c):
+			if ps.path.Empty() && c != 'M' && c != 'm' {
+				return ps.errorf("path data must begin with a moveto command")
+			}
+			cmd = c
+			ps.pos++
+		case cmd == 0:
+			return ps.errorf("path data must begin with a moveto command")
+		case cmd == 'Z' || cmd == 'z' || !ps.atNumber():
+			return ps.errorf("unexpected character %q", c)
+		}
+		if err := ps.command(cmd); err != nil {
+			return err
+		}
+		// Coordinates following a moveto are implicit lineto commands.
+		switch cmd {
+		case 'M':
+			cmd = 'L'
+		case 'm':
+			cmd = 'l'
+		}
+		// A comma may separate the argument groups of a repeated command.
+		if cmd != 'Z' && cmd != 'z' {
+			ps.skipSpace()
+			if ps.pos < len(ps.s) && ps.s[ps.pos] == ',' {
+				ps.pos++
+				ps.skipSpace()
+				if !ps.atNumber() {
+					return ps.errorf("expected number")
+				}
+			}
+		}
+	}
+}
+
+// command parses the arguments of a single command and appends it to the path.
+func (ps *svgParser) command(cmd byte) error {
+	rel := cmd >= 'a'
+	upper := cmd
+	if rel {
+		upper -= 'a' - 'A'
+	}
+	if upper == 'Z' {
+		ps.path.Close()
+		ps.cur, ps.prev = ps.start, upper
+		return nil
+	}
+	var args [7]float64
+	for k := 0; k < svgArgs[upper]; k++ {
+		if k == 0 {
+			ps.skipSpace()
+		} else {
+			ps.skipSeparator()
+		}
+		var err error
+		if upper == 'A' && (k == 3 || k == 4) {
+			args[k], err = ps.flag()
+		} else {
+			args[k], err = ps.number()
+		}
+		if err != nil {
+			return err
+		}
+	}
+	// point returns the point given by the arguments at k, resolving relative coordinates.
+	point := func(k int) Point64 {
+		p := Point64{X: args[k], Y: args[k+1]}
+		if rel {
+			p = p.Add(ps.cur)
+		}
+		return p
+	}
+	// reflected returns the reflection of the previous control point if the previous
+	// command belongs to the family, and the current point otherwise.
+	reflected := func(family ...byte) Point64 {
+		for _, f := range family {
+			if ps.prev == f {
+				return ps.cur.Mul(2).Sub(ps.ctrl)
+			}
+		}
+		return ps.cur
+	}
+
+	var end Point64
+	switch upper {
+	case 'M':
+		end = point(0)
+		ps.path.MoveTo(end.To32())
+		ps.start = end
+	case 'L':
+		end = point(0)
+		ps.path.LineTo(end.To32())
+	case 'H':
+		end = Point64{X: args[0], Y: ps.cur.Y}
+		if rel {
+			end.X += ps.cur.X
+		}
+		ps.path.LineTo(end.To32())
+	case 'V':
+		end = Point64{X: ps.cur.X, Y: args[0]}
+		if rel {
+			end.Y += ps.cur.Y
+		}
+		ps.path.LineTo(end.To32())
+	case 'C':
+		c1, c2 := point(0), point(2)
+		end = point(4)
+		ps.path.CubicTo(c1.To32(), c2.To32(), end.To32())
+		ps.ctrl = c2
+	case 'S':
+		c1, c2 := reflected('C', 'S'), point(0)
+		end = point(2)
+		ps.path.CubicTo(c1.To32(), c2.To32(), end.To32())
+		ps.ctrl = c2
+	case 'Q':
+		c := point(0)
+		end = point(2)
+		ps.path.QuadTo(c.To32(), end.To32())
+		ps.ctrl = c
+	case 'T':
+		c := reflected('Q', 'T')
+		end = point(0)
+		ps.path.QuadTo(c.To32(), end.To32())
+		ps.ctrl = c
+	case 'A':
+		end = point(5)
+		radii := Point{X: float32(args[0]), Y: float32(args[1])}
+		rotation := float32(args[2] * math.Pi / 180)
+		ps.path.ArcTo(radii, rotation, args[3] != 0, args[4] != 0, end.To32())
+	}
+	ps.cur, ps.prev = end, upper
+	return nil
+}
+
+// isSVGCommand reports whether c is an SVG path command letter.
+func isSVGCommand(c byte) bool {
+	switch c {
+	case 'M', 'm', 'L', 'l', 'H', 'h', 'V', 'v', 'C', 'c', 'S', 's', 'Q', 'q', 'T', 't', 'A', 'a', 'Z', 'z':
+		return true
+	}
+	return false
+}
+
+// SVGPath formats the path as SVG path data of minimal length. Coordinates are rounded
+// to precision decimal places; a negative precision uses the fewest digits that represent
+// every float32 coordinate exactly. For each command the shortest of the absolute and
+// relative forms is chosen, lines parallel to an axis use H and V, curves whose first control
+// point is the reflection of the previous one use S and T, repeated command letters are
+// omitted, and separators are only emitted where needed. Relative forms are only considered
+// with a non-negative precision, where they do not accumulate rounding errors.
+func (p *Path) SVGPath(precision int) string {
+	var segments []PathSegment
+	for s := range p.Segments() {
+		segments = append(segments, s)
+	}
+	w := svgWriter{precision: precision}
+	for k, s := range segments {
+		switch s.Verb {
+		case VerbMove:
+			// A subpath started implicitly after a close needs no moveto.
+			end := w.round(s.End.To64())
+			if w.cmd == 'Z' && end == w.cur && k+1 < len(segments) && segments[k+1].Verb != VerbMove {
+				continue
+			}
+			w.shortest(svgCandidate{'M', []float64{end.X, end.Y}}, svgCandidate{'m', w.rel(end)})
+			w.cur, w.start = end, end
+		case VerbLine:
+			w.line(w.round(s.End.To64()))
+		case VerbQuad:
+			w.quad(w.round(s.Ctrl[0].To64()), w.round(s.End.To64()))
+		case VerbCubic:
+			w.cubic(w.round(s.Ctrl[0].To64()), w.round(s.Ctrl[1].To64()), w.round(s.End.To64()))
+		case VerbArc:
+			w.arc(s.Arc, w.round(s.End.To64()))
+		case VerbClose:
+			w.write('Z', nil)
+			w.cur = w.start
+		}
+	}
+	return string(w.buf)
+}
+
+// svgCandidate is one way of writing an SVG path command.
+type svgCandidate struct {
+	cmd  byte
+	args []float64
+}
+
+// svgWriter accumulates SVG path data. Coordinates are tracked as they will be parsed back,
+// that is rounded to the precision, so that relative coordinates and reflected control
+// points refer to the same values as the parser.
+type svgWriter struct {
+	buf       []byte
+	precision int
+	// cmd is the last command letter written, which may be repeated implicitly.
+	cmd byte
+	// sep tells whether the next number needs a separator unless it starts with a sign,
+	// and dot whether the last number contains a decimal point.
+	sep, dot bool
+	// cur is the current point, start is the first point of the subpath
+	// and ctrl the last control point of the previous Bézier command.
+	cur, start, ctrl Point64
+}
+
+// line writes a line to end using L, H or V.
+func (w *svgWriter) line(end Point64) {
+	d := w.rel(end)
+	cands := []svgCandidate{{'L', []float64{end.X, end.Y}}, {'l', d}}
+	switch {
+	case end.Y == w.cur.Y:
+		cands = append(cands, svgCandidate{'H', []float64{end.X}}, svgCandidate{'h', d[:1]})
+	case end.X == w.cur.X:
+		cands = append(cands, svgCandidate{'V', []float64{end.Y}}, svgCandidate{'v', d[1:]})
+	}
+	w.shortest(cands...)
+	w.cur = end
+}
+
+// quad writes a quadratic Bézier curve using Q or T.
+func (w *svgWriter) quad(c, end Point64) {
+	var cands []svgCandidate
+	if c == w.reflection('Q', 'T') {
+		cands = append(cands, svgCandidate{'T', []float64{end.X, end.Y}}, svgCandidate{'t', w.rel(end)})
+	} else {
+		cands = append(cands, svgCandidate{'Q', []float64{c.X, c.Y, end.X, end.Y}}, svgCandidate{'q', w.rel(c, end)})
+	}
+	w.shortest(cands...)
+	w.cur, w.ctrl = end, c
+}
+
+// cubic writes a cubic Bézier curve using C or S.
+func (w *svgWriter) cubic(c1, c2, end Point64) {
+	var cands []svgCandidate
+	if c1 == w.reflection('C', 'S') {
+		cands = append(cands,
+			svgCandidate{'S', []float64{c2.X, c2.Y, end.X, end.Y}}, svgCandidate{'s', w.rel(c2, end)})
+	} else {
+		cands = append(cands,
+			svgCandidate{'C', []float64{c1.X, c1.Y, c2.X, c2.Y, end.X, end.Y}}, svgCandidate{'c', w.rel(c1, c2, end)})
+	}
+	w.shortest(cands...)
+	w.cur, w.ctrl = end, c2
+}
+
+// arc writes an elliptical arc using A. An arc returning to its start cannot be expressed
+// by a single command and is written in two halves.
+func (w *svgWriter) arc(a Arc, end Point64) {
+	if end == w.cur || math.Abs(float64(a.Sweep)) >= 2*math.Pi {
+		first, second := a, a
+		first.Sweep /= 2
+		second.Start += first.Sweep
+		second.Sweep -= first.Sweep
+		w.arc(first, w.round(a.At(0.5).To64()))
+		w.arc(second, end)
+		return
+	}
+	rx, ry := float64(a.Ellipse.Radii.X), float64(a.Ellipse.Radii.Y)
+	sweep := a.Sweep > 0
+	// Negating one radius mirrors the parameterization and reverses the sweep.
+	if (rx < 0) != (ry < 0) {
+		sweep = !sweep
+	}
+	rotation := float64(a.Ellipse.Rotation) * 180 / math.Pi
+	args := func(p Point64) []float64 {
+		return []float64{
+			w.roundValue(math.Abs(rx)), w.roundValue(math.Abs(ry)), w.roundValue(rotation),
+			boolFlag(math.Abs(float64(a.Sweep)) > math.Pi), boolFlag(sweep), p.X, p.Y,
+		}
+	}
+	w.shortest(svgCandidate{'A', args(end)}, svgCandidate{'a', args(Point64{X: end.X - w.cur.X, Y: end.Y - w.cur.Y})})
+	w.cur = end
+}
+
+// reflection returns the control point implied by S or T, which is the reflection of the previous
+// control point if the last command belongs to the family, and the current point otherwise.
+func (w *svgWriter) reflection(family ...byte) Point64 {
+	for _, f := range family {
+		if w.cmd == f || w.cmd == f+'a'-'A' {
+			return w.round(w.cur.Mul(2).Sub(w.ctrl))
+		}
+	}
+	return w.cur
+}
+
+// shortest writes the shortest of the candidates, preferring the earliest on ties.
+func (w *svgWriter) shortest(cands ...svgCandidate) {
+	best, bestLen := 0, math.MaxInt
+	for k, c := range cands {
+		if c.cmd >= 'a' && w.precision < 0 {
+			continue
+		}
+		trial := *w
+		trial.buf = nil
+		trial.write(c.cmd, c.args)
+		if len(trial.buf) < bestLen {
+			best, bestLen = k, len(trial.buf)
+		}
+	}
+	w.write(cands[best].cmd, cands[best].args)
+}
+
+// write writes a command with its arguments, omitting the letter when the command
+// is implied by the previous one.
+func (w *svgWriter) write(cmd byte, args []float64) {
+	implied := cmd == w.cmd && cmd != 'M' && cmd != 'm' && cmd != 'Z' && cmd != 'z' ||
+		cmd == 'L' && w.cmd == 'M' || cmd == 'l' && w.cmd == 'm'
+	if !implied {
+		w.buf = append(w.buf, cmd)
+		w.sep, w.dot = false, false
+	}
+	w.cmd = cmd
+	for k, v := range args {
+		if (cmd == 'A' || cmd == 'a') && (k == 3 || k == 4) {
+			// Flags are single characters, so nothing following them needs a separator.
+			if w.sep {
+				w.buf = append(w.buf, ' ')
+			}
+			w.buf = append(w.buf, '0'+byte(v))
+			w.sep, w.dot = false, false
+			continue
+		}
+		w.number(v)
+	}
+}
+
+// number writes a number with the shortest representation at the precision,
+// preceded by a separator only where the number would otherwise merge with the previous one.
+func (w *svgWriter) number(v float64) {
+	s := appendNumber(nil, v, w.precision)
+	hasDot := bytes.IndexByte(s, '.') >= 0
+	if w.sep && s[0] != '-' && !(s[0] == '.' && w.dot) {
+		w.buf = append(w.buf, ' ')
+	}
+	w.buf = append(w.buf, s...)
+	w.sep, w.dot = true, hasDot
+}
+
+// rel returns the coordinates of the points relative to the current point.
+func (w *svgWriter) rel(points ...Point64) []float64 {
+	out := make([]float64, 0, 2*len(points))
+	for _, p := range points {
+		out = append(out, w.roundValue(p.X-w.cur.X), w.roundValue(p.Y-w.cur.Y))
+	}
+	return out
+}
+
+// round rounds the coordinates of p to the precision.
+func (w *svgWriter) round(p Point64) Point64 {
+	return Point64{X: w.roundValue(p.X), Y: w.roundValue(p.Y)}
+}
+
+// roundValue rounds v to the precision, or to float32 with a negative precision.
+func (w *svgWriter) roundValue(v float64) float64 {
+	return roundTo(v, w.precision)
+}
+
+// boolFlag converts a flag to the value of an SVG arc flag argument.
+func boolFlag(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
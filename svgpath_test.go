@@ -0,0 +1,171 @@
+package tochka
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// segmentsOf collects the segments of a path.
+func segmentsOf(p *Path) []PathSegment {
+	var out []PathSegment
+	for s := range p.Segments() {
+		out = append(out, s)
+	}
+	return out
+}
+
+// TestParseSVGPath tests absolute and relative commands, implicit repetition and reflection.
+func TestParseSVGPath(t *testing.T) {
+	p, err := ParseSVGPath("M10,10 20 10 h5v5H10 V12 z m1-1 l1 1 c1 0 2 1 2 2 s1 2 2 2 Q20 20 22 22 t4 0 T30 30")
+	if err != nil {
+		t.Fatalf("ParseSVGPath() error = %v", err)
+	}
+	pt := func(x, y float32) Point { return Point{X: x, Y: y} }
+	want := []PathSegment{
+		{Verb: VerbMove, End: pt(10, 10)},
+		{Verb: VerbLine, Start: pt(10, 10), End: pt(20, 10)},
+		{Verb: VerbLine, Start: pt(20, 10), End: pt(25, 10)},
+		{Verb: VerbLine, Start: pt(25, 10), End: pt(25, 15)},
+		{Verb: VerbLine, Start: pt(25, 15), End: pt(10, 15)},
+		{Verb: VerbLine, Start: pt(10, 15), End: pt(10, 12)},
+		{Verb: VerbClose, Start: pt(10, 12), End: pt(10, 10)},
+		// The relative moveto after close is relative to the start of the closed subpath.
+		{Verb: VerbMove, Start: pt(10, 10), End: pt(11, 9)},
+		{Verb: VerbLine, Start: pt(11, 9), End: pt(12, 10)},
+		{Verb: VerbCubic, Start: pt(12, 10), Ctrl: [2]Point{pt(13, 10), pt(14, 11)}, End: pt(14, 12)},
+		{Verb: VerbCubic, Start: pt(14, 12), Ctrl: [2]Point{pt(14, 13), pt(15, 14)}, End: pt(16, 14)},
+		{Verb: VerbQuad, Start: pt(16, 14), Ctrl: [2]Point{pt(20, 20)}, End: pt(22, 22)},
+		{Verb: VerbQuad, Start: pt(22, 22), Ctrl: [2]Point{pt(24, 24)}, End: pt(26, 22)},
+		{Verb: VerbQuad, Start: pt(26, 22), Ctrl: [2]Point{pt(28, 20)}, End: pt(30, 30)},
+	}
+	got := segmentsOf(p)
+	if len(got) != len(want) {
+		t.Fatalf("ParseSVGPath() = %d segments; want %d: %+v", len(got), len(want), got)
+	}
+	for k := range want {
+		if got[k] != want[k] {
+			t.Errorf("segment %d = %+v; want %+v", k, got[k], want[k])
+		}
+	}
+}
+
+// TestParseSVGPath_Compact tests the compact number syntax and arcs with packed flags.
+func TestParseSVGPath_Compact(t *testing.T) {
+	p, err := ParseSVGPath("M.5.5l-1e1-2E-1,+3 .5\nA1 1 90 01-9.5 1")
+	if err != nil {
+		t.Fatalf("ParseSVGPath() error = %v", err)
+	}
+	got := segmentsOf(p)
+	if len(got) != 4 {
+		t.Fatalf("ParseSVGPath() = %+v; want 4 segments", got)
+	}
+	if got[0].End != (Point{X: 0.5, Y: 0.5}) || !pointAlmostEqual(got[1].End, Point{X: -9.5, Y: 0.3}, 1e-6) ||
+		!pointAlmostEqual(got[2].End, Point{X: -6.5, Y: 0.8}, 1e-6) {
+		t.Errorf("ParseSVGPath() = %+v", got)
+	}
+	a := got[3]
+	if a.Verb != VerbArc || a.End != (Point{X: -9.5, Y: 1}) || !almostEqual(a.Arc.Ellipse.Rotation, math.Pi/2, 1e-6) || a.Arc.Sweep <= 0 {
+		t.Errorf("ParseSVGPath() arc = %+v", a)
+	}
+	if p, err := ParseSVGPath(" \t"); err != nil || !p.Empty() {
+		t.Errorf("ParseSVGPath() of blank data = %v, %v; want an empty path", p, err)
+	}
+}
+
+// TestParseSVGPath_Errors tests that malformed data is reported at the right offset
+// and that the commands before the error are kept.
+func TestParseSVGPath_Errors(t *testing.T) {
+	tests := []struct {
+		d      string
+		offset int
+		len    int
+	}{
+		{"L1 2", 0, 0},
+		{"  10 20", 2, 0},
+		{"M1", 2, 0},
+		{"M1 2 x", 5, 1},
+		{"M1,2,L3 4", 5, 1},
+		{"M0 0A1 1 0 2 0 1 1", 11, 1},
+		{"M0 0Z1", 5, 2},
+		{"M0 0L1e999 0", 5, 1},
+		{"M0 0L1 2 3", 10, 2},
+		{"M0 0 L.", 6, 1},
+	}
+	for _, tt := range tests {
+		p, err := ParseSVGPath(tt.d)
+		var perr *ParseError
+		if !errors.As(err, &perr) {
+			t.Errorf("ParseSVGPath(%q) error = %v; want a *ParseError", tt.d, err)
+			continue
+		}
+		if perr.Offset != tt.offset {
+			t.Errorf("ParseSVGPath(%q) error = %v; want offset %d", tt.d, err, tt.offset)
+		}
+		if p.Len() != tt.len {
+			t.Errorf("ParseSVGPath(%q) kept %d commands; want %d", tt.d, p.Len(), tt.len)
+		}
+	}
+}
+
+// TestPath_SVGPath tests the minimal formatting of path data.
+func TestPath_SVGPath(t *testing.T) {
+	tests := []struct {
+		d         string
+		precision int
+		want      string
+	}{
+		{"M10 10 L20 10 L20 20 L10 20 Z", 3, "M10 10H20V20H10Z"},
+		{"M0.5 -0.5 L 1.5 .5", 3, "M.5-.5l1 1"},
+		{"M0.5 -0.5 L 1.5 .5", -1, "M.5-.5 1.5.5"},
+		{"M0 0C0 1 1 1 1 0C1-1 2-1 2 0", 3, "M0 0C0 1 1 1 1 0S2-1 2 0"},
+		{"M0 0Q1 1 2 0Q3-1 4 0", 3, "M0 0Q1 1 2 0T4 0"},
+		{"M100 100L110.25 103.125L105.25 108.125ZL101 101", 3, "M100 100l10.25 3.125-5 5Zl1 1"},
+		{"M1.23456 0L2 0", 2, "M1.23 0H2"},
+		{"M0 0A5 4 0 0 1 6 0", 3, "M0 0A5 4 0 016 0"},
+	}
+	for _, tt := range tests {
+		p, err := ParseSVGPath(tt.d)
+		if err != nil {
+			t.Fatalf("ParseSVGPath(%q) error = %v", tt.d, err)
+		}
+		if got := p.SVGPath(tt.precision); got != tt.want {
+			t.Errorf("SVGPath(%d) of %q = %q; want %q", tt.precision, tt.d, got, tt.want)
+		}
+	}
+}
+
+// TestPath_SVGPathRoundTrip tests that formatted path data parses back to the same path.
+func TestPath_SVGPathRoundTrip(t *testing.T) {
+	var p Path
+	p.MoveTo(Point{X: 1.5, Y: -2})
+	p.CubicTo(Point{X: 3, Y: 4}, Point{X: 5.25, Y: 4}, Point{X: 7, Y: -2})
+	p.QuadTo(Point{X: 8, Y: -8}, Point{X: 12, Y: 0})
+	p.ArcTo(Point{X: 3, Y: 2}, 0.4, true, false, Point{X: 12, Y: 6})
+	p.Close()
+	p.AddEllipse(NewEllipse(Point{X: -4, Y: 3}, 2, 1, 0.3))
+	p.LineTo(Point{X: 0, Y: 0})
+
+	for _, precision := range []int{-1, 4} {
+		d := p.SVGPath(precision)
+		q, err := ParseSVGPath(d)
+		if err != nil {
+			t.Fatalf("ParseSVGPath(%q) error = %v", d, err)
+		}
+		// Flattened geometry is compared since the full ellipse is written as two arcs.
+		want, got := p.Polylines(0.01), q.Polylines(0.01)
+		if len(got) != len(want) {
+			t.Fatalf("round trip of %q = %d subpaths; want %d", d, len(got), len(want))
+		}
+		for k := range want {
+			a, b := want[k], got[k]
+			if !pointAlmostEqual(a[0], b[0], 1e-4) || !pointAlmostEqual(a[len(a)-1], b[len(b)-1], 1e-4) {
+				t.Errorf("round trip of %q: subpath %d runs from %v to %v; want %v to %v",
+					d, k, b[0], b[len(b)-1], a[0], a[len(a)-1])
+			}
+			if got, want := Ring(b).Area(), Ring(a).Area(); !almostEqual(got, want, 1e-2) {
+				t.Errorf("round trip of %q: subpath %d area = %v; want %v", d, k, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,308 @@
+package tochka
+
+import (
+	"math"
+	"strings"
+)
+
+// unitKind classifies the arguments of transform functions by the units they accept.
+type unitKind int
+
+const (
+	unitNumber unitKind = iota
+	unitLength
+	unitAngle
+)
+
+// transformFunc describes a transform function of SVG or CSS.
+type transformFunc struct {
+	// kinds holds the unit kind of every argument, and counts the valid argument counts.
+	kinds  []unitKind
+	counts []int
+	// build returns the matrix of the function, with angles already in radians.
+	build func(args []float64) Affine2D64
+}
+
+// transformFuncs holds the supported transform functions by name.
+var transformFuncs = map[string]transformFunc{
+	"matrix": {
+		kinds:  []unitKind{unitNumber, unitNumber, unitNumber, unitNumber, unitNumber, unitNumber},
+		counts: []int{6},
+		build: func(a []float64) Affine2D64 {
+			return NewAffine2D64(a[0], a[2], a[4], a[1], a[3], a[5])
+		},
+	},
+	"translate": {
+		kinds:  []unitKind{unitLength, unitLength},
+		counts: []int{1, 2},
+		build: func(a []float64) Affine2D64 {
+			return NewAffine2D64(1, 0, a[0], 0, 1, a[1])
+		},
+	},
+	"translateX": {
+		kinds:  []unitKind{unitLength},
+		counts: []int{1},
+		build: func(a []float64) Affine2D64 {
+			return NewAffine2D64(1, 0, a[0], 0, 1, 0)
+		},
+	},
+	"translateY": {
+		kinds:  []unitKind{unitLength},
+		counts: []int{1},
+		build: func(a []float64) Affine2D64 {
+			return NewAffine2D64(1, 0, 0, 0, 1, a[0])
+		},
+	},
+	"scale": {
+		kinds:  []unitKind{unitNumber, unitNumber},
+		counts: []int{1, 2},
+		build: func(a []float64) Affine2D64 {
+			return NewAffine2D64(a[0], 0, 0, 0, a[1], 0)
+		},
+	},
+	"scaleX": {
+		kinds:  []unitKind{unitNumber},
+		counts: []int{1},
+		build: func(a []float64) Affine2D64 {
+			return NewAffine2D64(a[0], 0, 0, 0, 1, 0)
+		},
+	},
+	"scaleY": {
+		kinds:  []unitKind{unitNumber},
+		counts: []int{1},
+		build: func(a []float64) Affine2D64 {
+			return NewAffine2D64(1, 0, 0, 0, a[0], 0)
+		},
+	},
+	"rotate": {
+		kinds:  []unitKind{unitAngle, unitLength, unitLength},
+		counts: []int{1, 3},
+		build: func(a []float64) Affine2D64 {
+			sin, cos := math.Sincos(a[0])
+			cx, cy := a[1], a[2]
+			// Rotate about (cx, cy), which is translate(cx, cy) rotate(a) translate(-cx, -cy).
+			return NewAffine2D64(
+				cos, -sin, cx-cos*cx+sin*cy,
+				sin, cos, cy-sin*cx-cos*cy,
+			)
+		},
+	},
+	"skewX": {
+		kinds:  []unitKind{unitAngle},
+		counts: []int{1},
+		build: func(a []float64) Affine2D64 {
+			return NewAffine2D64(1, math.Tan(a[0]), 0, 0, 1, 0)
+		},
+	},
+	"skewY": {
+		kinds:  []unitKind{unitAngle},
+		counts: []int{1},
+		build: func(a []float64) Affine2D64 {
+			return NewAffine2D64(1, 0, 0, math.Tan(a[0]), 1, 0)
+		},
+	},
+	"skew": {
+		kinds:  []unitKind{unitAngle, unitAngle},
+		counts: []int{1, 2},
+		build: func(a []float64) Affine2D64 {
+			return NewAffine2D64(1, math.Tan(a[0]), 0, math.Tan(a[1]), 1, 0)
+		},
+	},
+}
+
+// ParseTransform parses an SVG transform attribute or a CSS transform property into an
+// affine transformation. The list may contain any sequence of the functions matrix,
+// translate, translateX, translateY, scale, scaleX, scaleY, rotate, skewX, skewY and skew,
+// separated by whitespace or commas, and is applied from right to left as in SVG. Arguments
+// may be separated by whitespace or commas. Angles are in degrees unless they carry one
+// of the CSS units deg, rad, grad or turn, and lengths may carry the unit px. The keyword
+// none and an empty string yield the identity.
+//
+// Malformed input yields a *ParseError locating the problem.
+func ParseTransform(s string) (Affine2D, error) {
+	if strings.TrimFunc(s, isSpaceRune) == "none" {
+		return Affine2D{}, nil
+	}
+	sc := textScanner{s: s}
+	m := NewAffine2D64(1, 0, 0, 0, 1, 0)
+	sc.skipSpace()
+	for sc.pos < len(s) {
+		f, err := sc.transformFunc()
+		if err != nil {
+			return Affine2D{}, err
+		}
+		m = m.Mul(f)
+		sc.skipSpace()
+		if sc.pos < len(s) && s[sc.pos] == ',' {
+			sc.pos++
+			sc.skipSpace()
+			if sc.pos == len(s) {
+				return Affine2D{}, sc.errorf("expected transform function")
+			}
+		}
+	}
+	return m.To32(), nil
+}
+
+// transformFunc parses a single transform function and returns its matrix.
+func (sc *textScanner) transformFunc() (Affine2D64, error) {
+	start := sc.pos
+	for sc.pos < len(sc.s) && isLetter(sc.s[sc.pos]) {
+		sc.pos++
+	}
+	name := sc.s[start:sc.pos]
+	if name == "" {
+		return Affine2D64{}, sc.errorf("expected transform function")
+	}
+	fn, ok := transformFuncs[name]
+	if !ok {
+		sc.pos = start
+		return Affine2D64{}, sc.errorf("unknown transform function %q", name)
+	}
+	sc.skipSpace()
+	if sc.pos == len(sc.s) || sc.s[sc.pos] != '(' {
+		return Affine2D64{}, sc.errorf("expected '('")
+	}
+	sc.pos++
+	var args []float64
+	for {
+		sc.skipSpace()
+		comma := false
+		if len(args) > 0 && sc.pos < len(sc.s) && sc.s[sc.pos] == ',' {
+			sc.pos++
+			sc.skipSpace()
+			comma = true
+		}
+		if !comma && sc.pos < len(sc.s) && sc.s[sc.pos] == ')' {
+			sc.pos++
+			break
+		}
+		if len(args) == len(fn.kinds) {
+			return Affine2D64{}, sc.errorf("expected ')'")
+		}
+		v, err := sc.quantity(fn.kinds[len(args)])
+		if err != nil {
+			return Affine2D64{}, err
+		}
+		args = append(args, v)
+	}
+	valid := false
+	for _, n := range fn.counts {
+		valid = valid || len(args) == n
+	}
+	if !valid {
+		sc.pos = start
+		return Affine2D64{}, sc.errorf("wrong number of arguments to %s", name)
+	}
+	// Fill in the defaults of optional arguments.
+	switch name {
+	case "scale":
+		if len(args) == 1 {
+			args = append(args, args[0])
+		}
+	case "translate", "skew":
+		if len(args) == 1 {
+			args = append(args, 0)
+		}
+	case "rotate":
+		if len(args) == 1 {
+			args = append(args, 0, 0)
+		}
+	}
+	return fn.build(args), nil
+}
+
+// quantity parses a number followed by an optional unit valid for the kind,
+// and converts angles to radians.
+func (sc *textScanner) quantity(kind unitKind) (float64, error) {
+	v, err := sc.number()
+	if err != nil {
+		return 0, err
+	}
+	start := sc.pos
+	for sc.pos < len(sc.s) && isLetter(sc.s[sc.pos]) {
+		sc.pos++
+	}
+	unit := sc.s[start:sc.pos]
+	switch {
+	case unit == "":
+		if kind == unitAngle {
+			v *= math.Pi / 180
+		}
+		return v, nil
+	case kind == unitLength && unit == "px":
+		return v, nil
+	case kind == unitAngle:
+		switch unit {
+		case "deg":
+			return v * math.Pi / 180, nil
+		case "rad":
+			return v, nil
+		case "grad":
+			return v * math.Pi / 200, nil
+		case "turn":
+			return v * 2 * math.Pi, nil
+		}
+	}
+	sc.pos = start
+	return 0, sc.errorf("invalid unit %q", unit)
+}
+
+// SVGMatrix formats the transformation as the SVG and CSS function matrix(a,b,c,d,e,f),
+// which ParseTransform reads back. Elements are rounded to precision decimal places;
+// a negative precision uses the fewest digits that represent every element exactly.
+func (a Affine2D) SVGMatrix(precision int) string {
+	sx, hx, ox, hy, sy, oy := a.Elems()
+	buf := []byte("matrix(")
+	for k, v := range [6]float32{sx, hy, hx, sy, ox, oy} {
+		if k > 0 {
+			buf = append(buf, ',')
+		}
+		buf = appendNumber(buf, float64(v), precision)
+	}
+	return string(append(buf, ')'))
+}
+
+// SVGTransform formats the transformation as a compact SVG transform list of the form
+// translate(tx ty) rotate(deg) skewX(deg) scale(sx sy), following Decompose. Components
+// that do not alter the transformation at the precision are omitted, arguments equal to
+// their defaults are dropped, and the identity yields an empty string. Values are rounded
+// to precision decimal places; a negative precision uses the fewest digits that represent
+// every float32 component exactly.
+func (a Affine2D) SVGTransform(precision int) string {
+	d := a.Decompose()
+	var parts []string
+	number := func(v float32) string {
+		return string(appendNumber(nil, float64(v), precision))
+	}
+	degrees := func(v float32) string {
+		return number(float32(float64(v) * 180 / math.Pi))
+	}
+	if tx, ty := number(d.Translation.X), number(d.Translation.Y); ty != "0" {
+		parts = append(parts, "translate("+tx+" "+ty+")")
+	} else if tx != "0" {
+		parts = append(parts, "translate("+tx+")")
+	}
+	if r := degrees(d.Rotation); r != "0" {
+		parts = append(parts, "rotate("+r+")")
+	}
+	if k := degrees(d.Skew); k != "0" {
+		parts = append(parts, "skewX("+k+")")
+	}
+	if sx, sy := number(d.Scale.X), number(d.Scale.Y); sx != sy {
+		parts = append(parts, "scale("+sx+" "+sy+")")
+	} else if sx != "1" {
+		parts = append(parts, "scale("+sx+")")
+	}
+	return strings.Join(parts, " ")
+}
+
+// isLetter reports whether c is an ASCII letter.
+func isLetter(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+// isSpaceRune reports whether r is whitespace in SVG and CSS syntax.
+func isSpaceRune(r rune) bool {
+	return r < 0x80 && isSpace(byte(r))
+}
@@ -0,0 +1,126 @@
+package tochka
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+// TestParseTransform tests every transform function and their composition order.
+func TestParseTransform(t *testing.T) {
+	tests := []struct {
+		s    string
+		want Affine2D
+	}{
+		{"", Affine2D{}},
+		{" none ", Affine2D{}},
+		{"matrix(1 2 3 4 5 6)", NewAffine2D(1, 3, 5, 2, 4, 6)},
+		{"translate(10)", NewAffine2D(1, 0, 10, 0, 1, 0)},
+		{"translate(10px, -2.5px)", NewAffine2D(1, 0, 10, 0, 1, -2.5)},
+		{"translateX(3) translateY(4)", NewAffine2D(1, 0, 3, 0, 1, 4)},
+		{"scale(2)", NewAffine2D(2, 0, 0, 0, 2, 0)},
+		{"scale(2,3)", NewAffine2D(2, 0, 0, 0, 3, 0)},
+		{"scaleX(2)scaleY(3)", NewAffine2D(2, 0, 0, 0, 3, 0)},
+		{"rotate(90)", Affine2D{}.Rotate(Point{}, math.Pi/2)},
+		{"rotate(0.25turn)", Affine2D{}.Rotate(Point{}, math.Pi/2)},
+		{"rotate(100grad)", Affine2D{}.Rotate(Point{}, math.Pi/2)},
+		{"rotate(1.5707963rad)", Affine2D{}.Rotate(Point{}, math.Pi/2)},
+		{"rotate(90 10 20)", Affine2D{}.Rotate(Point{X: 10, Y: 20}, math.Pi/2)},
+		{"skewX(45)", NewAffine2D(1, 1, 0, 0, 1, 0)},
+		{"skewY(45deg)", NewAffine2D(1, 0, 0, 1, 1, 0)},
+		{"skew(45deg, 45deg)", NewAffine2D(1, 1, 0, 1, 1, 0)},
+		// The rightmost function applies first.
+		{"translate(10, 0) , scale(2)", NewAffine2D(2, 0, 10, 0, 2, 0)},
+		{"scale(2) translate(10 0)", NewAffine2D(2, 0, 20, 0, 2, 0)},
+	}
+	for _, tt := range tests {
+		got, err := ParseTransform(tt.s)
+		if err != nil {
+			t.Errorf("ParseTransform(%q) error = %v", tt.s, err)
+			continue
+		}
+		if !affineAlmostEqual(got, tt.want, 1e-6) {
+			t.Errorf("ParseTransform(%q) = %v; want %v", tt.s, got, tt.want)
+		}
+	}
+	// rotate(a, cx, cy) keeps the center fixed.
+	a, _ := ParseTransform("rotate(30, 7, -3)")
+	if got := a.Transform(Point{X: 7, Y: -3}); !pointAlmostEqual(got, Point{X: 7, Y: -3}, 1e-6) {
+		t.Errorf("rotate about (7, -3) moves the center to %v", got)
+	}
+}
+
+// TestParseTransform_Errors tests that malformed transform lists are reported at the right offset.
+func TestParseTransform_Errors(t *testing.T) {
+	tests := []struct {
+		s      string
+		offset int
+	}{
+		{"rotate", 6},
+		{"rotate(", 7},
+		{"rotate(30", 9},
+		{"rotate(30,)", 10},
+		{"rotate(30 1)", 0},
+		{"scale(1 2 3)", 10},
+		{"spin(3)", 0},
+		{"translate(1em)", 11},
+		{"scale(2deg)", 7},
+		{"scale(2),", 9},
+		{"scale(2) 5", 9},
+	}
+	for _, tt := range tests {
+		_, err := ParseTransform(tt.s)
+		var perr *ParseError
+		if !errors.As(err, &perr) {
+			t.Errorf("ParseTransform(%q) error = %v; want a *ParseError", tt.s, err)
+			continue
+		}
+		if perr.Offset != tt.offset {
+			t.Errorf("ParseTransform(%q) error = %v; want offset %d", tt.s, err, tt.offset)
+		}
+	}
+}
+
+// TestAffine2D_SVGFormat tests the matrix and decomposed forms of simple transformations.
+func TestAffine2D_SVGFormat(t *testing.T) {
+	tests := []struct {
+		a                 Affine2D
+		matrix, transform string
+	}{
+		{Affine2D{}, "matrix(1,0,0,1,0,0)", ""},
+		{NewAffine2D(1, 0, 10, 0, 1, 0), "matrix(1,0,0,1,10,0)", "translate(10)"},
+		{NewAffine2D(2, 0, 10, 0, 2, -0.5), "matrix(2,0,0,2,10,-.5)", "translate(10 -.5) scale(2)"},
+		{Affine2D{}.Rotate(Point{}, math.Pi/2), "matrix(0,1,-1,0,0,0)", "rotate(90)"},
+		{NewAffine2D(1, 1, 0, 0, 1, 0), "matrix(1,0,1,1,0,0)", "skewX(45)"},
+		{NewAffine2D(1, 0, 0, 0, -1, 0), "matrix(1,0,0,-1,0,0)", "scale(1 -1)"},
+	}
+	for _, tt := range tests {
+		if got := tt.a.SVGMatrix(4); got != tt.matrix {
+			t.Errorf("SVGMatrix() of %v = %q; want %q", tt.a, got, tt.matrix)
+		}
+		if got := tt.a.SVGTransform(4); got != tt.transform {
+			t.Errorf("SVGTransform() of %v = %q; want %q", tt.a, got, tt.transform)
+		}
+	}
+}
+
+// TestAffine2D_SVGRoundTrip tests that both formats parse back to the original transformation.
+func TestAffine2D_SVGRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	for range 200 {
+		a := NewAffine2D(
+			r.Float32()*4-2, r.Float32()*4-2, r.Float32()*200-100,
+			r.Float32()*4-2, r.Float32()*4-2, r.Float32()*200-100,
+		)
+		for _, s := range []string{a.SVGMatrix(-1), a.SVGTransform(-1)} {
+			got, err := ParseTransform(s)
+			if err != nil {
+				t.Fatalf("ParseTransform(%q) error = %v", s, err)
+			}
+			if !affineAlmostEqual(got, a, 1e-4) {
+				t.Errorf("ParseTransform(%q) = %v; want %v", s, got, a)
+			}
+		}
+	}
+}
@@ -0,0 +1,425 @@
+package tochka
+
+import (
+	"cmp"
+	"math"
+	"slices"
+)
+
+// earNode is a vertex of the circular doubly linked list used by ear clipping.
+type earNode struct {
+	// i is the index of the vertex in the concatenated rings of the polygon.
+	i int
+	p Point
+	// prev and next link the vertices of the current outline.
+	prev, next *earNode
+	// steiner marks a vertex from a single-point hole, which is kept even if collinear.
+	steiner bool
+}
+
+// earClipper accumulates the triangles of a polygon.
+type earClipper struct {
+	indices []int
+}
+
+// Triangulate splits the polygon into triangles by ear clipping and returns their vertex
+// indices, three per triangle. Index k refers to the k-th vertex of the rings of the
+// polygon concatenated in order: the outer ring followed by every hole. Triangles are
+// counterclockwise regardless of the winding of the rings.
+//
+// Holes are joined to the outer ring by bridges before clipping. Duplicate vertices are
+// ignored, collinear vertices yield no degenerate triangles, a hole with a single vertex
+// adds that vertex to the mesh, and self-touching and mildly self-intersecting rings are
+// handled on a best-effort basis. Rings with fewer than three vertices produce no
+// triangles. The running time is quadratic in the number of vertices in the worst case.
+//
+// TriangulationDeviation measures how well the triangles cover the polygon.
+func (p Polygon) Triangulate() []int {
+	outer := linkRing(p.Outer, 0, CounterClockwise)
+	if outer == nil || outer.next == outer.prev {
+		return nil
+	}
+	if len(p.Holes) > 0 {
+		outer = eliminateHoles(p.Holes, len(p.Outer), outer)
+	}
+	var ec earClipper
+	ec.clip(outer, 0)
+	return ec.indices
+}
+
+// TriangulationDeviation returns the relative difference between the area of the polygon
+// and the total area of the triangles given by indices, as returned by Triangulate. It is
+// zero for a perfect triangulation, and grows when triangles are missing or overlap.
+// The result is 0 if both areas are zero.
+func (p Polygon) TriangulationDeviation(indices []int) float32 {
+	vertices := slices.Concat(append([]Ring{p.Outer}, p.Holes...)...)
+	polygonArea := math.Abs(p.Outer.signedArea64())
+	for _, h := range p.Holes {
+		polygonArea -= math.Abs(h.signedArea64())
+	}
+	var trianglesArea float64
+	for k := 0; k+2 < len(indices); k += 3 {
+		a, b, c := vertices[indices[k]].To64(), vertices[indices[k+1]].To64(), vertices[indices[k+2]].To64()
+		trianglesArea += math.Abs(b.Sub(a).Cross(c.Sub(a))) / 2
+	}
+	if polygonArea == 0 && trianglesArea == 0 {
+		return 0
+	}
+	return float32(math.Abs(polygonArea-trianglesArea) / math.Abs(polygonArea))
+}
+
+// linkRing creates a circular list of the ring vertices wound in the direction o, with
+// indices starting at offset. It returns nil for an empty ring.
+func linkRing(r Ring, offset int, o Orientation) *earNode {
+	var last *earNode
+	insert := func(k int) {
+		n := &earNode{i: offset + k, p: r[k]}
+		if last == nil {
+			n.prev, n.next = n, n
+		} else {
+			n.next, n.prev = last.next, last
+			last.next.prev = n
+			last.next = n
+		}
+		last = n
+	}
+	if ccw := r.signedArea64() > 0; ccw == (o == CounterClockwise) {
+		for k := range r {
+			insert(k)
+		}
+	} else {
+		for k := len(r) - 1; k >= 0; k-- {
+			insert(k)
+		}
+	}
+	// Drop a closing vertex that repeats the first one.
+	if last != nil && last.p == last.next.p && last != last.next {
+		next := last.next
+		last.remove()
+		last = next
+	}
+	return last
+}
+
+// clip cuts ears from the outline starting at ear. When no ear can be found, it retries
+// after removing degenerate vertices, then after curing local self-intersections, and
+// finally by splitting the outline in two.
+func (ec *earClipper) clip(ear *earNode, pass int) {
+	if ear == nil {
+		return
+	}
+	stop := ear
+	for ear.prev != ear.next {
+		prev, next := ear.prev, ear.next
+		if ear.isEar() {
+			ec.indices = append(ec.indices, prev.i, ear.i, next.i)
+			ear.remove()
+			// Skipping the next vertex avoids fans of sliver triangles.
+			ear, stop = next.next, next.next
+			continue
+		}
+		ear = next
+		if ear == stop {
+			switch pass {
+			case 0:
+				ec.clip(filterPoints(ear, nil), 1)
+			case 1:
+				ec.clip(ec.cureLocalIntersections(filterPoints(ear, nil)), 2)
+			case 2:
+				ec.split(ear)
+			}
+			return
+		}
+	}
+}
+
+// isEar reports whether the triangle of the vertex and its neighbours is convex and
+// contains no other reflex vertex of the outline.
+func (ear *earNode) isEar() bool {
+	a, b, c := ear.prev, ear, ear.next
+	if Orient2D(a.p, b.p, c.p) != CounterClockwise {
+		return false
+	}
+	lo := Point{X: min(a.p.X, b.p.X, c.p.X), Y: min(a.p.Y, b.p.Y, c.p.Y)}
+	hi := Point{X: max(a.p.X, b.p.X, c.p.X), Y: max(a.p.Y, b.p.Y, c.p.Y)}
+	for p := c.next; p != a; p = p.next {
+		// A copy of a left by a bridge does not block the ear.
+		if p.p != a.p && inBox(lo, hi, p.p) && inTriangle(a.p, b.p, c.p, p.p) &&
+			Orient2D(p.prev.p, p.p, p.next.p) != CounterClockwise {
+			return false
+		}
+	}
+	return true
+}
+
+// cureLocalIntersections cuts the triangles of vertex pairs whose edges cross, which
+// occur where an outline touches itself.
+func (ec *earClipper) cureLocalIntersections(start *earNode) *earNode {
+	p := start
+	for {
+		a, b := p.prev, p.next.next
+		if a.p != b.p && segmentsIntersect(a.p, p.p, p.next.p, b.p) && locallyInside(a, b) && locallyInside(b, a) {
+			ec.indices = append(ec.indices, a.i, p.i, b.i)
+			p.next.remove()
+			p.remove()
+			p, start = b, b
+		}
+		p = p.next
+		if p == start {
+			return filterPoints(p, nil)
+		}
+	}
+}
+
+// split divides the outline along a valid diagonal and clips both parts.
+func (ec *earClipper) split(start *earNode) {
+	a := start
+	for {
+		for b := a.next.next; b != a.prev; b = b.next {
+			if a.i != b.i && isValidDiagonal(a, b) {
+				c := splitPolygon(a, b)
+				a = filterPoints(a, a.next)
+				c = filterPoints(c, c.next)
+				ec.clip(a, 0)
+				ec.clip(c, 0)
+				return
+			}
+		}
+		a = a.next
+		if a == start {
+			return
+		}
+	}
+}
+
+// eliminateHoles links every hole into the outer outline with a bridge, from the
+// leftmost hole to the rightmost, and returns the merged outline.
+func eliminateHoles(holes []Ring, offset int, outer *earNode) *earNode {
+	var queue []*earNode
+	for _, h := range holes {
+		list := linkRing(h, offset, Clockwise)
+		offset += len(h)
+		if list == nil {
+			continue
+		}
+		if list == list.next {
+			list.steiner = true
+		}
+		queue = append(queue, leftmost(list))
+	}
+	slices.SortFunc(queue, func(a, b *earNode) int {
+		return cmp.Or(cmp.Compare(a.p.X, b.p.X), cmp.Compare(a.p.Y, b.p.Y))
+	})
+	for _, h := range queue {
+		if bridge := findHoleBridge(h, outer); bridge != nil {
+			reverse := splitPolygon(bridge, h)
+			filterPoints(reverse, reverse.next)
+			outer = filterPoints(bridge, bridge.next)
+		}
+	}
+	return outer
+}
+
+// findHoleBridge returns a vertex of the outer outline that can be connected to the
+// leftmost vertex of a hole without crossing any edge, or nil if there is none.
+func findHoleBridge(hole, outer *earNode) *earNode {
+	h := hole.p
+	hx, hy := float64(h.X), float64(h.Y)
+	qx := math.Inf(-1)
+	var m *earNode
+	// Find the nearest edge crossed by a ray from the hole vertex to the left.
+	p := outer
+	for {
+		py, ny := float64(p.p.Y), float64(p.next.p.Y)
+		if hy <= py && hy >= ny && ny != py {
+			px, nx := float64(p.p.X), float64(p.next.p.X)
+			x := px + (hy-py)*(nx-px)/(ny-py)
+			if x <= hx && x > qx {
+				qx = x
+				m = p
+				if p.next.p.X < p.p.X {
+					m = p.next
+				}
+				if x == hx {
+					// The hole touches the edge, so its endpoint is visible.
+					return m
+				}
+			}
+		}
+		p = p.next
+		if p == outer {
+			break
+		}
+	}
+	if m == nil {
+		return nil
+	}
+
+	// The edge endpoint is visible unless a vertex lies inside the triangle formed by the
+	// hole vertex, the crossing and the endpoint. Otherwise the vertex in that triangle
+	// with the smallest angle to the ray is visible.
+	q := Point{X: float32(qx), Y: h.Y}
+	a, c := q, h
+	if hy < float64(m.p.Y) {
+		a, c = h, q
+	}
+	stop, mp := m, m.p
+	tanMin := math.Inf(1)
+	for p := m; ; {
+		if h.X >= p.p.X && p.p.X >= mp.X && h.X != p.p.X && inTriangle(a, mp, c, p.p) {
+			tan := math.Abs(hy-float64(p.p.Y)) / (hx - float64(p.p.X))
+			if locallyInside(p, hole) &&
+				(tan < tanMin || tan == tanMin && (p.p.X > m.p.X || p.p.X == m.p.X && sectorContainsSector(m, p))) {
+				m, tanMin = p, tan
+			}
+		}
+		p = p.next
+		if p == stop {
+			break
+		}
+	}
+	return m
+}
+
+// sectorContainsSector reports whether the sector of vertex m contains the sector of p,
+// which decides between bridges from coincident vertices.
+func sectorContainsSector(m, p *earNode) bool {
+	return Orient2D(m.prev.p, m.p, p.prev.p) == CounterClockwise && Orient2D(p.next.p, m.p, m.next.p) == CounterClockwise
+}
+
+// leftmost returns the vertex of the outline with the smallest X, and smallest Y among those.
+func leftmost(start *earNode) *earNode {
+	best := start
+	for p := start.next; p != start; p = p.next {
+		if p.p.X < best.p.X || p.p.X == best.p.X && p.p.Y < best.p.Y {
+			best = p
+		}
+	}
+	return best
+}
+
+// filterPoints removes duplicate and collinear vertices from the outline between start
+// and end, or the whole outline if end is nil, and returns a remaining vertex.
+func filterPoints(start, end *earNode) *earNode {
+	if start == nil {
+		return nil
+	}
+	if end == nil {
+		end = start
+	}
+	p := start
+	for {
+		again := false
+		if !p.steiner && (p.p == p.next.p || Orient2D(p.prev.p, p.p, p.next.p) == Collinear) {
+			p.remove()
+			p, end = p.prev, p.prev
+			if p == p.next {
+				break
+			}
+			again = true
+		} else {
+			p = p.next
+		}
+		if !again && p == end {
+			break
+		}
+	}
+	return end
+}
+
+// isValidDiagonal reports whether the segment between a and b lies inside the outline
+// without crossing it.
+func isValidDiagonal(a, b *earNode) bool {
+	if a.next.i == b.i || a.prev.i == b.i || intersectsOutline(a, b) {
+		return false
+	}
+	if locallyInside(a, b) && locallyInside(b, a) && middleInside(a, b) &&
+		// The diagonal must not create sectors facing away from each other.
+		(Orient2D(a.prev.p, a.p, b.prev.p) != Collinear || Orient2D(a.p, b.prev.p, b.p) != Collinear) {
+		return true
+	}
+	// A zero-length diagonal joins two convex vertices at the same position.
+	return a.p == b.p && Orient2D(a.prev.p, a.p, a.next.p) == Clockwise && Orient2D(b.prev.p, b.p, b.next.p) == Clockwise
+}
+
+// intersectsOutline reports whether the segment between a and b crosses an edge of the
+// outline that does not end at a or b.
+func intersectsOutline(a, b *earNode) bool {
+	p := a
+	for {
+		if p.i != a.i && p.next.i != a.i && p.i != b.i && p.next.i != b.i && segmentsIntersect(p.p, p.next.p, a.p, b.p) {
+			return true
+		}
+		p = p.next
+		if p == a {
+			return false
+		}
+	}
+}
+
+// locallyInside reports whether the segment from a towards b starts inside the outline.
+func locallyInside(a, b *earNode) bool {
+	if Orient2D(a.prev.p, a.p, a.next.p) == CounterClockwise {
+		return Orient2D(a.p, b.p, a.next.p) != CounterClockwise && Orient2D(a.p, a.prev.p, b.p) != CounterClockwise
+	}
+	return Orient2D(a.p, b.p, a.prev.p) == CounterClockwise || Orient2D(a.p, a.next.p, b.p) == CounterClockwise
+}
+
+// middleInside reports whether the midpoint of the segment between a and b lies inside the outline.
+func middleInside(a, b *earNode) bool {
+	inside := false
+	px, py := (float64(a.p.X)+float64(b.p.X))/2, (float64(a.p.Y)+float64(b.p.Y))/2
+	p := a
+	for {
+		x0, y0 := float64(p.p.X), float64(p.p.Y)
+		x1, y1 := float64(p.next.p.X), float64(p.next.p.Y)
+		if (y0 > py) != (y1 > py) && y1 != y0 && px < (x1-x0)*(py-y0)/(y1-y0)+x0 {
+			inside = !inside
+		}
+		p = p.next
+		if p == a {
+			return inside
+		}
+	}
+}
+
+// splitPolygon connects a and b with two opposite edges, splitting the outline in two,
+// and returns the copy of b that starts the second outline.
+func splitPolygon(a, b *earNode) *earNode {
+	a2 := &earNode{i: a.i, p: a.p}
+	b2 := &earNode{i: b.i, p: b.p}
+	an, bp := a.next, b.prev
+
+	a.next, b.prev = b, a
+	a2.next, an.prev = an, a2
+	b2.next, a2.prev = a2, b2
+	bp.next, b2.prev = b2, bp
+	return b2
+}
+
+// remove unlinks the vertex from its outline.
+func (n *earNode) remove() {
+	n.next.prev = n.prev
+	n.prev.next = n.next
+}
+
+// inTriangle reports whether p lies inside or on the counterclockwise triangle a, b, c.
+func inTriangle(a, b, c, p Point) bool {
+	return Orient2D(a, b, p) != Clockwise && Orient2D(b, c, p) != Clockwise && Orient2D(c, a, p) != Clockwise
+}
+
+// segmentsIntersect reports whether the closed segments p1q1 and p2q2 share a point.
+func segmentsIntersect(p1, q1, p2, q2 Point) bool {
+	o1, o2 := Orient2D(p1, q1, p2), Orient2D(p1, q1, q2)
+	o3, o4 := Orient2D(p2, q2, p1), Orient2D(p2, q2, q1)
+	if o1 != o2 && o3 != o4 {
+		return true
+	}
+	return o1 == Collinear && inBox(p1, q1, p2) || o2 == Collinear && inBox(p1, q1, q2) ||
+		o3 == Collinear && inBox(p2, q2, p1) || o4 == Collinear && inBox(p2, q2, q1)
+}
+
+// inBox reports whether q lies in the bounding box of p and r.
+func inBox(p, r, q Point) bool {
+	return min(p.X, r.X) <= q.X && q.X <= max(p.X, r.X) && min(p.Y, r.Y) <= q.Y && q.Y <= max(p.Y, r.Y)
+}
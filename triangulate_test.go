@@ -0,0 +1,168 @@
+package tochka
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// checkTriangulation fails the test unless the triangles are counterclockwise, index the
+// polygon vertices and cover it exactly once. A triangulation that uses every vertex has
+// want triangles; collinear vertices may lower the count.
+func checkTriangulation(t *testing.T, name string, p Polygon, indices []int, want int) {
+	t.Helper()
+	if len(indices)%3 != 0 || len(indices) > 3*want || len(indices) < 3*want-6 {
+		t.Fatalf("%s: Triangulate() returned %d indices; want %d triangles", name, len(indices), want)
+	}
+	vertices := slices.Concat(append([]Ring{p.Outer}, p.Holes...)...)
+	for k := 0; k < len(indices); k += 3 {
+		for _, i := range indices[k : k+3] {
+			if i < 0 || i >= len(vertices) {
+				t.Fatalf("%s: index %d is out of range", name, i)
+			}
+		}
+		a, b, c := vertices[indices[k]], vertices[indices[k+1]], vertices[indices[k+2]]
+		if Orient2D(a, b, c) != CounterClockwise {
+			t.Fatalf("%s: triangle %v, %v, %v is not counterclockwise", name, a, b, c)
+		}
+	}
+	if d := p.TriangulationDeviation(indices); d > 1e-6 {
+		t.Fatalf("%s: TriangulationDeviation() = %v; want 0", name, d)
+	}
+	// Sample points off the vertex grid must be covered once inside and never outside.
+	b := p.Bounds()
+	for i := range 23 {
+		for j := range 19 {
+			s := Point{X: b.Min.X + (b.Max.X-b.Min.X)*(float32(i)+0.31)/23, Y: b.Min.Y + (b.Max.Y-b.Min.Y)*(float32(j)+0.57)/19}
+			covers := 0
+			for k := 0; k < len(indices); k += 3 {
+				if inTriangle(vertices[indices[k]], vertices[indices[k+1]], vertices[indices[k+2]], s) {
+					covers++
+				}
+			}
+			want := 0
+			if p.Contains(s, EvenOdd) {
+				want = 1
+			}
+			if covers != want {
+				t.Fatalf("%s: %v is covered by %d triangles; want %d", name, s, covers, want)
+			}
+		}
+	}
+}
+
+// TestPolygon_Triangulate tests simple polygons, holes and degenerate vertices.
+func TestPolygon_Triangulate(t *testing.T) {
+	pt := func(x, y float32) Point { return Point{X: x, Y: y} }
+	hole := Ring{pt(1, 1), pt(1, 3), pt(3, 3), pt(3, 1)}
+	tests := []struct {
+		name      string
+		p         Polygon
+		triangles int
+	}{
+		{"square", Polygon{Outer: square}, 2},
+		{"clockwise square", Polygon{Outer: square.Reverse()}, 2},
+		{"closed square", Polygon{Outer: append(slices.Clone(square), square[0])}, 2},
+		{
+			"collinear and duplicate vertices",
+			Polygon{Outer: Ring{pt(0, 0), pt(2, 0), pt(2, 0), pt(4, 0), pt(4, 2), pt(4, 4), pt(0, 4), pt(0, 4)}},
+			4,
+		},
+		{
+			"concave",
+			Polygon{Outer: Ring{pt(0, 0), pt(4, 0), pt(4, 4), pt(2, 1), pt(0, 4)}},
+			3,
+		},
+		{"square hole", Polygon{Outer: square, Holes: []Ring{hole}}, 8},
+		{"counterclockwise hole", Polygon{Outer: square, Holes: []Ring{hole.Reverse()}}, 8},
+		{
+			"two holes",
+			Polygon{
+				Outer: Ring{pt(0, 0), pt(8, 0), pt(8, 4), pt(0, 4)},
+				Holes: []Ring{hole, {pt(5, 1), pt(7, 1), pt(6, 3)}},
+			},
+			13,
+		},
+		{
+			"hole touching the outer ring",
+			Polygon{Outer: square, Holes: []Ring{{pt(0, 2), pt(2, 3), pt(2, 1)}}},
+			7,
+		},
+		{
+			"hole sharing a vertex with the outer ring",
+			Polygon{Outer: square, Holes: []Ring{{pt(0, 0), pt(2, 1), pt(1, 2)}}},
+			7,
+		},
+	}
+	for _, tt := range tests {
+		checkTriangulation(t, tt.name, tt.p, tt.p.Triangulate(), tt.triangles)
+	}
+
+	// A hole with a single vertex adds it to the mesh.
+	p := Polygon{Outer: square, Holes: []Ring{{pt(1, 2)}}}
+	indices := p.Triangulate()
+	checkTriangulation(t, "steiner point", p, indices, 4)
+	if !slices.Contains(indices, 4) {
+		t.Errorf("steiner point: triangles %v do not use the point", indices)
+	}
+
+	for _, p := range []Polygon{
+		{},
+		{Outer: Ring{pt(0, 0), pt(1, 1)}},
+		{Outer: Ring{pt(0, 0), pt(1, 1), pt(2, 2), pt(3, 3)}},
+		{Outer: Ring{pt(1, 1), pt(1, 1), pt(1, 1)}},
+	} {
+		if got := p.Triangulate(); len(got) != 0 {
+			t.Errorf("Triangulate() of degenerate %v = %v; want no triangles", p.Outer, got)
+		}
+	}
+}
+
+// TestPolygon_Triangulate_Random tests star-shaped polygons with many vertices and a
+// grid of small polygonal holes.
+func TestPolygon_Triangulate_Random(t *testing.T) {
+	r := rand.New(rand.NewPCG(9, 10))
+	ngon := func(cx, cy, radius float64, n int, jitter float64) Ring {
+		ring := make(Ring, n)
+		for k := range ring {
+			s, c := math.Sincos(2 * math.Pi * float64(k) / float64(n))
+			d := radius * (1 - jitter*r.Float64())
+			ring[k] = Point{X: float32(cx + c*d), Y: float32(cy + s*d)}
+		}
+		return ring
+	}
+	for range 50 {
+		var p Polygon
+		p.Outer = ngon(0, 0, 10, 20+r.IntN(200), 0.3)
+		for x := -4; x <= 4; x += 2 {
+			for y := -4; y <= 4; y += 2 {
+				if r.IntN(2) == 0 {
+					p.Holes = append(p.Holes, ngon(float64(x), float64(y), 0.8, 3+r.IntN(8), 0.5))
+				}
+			}
+		}
+		n := len(p.Outer)
+		for _, h := range p.Holes {
+			n += len(h)
+		}
+		checkTriangulation(t, "random", p, p.Triangulate(), n+2*len(p.Holes)-2)
+	}
+}
+
+// TestPolygon_TriangulationDeviation tests that missing and overlapping triangles are detected.
+func TestPolygon_TriangulationDeviation(t *testing.T) {
+	p := Polygon{Outer: square}
+	if got := p.TriangulationDeviation([]int{0, 1, 2, 0, 2, 3}); got != 0 {
+		t.Errorf("TriangulationDeviation() of a perfect triangulation = %v; want 0", got)
+	}
+	if got := p.TriangulationDeviation([]int{0, 1, 2}); got != 0.5 {
+		t.Errorf("TriangulationDeviation() with a missing triangle = %v; want 0.5", got)
+	}
+	if got := p.TriangulationDeviation([]int{0, 1, 2, 0, 2, 3, 1, 2, 3}); got != 0.5 {
+		t.Errorf("TriangulationDeviation() with an overlapping triangle = %v; want 0.5", got)
+	}
+	if got := (Polygon{}).TriangulationDeviation(nil); got != 0 {
+		t.Errorf("TriangulationDeviation() of an empty polygon = %v; want 0", got)
+	}
+}
@@ -0,0 +1,364 @@
+package tochka
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// Geometry type codes of WKB.
+const (
+	wkbPoint              = 1
+	wkbLineString         = 2
+	wkbPolygon            = 3
+	wkbMultiPoint         = 4
+	wkbMultiLineString    = 5
+	wkbMultiPolygon       = 6
+	wkbGeometryCollection = 7
+)
+
+// Flags of the geometry type in the extended WKB of PostGIS.
+const (
+	ewkbZ    = 0x80000000
+	ewkbM    = 0x40000000
+	ewkbSRID = 0x20000000
+)
+
+// maxWKBDepth limits the nesting of geometry collections, so that malicious input
+// cannot exhaust the stack.
+const maxWKBDepth = 64
+
+// wkbCodes maps geometry type names to their WKB codes.
+var wkbCodes = map[string]uint32{
+	"POINT":              wkbPoint,
+	"LINESTRING":         wkbLineString,
+	"POLYGON":            wkbPolygon,
+	"MULTIPOINT":         wkbMultiPoint,
+	"MULTILINESTRING":    wkbMultiLineString,
+	"MULTIPOLYGON":       wkbMultiPolygon,
+	"GEOMETRYCOLLECTION": wkbGeometryCollection,
+}
+
+// AppendWKB appends the two-dimensional Well-Known Binary of a geometry to dst in
+// little-endian byte order. Polygon rings are closed by repeating their first vertex,
+// EmptyPoint is written with NaN coordinates, and a nil Geometry is written as an
+// empty geometry collection.
+func AppendWKB(dst []byte, g Geometry) []byte {
+	return appendWKB(dst, g, 0, false)
+}
+
+// AppendEWKB appends the geometry like AppendWKB, but in the extended WKB of PostGIS
+// with an SRID header identifying the spatial reference system.
+func AppendEWKB(dst []byte, g Geometry, srid int32) []byte {
+	return appendWKB(dst, g, srid, true)
+}
+
+// appendWKB appends a geometry with an optional SRID header.
+func appendWKB(dst []byte, g Geometry, srid int32, withSRID bool) []byte {
+	if g == nil {
+		g = GeometryCollection(nil)
+	}
+	code := wkbCodes[g.geometryType()]
+	if withSRID {
+		code |= ewkbSRID
+	}
+	dst = binary.LittleEndian.AppendUint32(append(dst, 1), code)
+	if withSRID {
+		dst = binary.LittleEndian.AppendUint32(dst, uint32(srid))
+	}
+	switch g := g.(type) {
+	case Point:
+		return appendWKBCoord(dst, g)
+	case LineString:
+		return appendWKBPoints(dst, g)
+	case Polygon:
+		return appendWKBPolygon(dst, g)
+	case MultiPoint:
+		dst = binary.LittleEndian.AppendUint32(dst, uint32(len(g)))
+		for _, p := range g {
+			dst = appendWKB(dst, p, 0, false)
+		}
+	case MultiLineString:
+		dst = binary.LittleEndian.AppendUint32(dst, uint32(len(g)))
+		for _, l := range g {
+			dst = appendWKB(dst, l, 0, false)
+		}
+	case MultiPolygon:
+		dst = binary.LittleEndian.AppendUint32(dst, uint32(len(g)))
+		for _, p := range g {
+			dst = appendWKB(dst, p, 0, false)
+		}
+	case GeometryCollection:
+		dst = binary.LittleEndian.AppendUint32(dst, uint32(len(g)))
+		for _, h := range g {
+			dst = appendWKB(dst, h, 0, false)
+		}
+	}
+	return dst
+}
+
+// appendWKBCoord appends the X and Y values of a coordinate.
+func appendWKBCoord(dst []byte, p Point) []byte {
+	dst = binary.LittleEndian.AppendUint64(dst, math.Float64bits(float64(p.X)))
+	return binary.LittleEndian.AppendUint64(dst, math.Float64bits(float64(p.Y)))
+}
+
+// appendWKBPoints appends a counted list of coordinates.
+func appendWKBPoints(dst []byte, pts []Point) []byte {
+	dst = binary.LittleEndian.AppendUint32(dst, uint32(len(pts)))
+	for _, p := range pts {
+		dst = appendWKBCoord(dst, p)
+	}
+	return dst
+}
+
+// appendWKBPolygon appends the closed rings of a polygon.
+func appendWKBPolygon(dst []byte, poly Polygon) []byte {
+	if len(poly.Outer) == 0 && len(poly.Holes) == 0 {
+		return binary.LittleEndian.AppendUint32(dst, 0)
+	}
+	dst = binary.LittleEndian.AppendUint32(dst, uint32(1+len(poly.Holes)))
+	dst = appendWKBPoints(dst, closeRing(poly.Outer))
+	for _, h := range poly.Holes {
+		dst = appendWKBPoints(dst, closeRing(h))
+	}
+	return dst
+}
+
+// wkbReader reads Well-Known Binary.
+type wkbReader struct {
+	data  []byte
+	pos   int
+	order binary.ByteOrder
+}
+
+// DecodeWKB decodes a geometry from Well-Known Binary. Both byte orders are accepted,
+// as are the Z, M and ZM types of ISO WKB and of the extended WKB of PostGIS, whose
+// extra values are discarded. The SRID of an extended WKB header is returned, or 0 if
+// there is none. Polygon rings must be closed and have at least four vertices; the
+// closing vertex is removed, as in Ring. The data must hold exactly one geometry.
+//
+// Malformed input yields a *ParseError with the byte offset of the problem.
+func DecodeWKB(data []byte) (g Geometry, srid int32, err error) {
+	r := wkbReader{data: data}
+	g, srid, err = r.geometry(0)
+	if err != nil {
+		return nil, 0, err
+	}
+	if r.pos != len(data) {
+		return nil, 0, r.errorf("unexpected data after geometry")
+	}
+	return g, srid, nil
+}
+
+// geometry reads a geometry with its header at the given nesting depth.
+func (r *wkbReader) geometry(depth int) (Geometry, int32, error) {
+	if depth > maxWKBDepth {
+		return nil, 0, r.errorf("geometry nested too deeply")
+	}
+	start := r.pos
+	if err := r.need(5); err != nil {
+		return nil, 0, err
+	}
+	switch r.data[r.pos] {
+	case 0:
+		r.order = binary.BigEndian
+	case 1:
+		r.order = binary.LittleEndian
+	default:
+		return nil, 0, r.errorf("invalid byte order %d", r.data[r.pos])
+	}
+	r.pos++
+	t := r.order.Uint32(r.data[r.pos:])
+	dims := 2
+	if t&ewkbZ != 0 {
+		dims++
+	}
+	if t&ewkbM != 0 {
+		dims++
+	}
+	code := t &^ (ewkbZ | ewkbM | ewkbSRID)
+	base := code % 1000
+	// The dimension is given either by the flags of EWKB or by the code of ISO WKB, not both.
+	switch code / 1000 {
+	case 0:
+	case 1, 2:
+		if dims > 2 {
+			base = 0
+		}
+		dims++
+	case 3:
+		if dims > 2 {
+			base = 0
+		}
+		dims += 2
+	default:
+		base = 0
+	}
+	if base < wkbPoint || base > wkbGeometryCollection || dims > 4 {
+		return nil, 0, r.errorf("unknown geometry type %d", t)
+	}
+	r.pos += 4
+
+	var srid int32
+	if t&ewkbSRID != 0 {
+		if depth > 0 {
+			r.pos = start
+			return nil, 0, r.errorf("SRID in nested geometry")
+		}
+		if err := r.need(4); err != nil {
+			return nil, 0, err
+		}
+		srid = int32(r.order.Uint32(r.data[r.pos:]))
+		r.pos += 4
+	}
+
+	var g Geometry
+	var err error
+	switch base {
+	case wkbPoint:
+		g, err = r.coord(dims)
+	case wkbLineString:
+		var pts []Point
+		pts, err = r.points(dims)
+		g = LineString(pts)
+	case wkbPolygon:
+		g, err = r.polygon(dims)
+	default:
+		g, err = r.collection(base, depth)
+	}
+	return g, srid, err
+}
+
+// collection reads the members of a multi-geometry or geometry collection.
+func (r *wkbReader) collection(base uint32, depth int) (Geometry, error) {
+	// Every member takes at least a header and a count.
+	n, err := r.count(9)
+	if err != nil {
+		return nil, err
+	}
+	var g Geometry
+	switch base {
+	case wkbMultiPoint:
+		g = make(MultiPoint, 0, n)
+	case wkbMultiLineString:
+		g = make(MultiLineString, 0, n)
+	case wkbMultiPolygon:
+		g = make(MultiPolygon, 0, n)
+	default:
+		g = make(GeometryCollection, 0, n)
+	}
+	for range n {
+		start := r.pos
+		h, _, err := r.geometry(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		ok := true
+		switch c := g.(type) {
+		case MultiPoint:
+			p, isPoint := h.(Point)
+			g, ok = append(c, p), isPoint
+		case MultiLineString:
+			l, isLine := h.(LineString)
+			g, ok = append(c, l), isLine
+		case MultiPolygon:
+			p, isPolygon := h.(Polygon)
+			g, ok = append(c, p), isPolygon
+		case GeometryCollection:
+			g = append(c, h)
+		}
+		if !ok {
+			r.pos = start
+			return nil, r.errorf("unexpected %s in %s", h.geometryType(), g.geometryType())
+		}
+	}
+	return g, nil
+}
+
+// polygon reads a counted list of rings.
+func (r *wkbReader) polygon(dims int) (Polygon, error) {
+	n, err := r.count(4)
+	if err != nil {
+		return Polygon{}, err
+	}
+	var poly Polygon
+	for k := range n {
+		start := r.pos
+		pts, err := r.points(dims)
+		if err != nil {
+			return Polygon{}, err
+		}
+		ring, msg := openRing(pts)
+		if msg != "" {
+			r.pos = start
+			return Polygon{}, r.errorf("%s", msg)
+		}
+		if k == 0 {
+			poly.Outer = ring
+		} else {
+			poly.Holes = append(poly.Holes, ring)
+		}
+	}
+	return poly, nil
+}
+
+// points reads a counted list of coordinates.
+func (r *wkbReader) points(dims int) ([]Point, error) {
+	n, err := r.count(8 * dims)
+	if err != nil {
+		return nil, err
+	}
+	pts := make([]Point, n)
+	for k := range pts {
+		if pts[k], err = r.coord(dims); err != nil {
+			return nil, err
+		}
+	}
+	return pts, nil
+}
+
+// coord reads a coordinate of dims values and keeps X and Y.
+func (r *wkbReader) coord(dims int) (Point, error) {
+	if err := r.need(8 * dims); err != nil {
+		return Point{}, err
+	}
+	var v [2]float32
+	for k := range v {
+		x := math.Float64frombits(r.order.Uint64(r.data[r.pos+8*k:]))
+		if math.Abs(x) > math.MaxFloat32 {
+			r.pos += 8 * k
+			return Point{}, r.errorf("coordinate out of range")
+		}
+		v[k] = float32(x)
+	}
+	r.pos += 8 * dims
+	return Point{X: v[0], Y: v[1]}, nil
+}
+
+// count reads an element count and checks that the remaining data can hold that many
+// elements of at least size bytes each.
+func (r *wkbReader) count(size int) (int, error) {
+	if err := r.need(4); err != nil {
+		return 0, err
+	}
+	n := r.order.Uint32(r.data[r.pos:])
+	if uint64(n)*uint64(size) > uint64(len(r.data)-r.pos-4) {
+		return 0, r.errorf("count %d exceeds data", n)
+	}
+	r.pos += 4
+	return int(n), nil
+}
+
+// need checks that n more bytes are available.
+func (r *wkbReader) need(n int) error {
+	if len(r.data)-r.pos < n {
+		return r.errorf("unexpected end of data")
+	}
+	return nil
+}
+
+// errorf returns a *ParseError at the current position.
+func (r *wkbReader) errorf(format string, args ...any) error {
+	return &ParseError{Offset: r.pos, Msg: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,142 @@
+package tochka
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// TestDecodeWKB tests reference encodings in both byte orders, with dimensions and an SRID.
+func TestDecodeWKB(t *testing.T) {
+	tests := []struct {
+		name string
+		hex  string
+		want Geometry
+		srid int32
+	}{
+		{"point NDR", "0101000000000000000000f03f0000000000000040", Point{X: 1, Y: 2}, 0},
+		{"point XDR", "00000000013ff00000000000004000000000000000", Point{X: 1, Y: 2}, 0},
+		{"point ISO Z", "01e9030000000000000000f03f00000000000000400000000000000840", Point{X: 1, Y: 2}, 0},
+		{"point EWKB Z", "0101000080000000000000f03f00000000000000400000000000000840", Point{X: 1, Y: 2}, 0},
+		{"point EWKB SRID", "0101000020e6100000000000000000f03f0000000000000040", Point{X: 1, Y: 2}, 4326},
+		{"point ISO ZM", "01b90b0000000000000000f03f000000000000004000000000000008400000000000001040", Point{X: 1, Y: 2}, 0},
+		{
+			"linestring",
+			"010200000002000000000000000000000000000000000000000000000000000840000000000000f0bf",
+			LineString{{X: 0, Y: 0}, {X: 3, Y: -1}}, 0,
+		},
+		{
+			"multipoint with mixed byte orders",
+			"0104000000020000000101000000000000000000f03f000000000000004000000000014008000000000000c000000000000000",
+			MultiPoint{{X: 1, Y: 2}, {X: 3, Y: -2}}, 0,
+		},
+		{"empty collection", "010700000000000000", GeometryCollection{}, 0},
+	}
+	for _, tt := range tests {
+		data, _ := hex.DecodeString(tt.hex)
+		g, srid, err := DecodeWKB(data)
+		if err != nil {
+			t.Errorf("%s: DecodeWKB() error = %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(g, tt.want) || srid != tt.srid {
+			t.Errorf("%s: DecodeWKB() = %#v, %d; want %#v, %d", tt.name, g, srid, tt.want, tt.srid)
+		}
+	}
+}
+
+// TestDecodeWKB_Errors tests that malformed data is reported at the right offset.
+func TestDecodeWKB_Errors(t *testing.T) {
+	point := "0101000000000000000000f03f0000000000000040"
+	tests := []struct {
+		name   string
+		hex    string
+		offset int
+	}{
+		{"empty", "", 0},
+		{"byte order", "02", 0},
+		{"unknown type", "0108000000", 1},
+		{"ISO and EWKB dimensions", "01e9030080", 1},
+		{"truncated point", "0101000000000000000000f03f", 5},
+		{"trailing data", point + "00", 21},
+		{"huge count", "0102000000ffffff7f", 5},
+		{"open ring", "01030000000100000004000000" +
+			"00000000000000000000000000000000" + "000000000000f03f0000000000000000" +
+			"00000000000000000000000000000040" + "000000000000f03f000000000000f03f", 9},
+		{"member type", "010400000001000000" + "010200000000000000", 9},
+		{"nested SRID", "010700000001000000" + "0101000020e6100000000000000000f03f0000000000000040", 9},
+		{"out of range", "0101000000" + "000000000000f07f" + "0000000000000000", 5},
+	}
+	for _, tt := range tests {
+		data, _ := hex.DecodeString(tt.hex)
+		_, _, err := DecodeWKB(data)
+		var perr *ParseError
+		if !errors.As(err, &perr) {
+			t.Errorf("%s: DecodeWKB() error = %v; want a *ParseError", tt.name, err)
+			continue
+		}
+		if perr.Offset != tt.offset {
+			t.Errorf("%s: DecodeWKB() error = %v; want offset %d", tt.name, err, tt.offset)
+		}
+	}
+}
+
+// TestAppendWKB tests the round trip of every geometry type, with and without an SRID.
+func TestAppendWKB(t *testing.T) {
+	sq := NewPolygon(square, Ring{{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 1, Y: 2}})
+	geoms := []Geometry{
+		Point{X: 1.5, Y: -2},
+		LineString{{X: 0, Y: 0}, {X: 1, Y: 1}},
+		sq,
+		MultiPoint{{X: 1, Y: 2}, {X: 3, Y: 4}},
+		MultiLineString{{{X: 0, Y: 0}, {X: 1, Y: 1}}, {}},
+		MultiPolygon{sq, {}},
+		GeometryCollection{Point{X: 1, Y: 2}, GeometryCollection{sq}},
+	}
+	for _, g := range geoms {
+		for _, srid := range []int32{0, 3857} {
+			var data []byte
+			if srid == 0 {
+				data = AppendWKB(nil, g)
+			} else {
+				data = AppendEWKB(nil, g, srid)
+			}
+			got, gotSRID, err := DecodeWKB(data)
+			if err != nil {
+				t.Fatalf("DecodeWKB(AppendWKB(%#v)) error = %v", g, err)
+			}
+			if FormatWKT(got) != FormatWKT(g) || gotSRID != srid {
+				t.Errorf("round trip = %s, SRID %d; want %s, SRID %d", FormatWKT(got), gotSRID, FormatWKT(g), srid)
+			}
+		}
+	}
+	// The empty point is written with NaN coordinates.
+	want, _ := hex.DecodeString("0101000000000000000000f87f000000000000f87f")
+	if got := AppendWKB(nil, EmptyPoint()); !bytes.Equal(got, want) {
+		t.Errorf("AppendWKB(EmptyPoint()) = %x; want %x", got, want)
+	}
+}
+
+// FuzzDecodeWKB tests that arbitrary input never panics and that decoded geometries
+// survive a round trip.
+func FuzzDecodeWKB(f *testing.F) {
+	f.Add(AppendWKB(nil, NewPolygon(square)))
+	f.Add(AppendEWKB(nil, GeometryCollection{MultiPoint{{X: 1, Y: 2}}, LineString{{X: 3, Y: 4}}}, 4326))
+	data, _ := hex.DecodeString("01b90b0000000000000000f03f000000000000004000000000000008400000000000001040")
+	f.Add(data)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		g, srid, err := DecodeWKB(data)
+		if err != nil {
+			return
+		}
+		again, _, err := DecodeWKB(AppendEWKB(nil, g, srid))
+		if err != nil {
+			t.Fatalf("DecodeWKB() of re-encoded geometry error = %v", err)
+		}
+		if a, b := FormatWKT(again), FormatWKT(g); a != b {
+			t.Errorf("round trip = %s; want %s", a, b)
+		}
+	})
+}
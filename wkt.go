@@ -0,0 +1,348 @@
+package tochka
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// wktParser reads Well-Known Text.
+type wktParser struct {
+	textScanner
+}
+
+// ParseWKT parses a geometry in Well-Known Text, such as "POINT (1 2)" or
+// "POLYGON ((0 0, 4 0, 4 4, 0 0))". Keywords are case-insensitive and every geometry type
+// may be EMPTY. Coordinates with Z and M values are accepted, either tagged as in
+// "POINT ZM (1 2 3 4)" or "POINTZ (1 2 3)", or untagged with three or four values, and
+// the extra values are discarded. Polygon rings must be closed and have at least four
+// vertices; the closing vertex is removed, as in Ring.
+//
+// Malformed input yields a *ParseError locating the problem.
+func ParseWKT(s string) (Geometry, error) {
+	p := wktParser{textScanner{s: s}}
+	p.skipSpace()
+	g, err := p.geometry()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos != len(s) {
+		return nil, p.errorf("unexpected character %q", s[p.pos])
+	}
+	return g, nil
+}
+
+// geometry parses a tagged geometry.
+func (p *wktParser) geometry() (Geometry, error) {
+	start := p.pos
+	name := p.keyword()
+	if name == "" {
+		return nil, p.errorf("expected geometry type")
+	}
+	// The dimension may be attached to the type name, as in POINTZ.
+	dims := 0
+	for _, t := range []string{"POINT", "LINESTRING", "POLYGON", "MULTIPOINT", "MULTILINESTRING", "MULTIPOLYGON", "GEOMETRYCOLLECTION"} {
+		if suffix, ok := strings.CutPrefix(name, t); ok {
+			if d := wktDims(suffix); d != 0 {
+				name, dims = t, d
+			}
+		}
+	}
+	if dims == 0 {
+		p.skipSpace()
+		save := p.pos
+		if d := wktDims(p.keyword()); d != 0 {
+			dims = d
+		} else {
+			p.pos = save
+		}
+	}
+
+	switch name {
+	case "POINT":
+		if p.empty() {
+			return EmptyPoint(), nil
+		}
+		if err := p.expect('('); err != nil {
+			return nil, err
+		}
+		pt, err := p.coord(dims)
+		if err != nil {
+			return nil, err
+		}
+		return pt, p.expect(')')
+	case "LINESTRING":
+		pts, err := p.points(dims)
+		return LineString(pts), err
+	case "POLYGON":
+		return p.polygon(dims)
+	case "MULTIPOINT":
+		var mp MultiPoint
+		err := p.list(func() error {
+			// Points may appear with or without their own parentheses.
+			p.skipSpace()
+			var pt Point
+			var err error
+			switch {
+			case p.empty():
+				pt = EmptyPoint()
+			case p.pos < len(p.s) && p.s[p.pos] == '(':
+				p.pos++
+				if pt, err = p.coord(dims); err == nil {
+					err = p.expect(')')
+				}
+			default:
+				pt, err = p.coord(dims)
+			}
+			mp = append(mp, pt)
+			return err
+		})
+		return mp, err
+	case "MULTILINESTRING":
+		var ml MultiLineString
+		err := p.list(func() error {
+			pts, err := p.points(dims)
+			ml = append(ml, pts)
+			return err
+		})
+		return ml, err
+	case "MULTIPOLYGON":
+		var mp MultiPolygon
+		err := p.list(func() error {
+			poly, err := p.polygon(dims)
+			mp = append(mp, poly)
+			return err
+		})
+		return mp, err
+	case "GEOMETRYCOLLECTION":
+		var gc GeometryCollection
+		err := p.list(func() error {
+			p.skipSpace()
+			g, err := p.geometry()
+			gc = append(gc, g)
+			return err
+		})
+		return gc, err
+	}
+	p.pos = start
+	return nil, p.errorf("unknown geometry type %q", name)
+}
+
+// wktDims returns the number of values per coordinate denoted by a dimension tag,
+// or 0 if s is not a tag.
+func wktDims(s string) int {
+	switch s {
+	case "Z", "M":
+		return 3
+	case "ZM":
+		return 4
+	}
+	return 0
+}
+
+// keyword reads a keyword and returns it in upper case.
+func (p *wktParser) keyword() string {
+	start := p.pos
+	for p.pos < len(p.s) && isLetter(p.s[p.pos]) {
+		p.pos++
+	}
+	return strings.ToUpper(p.s[start:p.pos])
+}
+
+// empty consumes the keyword EMPTY if it comes next.
+func (p *wktParser) empty() bool {
+	p.skipSpace()
+	save := p.pos
+	if p.keyword() == "EMPTY" {
+		return true
+	}
+	p.pos = save
+	return false
+}
+
+// expect consumes the character c, which may be preceded by whitespace.
+func (p *wktParser) expect(c byte) error {
+	p.skipSpace()
+	if p.pos == len(p.s) || p.s[p.pos] != c {
+		return p.errorf("expected '%c'", c)
+	}
+	p.pos++
+	return nil
+}
+
+// list parses EMPTY or a parenthesized, comma-separated list of elements.
+// It returns nil for EMPTY without calling elem.
+func (p *wktParser) list(elem func() error) error {
+	if p.empty() {
+		return nil
+	}
+	if err := p.expect('('); err != nil {
+		return err
+	}
+	for {
+		if err := elem(); err != nil {
+			return err
+		}
+		p.skipSpace()
+		if p.pos < len(p.s) && p.s[p.pos] == ',' {
+			p.pos++
+			continue
+		}
+		return p.expect(')')
+	}
+}
+
+// points parses a list of coordinates.
+func (p *wktParser) points(dims int) ([]Point, error) {
+	var pts []Point
+	err := p.list(func() error {
+		pt, err := p.coord(dims)
+		pts = append(pts, pt)
+		return err
+	})
+	return pts, err
+}
+
+// polygon parses a list of rings.
+func (p *wktParser) polygon(dims int) (Polygon, error) {
+	var poly Polygon
+	first := true
+	err := p.list(func() error {
+		p.skipSpace()
+		start := p.pos
+		pts, err := p.points(dims)
+		if err != nil {
+			return err
+		}
+		r, msg := openRing(pts)
+		if msg != "" {
+			p.pos = start
+			return p.errorf("%s", msg)
+		}
+		if first {
+			poly.Outer, first = r, false
+		} else {
+			poly.Holes = append(poly.Holes, r)
+		}
+		return nil
+	})
+	return poly, err
+}
+
+// coord parses a coordinate of dims values, or of two to four values if dims is 0,
+// and keeps X and Y.
+func (p *wktParser) coord(dims int) (Point, error) {
+	var v [4]float64
+	n := 0
+	for n < 4 {
+		p.skipSpace()
+		if n >= 2 && (!p.atNumber() || dims != 0 && n == dims) {
+			break
+		}
+		x, err := p.number()
+		if err != nil {
+			return Point{}, err
+		}
+		if math.Abs(x) > math.MaxFloat32 {
+			return Point{}, p.errorf("number out of range")
+		}
+		v[n] = x
+		n++
+	}
+	if dims != 0 && n != dims {
+		return Point{}, p.errorf("expected %d coordinate values", dims)
+	}
+	return Point{X: float32(v[0]), Y: float32(v[1])}, nil
+}
+
+// FormatWKT formats a geometry as two-dimensional Well-Known Text, which ParseWKT reads
+// back. Coordinates are written with the fewest digits that represent them exactly, and
+// polygon rings are closed by repeating their first vertex. Empty geometries, including
+// EmptyPoint, are written with the keyword EMPTY, and a nil Geometry is written as an
+// empty geometry collection.
+func FormatWKT(g Geometry) string {
+	return string(appendWKT(nil, g, true))
+}
+
+// appendWKT appends the text of g to dst, preceded by its type name if tagged.
+func appendWKT(dst []byte, g Geometry, tagged bool) []byte {
+	if g == nil {
+		g = GeometryCollection(nil)
+	}
+	if tagged {
+		dst = append(dst, g.geometryType()...)
+		dst = append(dst, ' ')
+	}
+	switch g := g.(type) {
+	case Point:
+		if isEmptyPoint(g) {
+			return append(dst, "EMPTY"...)
+		}
+		return append(appendWKTCoord(append(dst, '('), g), ')')
+	case LineString:
+		return appendWKTPoints(dst, g)
+	case Polygon:
+		return appendWKTPolygon(dst, g)
+	case MultiPoint:
+		return appendWKTList(dst, len(g), func(dst []byte, k int) []byte {
+			return appendWKT(dst, g[k], false)
+		})
+	case MultiLineString:
+		return appendWKTList(dst, len(g), func(dst []byte, k int) []byte {
+			return appendWKTPoints(dst, g[k])
+		})
+	case MultiPolygon:
+		return appendWKTList(dst, len(g), func(dst []byte, k int) []byte {
+			return appendWKTPolygon(dst, g[k])
+		})
+	case GeometryCollection:
+		return appendWKTList(dst, len(g), func(dst []byte, k int) []byte {
+			return appendWKT(dst, g[k], true)
+		})
+	}
+	return dst
+}
+
+// appendWKTList appends EMPTY for n == 0, or the n elements written by elem in parentheses.
+func appendWKTList(dst []byte, n int, elem func(dst []byte, k int) []byte) []byte {
+	if n == 0 {
+		return append(dst, "EMPTY"...)
+	}
+	dst = append(dst, '(')
+	for k := range n {
+		if k > 0 {
+			dst = append(dst, ", "...)
+		}
+		dst = elem(dst, k)
+	}
+	return append(dst, ')')
+}
+
+// appendWKTPoints appends a list of coordinates.
+func appendWKTPoints(dst []byte, pts []Point) []byte {
+	return appendWKTList(dst, len(pts), func(dst []byte, k int) []byte {
+		return appendWKTCoord(dst, pts[k])
+	})
+}
+
+// appendWKTPolygon appends the closed rings of a polygon.
+func appendWKTPolygon(dst []byte, poly Polygon) []byte {
+	if len(poly.Outer) == 0 && len(poly.Holes) == 0 {
+		return append(dst, "EMPTY"...)
+	}
+	return appendWKTList(dst, 1+len(poly.Holes), func(dst []byte, k int) []byte {
+		r := poly.Outer
+		if k > 0 {
+			r = poly.Holes[k-1]
+		}
+		return appendWKTPoints(dst, closeRing(r))
+	})
+}
+
+// appendWKTCoord appends the X and Y values of a coordinate.
+func appendWKTCoord(dst []byte, p Point) []byte {
+	dst = strconv.AppendFloat(dst, float64(p.X), 'f', -1, 32)
+	dst = append(dst, ' ')
+	return strconv.AppendFloat(dst, float64(p.Y), 'f', -1, 32)
+}
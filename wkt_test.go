@@ -0,0 +1,114 @@
+package tochka
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// TestParseWKT tests every geometry type, dimension tags and EMPTY.
+func TestParseWKT(t *testing.T) {
+	tri := Ring{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 0, Y: 4}}
+	hole := Ring{{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 1, Y: 2}}
+	tests := []struct {
+		s    string
+		want Geometry
+	}{
+		{"POINT (1 2)", Point{X: 1, Y: 2}},
+		{"point(1.5 -2e1)", Point{X: 1.5, Y: -20}},
+		{"POINT Z (1 2 3)", Point{X: 1, Y: 2}},
+		{"POINTM(1 2 3)", Point{X: 1, Y: 2}},
+		{"POINT ZM (1 2 3 4)", Point{X: 1, Y: 2}},
+		{"POINT (1 2 3 4)", Point{X: 1, Y: 2}},
+		{"LINESTRING (0 0, 1 1, 2 0)", LineString{{X: 0, Y: 0}, {X: 1, Y: 1}, {X: 2, Y: 0}}},
+		{"LINESTRING EMPTY", LineString(nil)},
+		{"POLYGON ((0 0, 4 0, 0 4, 0 0), (1 1, 2 1, 1 2, 1 1))", NewPolygon(tri, hole)},
+		{"POLYGON Z ((0 0 1, 4 0 1, 0 4 1, 0 0 1))", NewPolygon(tri)},
+		{"POLYGON EMPTY", Polygon{}},
+		{"MULTIPOINT ((1 2), (3 4))", MultiPoint{{X: 1, Y: 2}, {X: 3, Y: 4}}},
+		{"MULTIPOINT (1 2, 3 4)", MultiPoint{{X: 1, Y: 2}, {X: 3, Y: 4}}},
+		{"MULTILINESTRING ((0 0, 1 1), EMPTY)", MultiLineString{{{X: 0, Y: 0}, {X: 1, Y: 1}}, nil}},
+		{"MULTIPOLYGON (((0 0, 4 0, 0 4, 0 0)), EMPTY)", MultiPolygon{NewPolygon(tri), {}}},
+		{"GEOMETRYCOLLECTION (POINT (1 2), LINESTRING Z (0 0 0, 1 1 1))",
+			GeometryCollection{Point{X: 1, Y: 2}, LineString{{X: 0, Y: 0}, {X: 1, Y: 1}}}},
+		{" GEOMETRYCOLLECTION EMPTY ", GeometryCollection(nil)},
+	}
+	for _, tt := range tests {
+		got, err := ParseWKT(tt.s)
+		if err != nil {
+			t.Errorf("ParseWKT(%q) error = %v", tt.s, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseWKT(%q) = %#v; want %#v", tt.s, got, tt.want)
+		}
+	}
+	g, err := ParseWKT("POINT EMPTY")
+	if p, ok := g.(Point); err != nil || !ok || !isEmptyPoint(p) {
+		t.Errorf("ParseWKT(POINT EMPTY) = %v, %v; want EmptyPoint", g, err)
+	}
+}
+
+// TestParseWKT_Errors tests that malformed text is reported at the right offset.
+func TestParseWKT_Errors(t *testing.T) {
+	tests := []struct {
+		s      string
+		offset int
+	}{
+		{"", 0},
+		{"CIRCLE (1 2)", 0},
+		{"POINT", 5},
+		{"POINT (1)", 8},
+		{"POINT (1 2", 10},
+		{"POINT Z (1 2)", 12},
+		{"POINT (1 2 3 4 5)", 15},
+		{"POINT (1 2) x", 12},
+		{"LINESTRING (0 0,)", 16},
+		{"POLYGON ((0 0, 1 0, 0 1, 0 0.5))", 9},
+		{"POLYGON ((0 0, 1 0, 0 0))", 9},
+		{"POINT (1e39 0)", 11},
+		{"GEOMETRYCOLLECTION (POINT (1 2), BOX (0 0))", 33},
+	}
+	for _, tt := range tests {
+		_, err := ParseWKT(tt.s)
+		var perr *ParseError
+		if !errors.As(err, &perr) {
+			t.Errorf("ParseWKT(%q) error = %v; want a *ParseError", tt.s, err)
+			continue
+		}
+		if perr.Offset != tt.offset {
+			t.Errorf("ParseWKT(%q) error = %v; want offset %d", tt.s, err, tt.offset)
+		}
+	}
+}
+
+// TestFormatWKT tests the text of every geometry type and its round trip through ParseWKT.
+func TestFormatWKT(t *testing.T) {
+	sq := NewPolygon(square)
+	tests := []struct {
+		g    Geometry
+		want string
+	}{
+		{Point{X: 1.5, Y: -0.25}, "POINT (1.5 -0.25)"},
+		{EmptyPoint(), "POINT EMPTY"},
+		{LineString{{X: 0, Y: 0}, {X: 1e6, Y: 0.1}}, "LINESTRING (0 0, 1000000 0.1)"},
+		{LineString{}, "LINESTRING EMPTY"},
+		{sq, "POLYGON ((0 0, 4 0, 4 4, 0 4, 0 0))"},
+		{Polygon{}, "POLYGON EMPTY"},
+		{MultiPoint{{X: 1, Y: 2}, EmptyPoint()}, "MULTIPOINT ((1 2), EMPTY)"},
+		{MultiLineString{{{X: 0, Y: 0}, {X: 1, Y: 1}}}, "MULTILINESTRING ((0 0, 1 1))"},
+		{MultiPolygon{sq}, "MULTIPOLYGON (((0 0, 4 0, 4 4, 0 4, 0 0)))"},
+		{GeometryCollection{Point{X: 1, Y: 2}, nil}, "GEOMETRYCOLLECTION (POINT (1 2), GEOMETRYCOLLECTION EMPTY)"},
+		{nil, "GEOMETRYCOLLECTION EMPTY"},
+	}
+	for _, tt := range tests {
+		got := FormatWKT(tt.g)
+		if got != tt.want {
+			t.Errorf("FormatWKT(%#v) = %q; want %q", tt.g, got, tt.want)
+			continue
+		}
+		if _, err := ParseWKT(got); err != nil {
+			t.Errorf("ParseWKT(%q) error = %v", got, err)
+		}
+	}
+}